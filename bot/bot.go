@@ -1,28 +1,67 @@
 package bot
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"github.com/dmalyar/pimpmyvocab/domain"
+	"github.com/dmalyar/pimpmyvocab/export"
 	"github.com/dmalyar/pimpmyvocab/log"
 	"github.com/dmalyar/pimpmyvocab/service"
 	"github.com/go-telegram-bot-api/telegram-bot-api"
+	"io"
+	"io/ioutil"
+	"math"
+	"net/http"
+	"path/filepath"
 	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 )
 
 type Bot struct {
-	logger       log.Logger
-	api          *tgbotapi.BotAPI
-	vocabService service.Vocab
+	logger           log.Logger
+	api              *tgbotapi.BotAPI
+	vocabService     service.Vocab
+	schedulerService service.Scheduler
+	quizService      service.Quiz
+	statsService     service.Stats
+	reminderService  service.Reminder
+	langService      service.Lang
+	extractorService service.Extractor
+	states           *chatStates
+	searchQueries    *searchQueries
+	extractions      *extractions
+	choiceQuestions  *choiceQuestions
 }
 
-func New(logger log.Logger, api *tgbotapi.BotAPI, vocabService service.Vocab) *Bot {
+func New(
+	logger log.Logger,
+	api *tgbotapi.BotAPI,
+	vocabService service.Vocab,
+	schedulerService service.Scheduler,
+	quizService service.Quiz,
+	statsService service.Stats,
+	reminderService service.Reminder,
+	langService service.Lang,
+	extractorService service.Extractor,
+) *Bot {
 	return &Bot{
-		logger:       logger,
-		api:          api,
-		vocabService: vocabService,
+		logger:           logger,
+		api:              api,
+		vocabService:     vocabService,
+		schedulerService: schedulerService,
+		quizService:      quizService,
+		statsService:     statsService,
+		reminderService:  reminderService,
+		langService:      langService,
+		extractorService: extractorService,
+		states:           newChatStates(),
+		searchQueries:    newSearchQueries(),
+		extractions:      newExtractions(),
+		choiceQuestions:  newChoiceQuestions(),
 	}
 }
 
@@ -39,6 +78,7 @@ func (b *Bot) Run() {
 	}
 	b.logger.Info("Successfully got updates channel and start processing messages")
 	b.processOfflineUpdates(updates)
+	go b.runReminders()
 	for update := range updates {
 		if update.Message != nil {
 			go b.processMessage(update.Message)
@@ -101,10 +141,15 @@ func (b *Bot) processMessage(in *tgbotapi.Message) {
 		userID:   in.From.ID,
 		userName: in.From.UserName,
 		text:     in.Text,
+		document: in.Document,
 	}
 	logger := b.logger.WithField("message", msg)
 	defer logProcessingTime(logger, time.Now())
 	text := strings.ToLower(msg.text)
+	state := b.states.get(msg.chatID)
+	if strings.HasPrefix(text, "/") {
+		b.states.reset(msg.chatID)
+	}
 	switch {
 	case text == startCommand:
 		b.processStartCommand(logger, msg)
@@ -118,10 +163,41 @@ func (b *Bot) processMessage(in *tgbotapi.Message) {
 		b.processRepeatCommand(logger, msg)
 	case text == quizCommand:
 		b.processQuizCommand(logger, msg)
+	case text == typeCommand:
+		b.processTypeCommand(logger, msg)
+	case text == choiceCommand:
+		b.processChoiceCommand(logger, msg)
+	case text == statsCommand:
+		b.processStatsCommand(logger, msg)
+	case text == remindCommand || strings.HasPrefix(text, remindCommand+" "):
+		b.processRemindCommand(logger, msg)
+	case text == goalCommand || strings.HasPrefix(text, goalCommand+" "):
+		b.processGoalCommand(logger, msg)
+	case text == langCommand || strings.HasPrefix(text, langCommand+" "):
+		b.processLangCommand(logger, msg)
+	case text == findCommand || strings.HasPrefix(text, findCommand+" "):
+		b.processFindCommand(logger, msg)
+	case text == extractCommand || strings.HasPrefix(text, extractCommand+" "):
+		b.processExtractCommand(logger, msg)
+	case text == exportCommand:
+		b.processExportCommand(logger, msg)
+	case text == decksCommand:
+		b.processDecksCommand(logger, msg)
+	case text == newDeckCommand || strings.HasPrefix(text, newDeckCommand+" "):
+		b.processNewDeckCommand(logger, msg)
+	case text == useDeckCommand || strings.HasPrefix(text, useDeckCommand+" "):
+		b.processUseDeckCommand(logger, msg)
 	case strings.HasPrefix(text, "/"):
 		logger.Info("Received unsupported command")
+	case msg.document != nil:
+		b.processDocument(logger, msg)
 	case text == "":
 		logger.Info("Received msg with no text")
+	case state.mode == typeQuizMode:
+		b.processTypedAnswer(logger, msg, state)
+	case state.mode == extractMode:
+		b.states.reset(msg.chatID)
+		b.processExtractText(logger, msg, msg.text)
 	default:
 		b.processText(logger, msg)
 	}
@@ -136,6 +212,9 @@ func (b *Bot) processCallback(in *tgbotapi.CallbackQuery) {
 		userName: in.From.UserName,
 		data:     new(CallbackData),
 	}
+	if in.Message.ReplyToMessage != nil {
+		callbackMsg.quotedText = in.Message.ReplyToMessage.Text
+	}
 	logger := b.logger.WithField("callbackMessage", callbackMsg)
 	defer logProcessingTime(logger, time.Now())
 	defer b.answerCallback(logger, callbackMsg.id)
@@ -162,10 +241,62 @@ func (b *Bot) processCallback(in *tgbotapi.CallbackQuery) {
 		b.processClearVocabAnswerCommand(logger, callbackMsg, false)
 	case repeatCallbackCmd:
 		b.processRepeatCallbackCommand(logger, callbackMsg)
-	case continueQuizCallbackCmd:
+	case startQuizCallbackCmd:
+		b.processStartQuizCommand(logger, callbackMsg)
+	case choiceAnswerCallbackCmd:
+		b.processChoiceAnswerCommand(logger, callbackMsg)
+	case nextChoiceCallbackCmd:
+		b.processNextChoiceCommand(logger, callbackMsg)
+	case continueQuizCallbackCmd, continueQuizAfterAnswerCallbackCmd:
 		b.processContinueQuizCommand(logger, callbackMsg)
+	case stopQuizCallbackCmd:
+		b.processStopQuizCommand(logger, callbackMsg)
+	case startReminderQuizCallbackCmd:
+		b.processStartReminderQuizCommand(logger, callbackMsg)
+	case setLangCallbackCmd:
+		b.processSetLangCommand(logger, callbackMsg)
+	case addCandidateCallbackCmd:
+		b.processAddCandidateCommand(logger, callbackMsg)
+	case listPageCallbackCmd:
+		b.processEntriesPageCommand(logger, callbackMsg, false)
+	case listEntryCallbackCmd:
+		b.processEntryCardCommand(logger, callbackMsg, false)
+	case rmFromListCallbackCmd:
+		b.processRemoveFromPageCommand(logger, callbackMsg, false)
+	case findPageCallbackCmd:
+		b.processEntriesPageCommand(logger, callbackMsg, true)
+	case findEntryCallbackCmd:
+		b.processEntryCardCommand(logger, callbackMsg, true)
+	case rmFromFindCallbackCmd:
+		b.processRemoveFromPageCommand(logger, callbackMsg, true)
+	case toggleListedWordCallbackCmd:
+		b.processToggleListedWordCommand(logger, callbackMsg)
+	case addAllListedCallbackCmd:
+		b.processAddAllListedCommand(logger, callbackMsg)
+	case extractToggleCallbackCmd:
+		b.processExtractToggleCommand(logger, callbackMsg)
+	case extractPageCallbackCmd:
+		b.processExtractPageCommand(logger, callbackMsg)
+	case extractAddCallbackCmd:
+		b.processExtractAddCommand(logger, callbackMsg)
+	case exportCallbackCmd:
+		b.processExportFormatCommand(logger, callbackMsg)
 	case showAnswerCallbackCmd:
 		b.processShowAnswerCommand(logger, callbackMsg)
+	case gradeAgainCallbackCmd:
+		b.processGradeCommand(logger, callbackMsg, domain.GradeAgain)
+	case gradeHardCallbackCmd:
+		b.processGradeCommand(logger, callbackMsg, domain.GradeHard)
+	case gradeGoodCallbackCmd:
+		b.processGradeCommand(logger, callbackMsg, domain.GradeGood)
+	case gradeEasyCallbackCmd:
+		b.processGradeCommand(logger, callbackMsg, domain.GradeEasy)
+	case noopCallbackCmd:
+		logger.Debug("Received noop callback")
+	case typeQuizGiveUpCallbackCmd:
+		b.processTypeQuizGiveUpCommand(logger, callbackMsg)
+	case typeQuizStopCallbackCmd:
+		b.processTypeQuizStopCommand(logger, callbackMsg)
 	default:
 		logger.Info("Received unsupported callback")
 	}
@@ -180,42 +311,92 @@ func (b *Bot) processStartCommand(logger log.Logger, msg *message) {
 		return
 	}
 	if vocab != nil {
-		b.send(logger, newReply(msg.chatID, helpReply))
+		b.send(logger, newReply(msg.chatID, fmt.Sprintf(helpReply, domain.LearnedCorrectStreak, quizSessionSize)))
 	}
 	logger.Info("Processed /start command")
 }
 
 func (b *Bot) processHelpCommand(logger log.Logger, msg *message) {
 	logger.Info("Received /help command")
-	b.send(logger, newReply(msg.chatID, helpReply))
+	b.send(logger, newReply(msg.chatID, fmt.Sprintf(helpReply, domain.LearnedCorrectStreak, quizSessionSize)))
 	logger.Info("Processed /help command")
 }
 
 func (b *Bot) processListCommand(logger log.Logger, msg *message) {
 	logger.Info("Received /list command")
-	entries, err := b.vocabService.GetEntriesFromUserVocab(msg.userID)
+	page, err := b.vocabService.GetEntriesPageFromUserVocab(msg.userID, 0, listPageSize)
 	if err != nil {
-		logger.Errorf("Error getting entries: %s", err)
+		logger.Errorf("Error getting page of entries: %s", err)
 		b.send(logger, newReply(msg.chatID, techErrReply))
 		return
 	}
-	if len(entries) == 0 {
+	if page.Total == 0 {
 		logger.Info("Processed /list command (no entries)")
 		b.send(logger, newReply(msg.chatID, emptyVocabReply))
 		return
 	}
-	b.send(logger, newReply(msg.chatID, createListReply(entries)))
+	b.send(logger, newReply(msg.chatID, createListReply(page)).withEntriesPageKeyboard(logger, page, listCallbacks, 0))
 	logger.Info("Processed /list command")
 }
 
-func createListReply(entries []*domain.VocabEntry) string {
+func (b *Bot) processFindCommand(logger log.Logger, msg *message) {
+	logger.Info("Received /find command")
+	query := strings.Join(strings.Fields(msg.text[len(findCommand):]), " ")
+	if query == "" || len([]rune(query)) > maxFindQueryLen {
+		logger.Info("Processed /find command (invalid query)")
+		b.send(logger, newReply(msg.chatID, findUsageReply).withQuote(msg.id))
+		return
+	}
+	page, err := b.vocabService.FindEntriesInUserVocab(msg.userID, query, 0, listPageSize)
+	if err != nil {
+		logger.Errorf("Error finding entries: %s", err)
+		b.send(logger, newReply(msg.chatID, techErrReply))
+		return
+	}
+	if page.Total == 0 {
+		logger.Info("Processed /find command (nothing found)")
+		b.send(logger, newReply(msg.chatID, fmt.Sprintf(nothingFoundReply, query)))
+		return
+	}
+	ref := b.searchQueries.add(msg.chatID, query)
+	b.send(
+		logger,
+		newReply(msg.chatID, createFindReply(query, page)).withEntriesPageKeyboard(logger, page, findCallbacks, ref),
+	)
+	logger.Info("Processed /find command")
+}
+
+// createFindReply returns the found entries of the page in the order they were found.
+func createFindReply(query string, page *domain.EntriesPage) string {
 	builder := new(strings.Builder)
-	sort.Slice(entries, func(i, j int) bool {
-		return entries[i].Text < entries[j].Text
-	})
-	for _, entry := range entries {
+	builder.WriteString(fmt.Sprintf(findPageReply, query, page.Total, page.Number+1, page.PagesQnt()))
+	for _, entry := range page.Entries {
 		builder.WriteString(fmt.Sprintf("%s – %s\n", entry.Text, entry.MainTranslation))
 	}
+	builder.WriteString(listHintReply)
+	return builder.String()
+}
+
+// createListReply returns the page entries grouped by mastery level.
+// Entries of the page are expected to be already ordered by mastery level.
+func createListReply(page *domain.EntriesPage) string {
+	builder := new(strings.Builder)
+	builder.WriteString(fmt.Sprintf(listPageReply, page.Total, page.Number+1, page.PagesQnt()))
+	byLevel := make(map[domain.MasteryLevel][]*domain.VocabEntry)
+	for _, entry := range page.Entries {
+		byLevel[entry.Mastery] = append(byLevel[entry.Mastery], entry)
+	}
+	for _, level := range domain.MasteryLevels {
+		if len(byLevel[level]) == 0 {
+			continue
+		}
+		builder.WriteString(fmt.Sprintf("%s:\n", masteryLevelHeaders[level]))
+		for _, entry := range byLevel[level] {
+			builder.WriteString(fmt.Sprintf("%s – %s\n", entry.Text, entry.MainTranslation))
+		}
+		builder.WriteString("\n")
+	}
+	builder.WriteString(listHintReply)
 	return builder.String()
 }
 
@@ -225,11 +406,89 @@ func (b *Bot) processClearCommand(logger log.Logger, msg *message) {
 	logger.Info("Processed /clear command")
 }
 
+func (b *Bot) processDecksCommand(logger log.Logger, msg *message) {
+	logger.Info("Received /decks command")
+	vocabs, err := b.vocabService.GetUserVocabs(msg.userID)
+	if err != nil {
+		logger.Errorf("Error getting vocabs: %s", err)
+		b.send(logger, newReply(msg.chatID, techErrReply))
+		return
+	}
+	builder := new(strings.Builder)
+	if len(vocabs) != 0 {
+		builder.WriteString(decksReply)
+	}
+	for _, v := range vocabs {
+		builder.WriteString("• " + v.Name)
+		if v.Active {
+			builder.WriteString(activeDeckMark)
+		}
+		builder.WriteString("\n")
+	}
+	builder.WriteString(decksUsageReply)
+	b.send(logger, newReply(msg.chatID, builder.String()))
+	logger.Info("Processed /decks command")
+}
+
+func (b *Bot) processNewDeckCommand(logger log.Logger, msg *message) {
+	logger.Info("Received /newdeck command")
+	name, ok := parseDeckName(msg.text, newDeckCommand)
+	if !ok {
+		logger.Info("Processed /newdeck command (invalid name)")
+		b.send(logger, newReply(msg.chatID, fmt.Sprintf(deckNameUsageReply, maxDeckNameLen, newDeckCommand)).withQuote(msg.id))
+		return
+	}
+	vocab, err := b.vocabService.AddUserVocab(msg.userID, name)
+	if err != nil {
+		logger.Errorf("Error adding vocab: %s", err)
+		b.send(logger, newReply(msg.chatID, techErrReply))
+		return
+	}
+	if vocab == nil {
+		logger.Info("Processed /newdeck command (vocab exists)")
+		b.send(logger, newReply(msg.chatID, fmt.Sprintf(deckExistsReply, name, name)))
+		return
+	}
+	b.send(logger, newReply(msg.chatID, fmt.Sprintf(deckCreatedReply, vocab.Name)))
+	logger.Info("Processed /newdeck command")
+}
+
+func (b *Bot) processUseDeckCommand(logger log.Logger, msg *message) {
+	logger.Info("Received /usedeck command")
+	name, ok := parseDeckName(msg.text, useDeckCommand)
+	if !ok {
+		logger.Info("Processed /usedeck command (invalid name)")
+		b.send(logger, newReply(msg.chatID, fmt.Sprintf(deckNameUsageReply, maxDeckNameLen, useDeckCommand)).withQuote(msg.id))
+		return
+	}
+	vocab, err := b.vocabService.UseUserVocab(msg.userID, name)
+	if err != nil {
+		logger.Errorf("Error switching vocab: %s", err)
+		b.send(logger, newReply(msg.chatID, techErrReply))
+		return
+	}
+	if vocab == nil {
+		logger.Info("Processed /usedeck command (vocab not found)")
+		b.send(logger, newReply(msg.chatID, fmt.Sprintf(deckNotFoundReply, name)))
+		return
+	}
+	b.send(logger, newReply(msg.chatID, fmt.Sprintf(deckSwitchedReply, vocab.Name)))
+	logger.Info("Processed /usedeck command")
+}
+
+// parseDeckName returns the deck name following the command keeping its case.
+// Returns false if the name is empty or too long.
+func parseDeckName(text, command string) (string, bool) {
+	name := strings.Join(strings.Fields(text[len(command):]), " ")
+	length := len([]rune(name))
+	return name, length > 0 && length <= maxDeckNameLen
+}
+
 func (b *Bot) processRepeatCommand(logger log.Logger, msg *message) {
 	logger.Info("Received /repeat command")
-	entry, err := b.vocabService.GetRandomEntryFromUserVocab(msg.userID, -1)
+	entry, err := b.schedulerService.GetNextEntryToReview(msg.userID, -1, domain.DirectionForward)
 	if err != nil {
-		logger.Errorf("Error getting random entry: %s", err)
+		logger.Errorf("Error getting next entry to review: %s", err)
 		b.send(logger, newReply(msg.chatID, techErrReply))
 		return
 	}
@@ -244,190 +503,1511 @@ func (b *Bot) processRepeatCommand(logger log.Logger, msg *message) {
 
 func (b *Bot) processQuizCommand(logger log.Logger, msg *message) {
 	logger.Info("Received /quiz command")
-	entry, err := b.vocabService.GetRandomEntryFromUserVocab(msg.userID, -1)
-	if err != nil {
-		logger.Errorf("Error getting random entry: %s", err)
-		b.send(logger, newReply(msg.chatID, techErrReply))
-		return
-	}
-	if entry == nil {
-		logger.Info("Processed /quiz command (no entries)")
-		b.send(logger, newReply(msg.chatID, emptyVocabReply))
-		return
-	}
-	b.send(logger, newReply(msg.chatID, entry.Text).withQuizKeyboard(logger, entry.ID))
+	b.send(logger, newReply(msg.chatID, quizDirectionReply).withQuizDirectionKeyboard(logger))
 	logger.Info("Processed /quiz command")
 }
 
-func (b *Bot) processText(logger log.Logger, msg *message) {
-	logger.Info("Received text")
-	entry, err := b.vocabService.GetVocabEntryByText(strings.ToLower(msg.text))
+func (b *Bot) processTypeCommand(logger log.Logger, msg *message) {
+	logger.Info("Received /type command")
+	b.sendNextTypeQuizEntry(logger, msg.chatID, msg.userID, -1)
+	logger.Info("Processed /type command")
+}
+
+func (b *Bot) processTypedAnswer(logger log.Logger, msg *message, state chatState) {
+	logger = logger.WithField("chatState", &state)
+	logger.Info("Received typed answer")
+	if !b.states.take(msg.chatID, state) {
+		logger.Info("Processed typed answer (question is already answered)")
+		return
+	}
+	entry, err := b.vocabService.GetVocabEntryByID(state.entryID)
 	if err != nil {
 		logger.Errorf("Error getting vocab entry: %s", err)
 		b.send(logger, newReply(msg.chatID, techErrReply))
 		return
 	}
 	if entry == nil {
-		logger.Info("Text processed (not found)")
-		b.send(logger, newReply(msg.chatID, wordNotFoundReply).withQuote(msg.id))
+		logger.Errorf("Vocab entry not found")
+		b.send(logger, newReply(msg.chatID, techErrReply))
 		return
 	}
-	inVocab, err := b.vocabService.CheckEntryInUserVocab(entry.ID, msg.userID)
+	var grade domain.Grade
+	var reply string
+	switch entry.MatchTranslation(msg.text) {
+	case domain.AnswerExact:
+		grade, reply = domain.GradeGood, correctAnswerReply
+	case domain.AnswerClose:
+		grade, reply = domain.GradeHard, closeAnswerReply
+	default:
+		grade, reply = domain.GradeAgain, wrongAnswerReply
+	}
+	err = b.schedulerService.ReviewEntry(entry.ID, msg.userID, domain.DirectionForward, grade)
 	if err != nil {
-		logger.Errorf("Error checking if entry is in the user's vocab: %s", err)
+		logger.Errorf("Error reviewing entry: %s", err)
 		b.send(logger, newReply(msg.chatID, techErrReply))
 		return
 	}
-	b.send(
-		logger,
-		newReply(msg.chatID, entry.ShortDesc()).withQuote(msg.id).withShortDescKeyboard(logger, entry.ID, inVocab),
-	)
-	logger.Info("Text processed")
+	b.send(logger, newReply(msg.chatID, reply+"\n\n"+entry.FullDesc(true)).withQuote(msg.id))
+	b.checkDailyGoal(logger, msg.chatID, msg.userID)
+	b.sendNextTypeQuizEntry(logger, msg.chatID, msg.userID, entry.ID)
+	logger.Infof("Processed typed answer (%s)", grade)
 }
 
-func (b *Bot) processShowFullDescCommand(logger log.Logger, callbackMsg *callbackMessage) {
-	logger.Info("Received show full description callback command")
-	entry, err := b.vocabService.GetVocabEntryByID(callbackMsg.data.EntryID)
+func (b *Bot) sendNextTypeQuizEntry(logger log.Logger, chatID int64, userID, previousEntryID int) {
+	entry, err := b.schedulerService.GetNextEntryToReview(userID, previousEntryID, domain.DirectionForward)
 	if err != nil {
-		logger.Errorf("Error getting vocab entry: %s", err)
-		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		logger.Errorf("Error getting next entry to review: %s", err)
+		b.states.reset(chatID)
+		b.send(logger, newReply(chatID, techErrReply))
+		return
 	}
 	if entry == nil {
-		logger.Errorf("Vocab entry not found")
-		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		logger.Info("User's vocab is empty")
+		b.states.reset(chatID)
+		b.send(logger, newReply(chatID, emptyVocabReply))
 		return
 	}
-	logger.WithField("vocabEntry", entry)
-	inVocab, err := b.vocabService.CheckEntryInUserVocab(entry.ID, callbackMsg.userID)
+	b.states.set(chatID, chatState{mode: typeQuizMode, entryID: entry.ID})
+	b.send(logger, newReply(chatID, typeQuizPrompt+entry.Text).withTypeQuizKeyboard(logger, entry.ID))
+}
+
+func (b *Bot) processChoiceCommand(logger log.Logger, msg *message) {
+	logger.Info("Received /choice command")
+	b.sendNextChoiceQuestion(logger, msg.chatID, msg.userID, -1)
+	logger.Info("Processed /choice command")
+}
+
+func (b *Bot) sendNextChoiceQuestion(logger log.Logger, chatID int64, userID, previousEntryID int) {
+	question, err := b.quizService.GetChoiceQuestion(userID, previousEntryID, choiceOptionsQnt)
 	if err != nil {
-		logger.Errorf("Error checking if entry is in the user's vocab: %s", err)
-		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		logger.Errorf("Error getting choice question: %s", err)
+		b.send(logger, newReply(chatID, techErrReply))
 		return
 	}
-	b.send(
-		logger,
-		newEditText(callbackMsg.chatID, callbackMsg.msgID, entry.FullDesc(false)).withFullDescKeyboard(logger, entry.ID, inVocab),
-	)
-	logger.Info("Processed show full description callback command")
+	if question == nil {
+		logger.Info("User's vocab is empty")
+		b.send(logger, newReply(chatID, emptyVocabReply))
+		return
+	}
+	if len(question.Options) < 2 {
+		logger.Info("Not enough entries in the user's vocab for choice question")
+		b.send(logger, newReply(chatID, notEnoughEntriesForChoiceReply))
+		return
+	}
+	b.choiceQuestions.set(chatID, question.Entry.ID)
+	b.send(logger, newReply(chatID, question.Entry.Text).withChoiceKeyboard(logger, question))
 }
 
-func (b *Bot) processAddToVocabCommand(logger log.Logger, callbackMsg *callbackMessage, fullDescShown bool) {
-	logger.Info("Received add to vocab callback command")
-	callbackData := callbackMsg.data
-	err := b.vocabService.AddEntryToUserVocab(callbackData.EntryID, callbackMsg.userID)
+func (b *Bot) processStatsCommand(logger log.Logger, msg *message) {
+	logger.Info("Received /stats command")
+	stats, err := b.statsService.GetStats(msg.userID)
 	if err != nil {
-		logger.Errorf("Error adding entry to vocab: %s", err)
-		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		logger.Errorf("Error getting stats: %s", err)
+		b.send(logger, newReply(msg.chatID, techErrReply))
 		return
 	}
-	if fullDescShown {
-		b.send(
-			logger,
-			newEditKeyboardMsgFullDesc(logger, callbackMsg.chatID, callbackMsg.msgID, callbackData.EntryID, true),
-		)
-	} else {
-		b.send(
-			logger,
-			newEditKeyboardMsgShortDesc(logger, callbackMsg.chatID, callbackMsg.msgID, callbackData.EntryID, true),
-		)
+	b.send(logger, newReply(msg.chatID, fmt.Sprintf(statsReply,
+		stats.TotalEntries, stats.AddedThisWeek,
+		stats.ReviewsToday, stats.ReviewsLastWeek, stats.ReviewsLastMonth,
+		math.Round(stats.Accuracy*100), stats.Streak,
+	)))
+	logger.Info("Processed /stats command")
+}
+
+func (b *Bot) processRemindCommand(logger log.Logger, msg *message) {
+	logger.Info("Received /remind command")
+	args := strings.Fields(msg.text)[1:]
+	var settings *domain.UserSettings
+	var err error
+	switch {
+	case len(args) == 0:
+		settings, err = b.reminderService.GetUserSettings(msg.userID)
+	case len(args) == 1 && strings.ToLower(args[0]) == remindOffArg:
+		settings, err = b.reminderService.SetRemindersEnabled(msg.userID, msg.chatID, false)
+	case len(args) == 1 && strings.ToLower(args[0]) == remindOnArg:
+		settings, err = b.reminderService.SetRemindersEnabled(msg.userID, msg.chatID, true)
+	case len(args) <= 2:
+		remindAt, parseErr := domain.ParseTimeOfDay(args[0])
+		if parseErr != nil {
+			logger.Infof("Processed /remind command (invalid time: %s)", parseErr)
+			b.send(logger, newReply(msg.chatID, remindUsageReply).withQuote(msg.id))
+			return
+		}
+		var timezone string
+		if len(args) == 2 {
+			timezone = args[1]
+			if _, tzErr := time.LoadLocation(timezone); tzErr != nil {
+				logger.Infof("Processed /remind command (invalid timezone: %s)", tzErr)
+				b.send(logger, newReply(msg.chatID, unknownTimezoneReply).withQuote(msg.id))
+				return
+			}
+		} else {
+			settings, err = b.reminderService.GetUserSettings(msg.userID)
+			if err != nil {
+				break
+			}
+			timezone = settings.Timezone
+		}
+		settings, err = b.reminderService.SetReminder(msg.userID, msg.chatID, remindAt, timezone)
+	default:
+		logger.Info("Processed /remind command (invalid arguments)")
+		b.send(logger, newReply(msg.chatID, remindUsageReply).withQuote(msg.id))
+		return
 	}
-	logger.Info("Processed add to vocab callback command")
+	if err != nil {
+		logger.Errorf("Error processing reminder settings: %s", err)
+		b.send(logger, newReply(msg.chatID, techErrReply))
+		return
+	}
+	b.send(logger, newReply(msg.chatID, createRemindReply(settings)))
+	logger.Info("Processed /remind command")
 }
 
-func (b *Bot) processRemoveFromVocabCommand(logger log.Logger, callbackMsg *callbackMessage, fullDescShown bool) {
-	logger.Info("Received remove from vocab callback command")
-	callbackData := callbackMsg.data
-	err := b.vocabService.RemoveEntryFromUserVocab(callbackData.EntryID, callbackMsg.userID)
+func createRemindReply(settings *domain.UserSettings) string {
+	if !settings.RemindersEnabled {
+		return remindersDisabledReply + remindUsageReply
+	}
+	return fmt.Sprintf(remindersEnabledReply, settings.RemindAt, settings.Timezone) + remindUsageReply
+}
+
+func (b *Bot) processGoalCommand(logger log.Logger, msg *message) {
+	logger.Info("Received /goal command")
+	args := strings.Fields(msg.text)[1:]
+	var progress *domain.DailyProgress
+	var err error
+	switch {
+	case len(args) == 0:
+		progress, err = b.statsService.GetDailyProgress(msg.userID)
+	case len(args) == 1 && strings.ToLower(args[0]) == goalOffArg:
+		progress, err = b.statsService.SetDailyGoal(msg.userID, msg.chatID, 0)
+	case len(args) == 1:
+		goal, convErr := strconv.Atoi(args[0])
+		if convErr != nil || goal < 0 || goal > maxDailyGoal {
+			logger.Info("Processed /goal command (invalid goal)")
+			b.send(logger, newReply(msg.chatID, goalUsageReply).withQuote(msg.id))
+			return
+		}
+		progress, err = b.statsService.SetDailyGoal(msg.userID, msg.chatID, goal)
+	default:
+		logger.Info("Processed /goal command (invalid arguments)")
+		b.send(logger, newReply(msg.chatID, goalUsageReply).withQuote(msg.id))
+		return
+	}
 	if err != nil {
-		logger.Errorf("Error removing entry to vocab: %s", err)
-		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		logger.Errorf("Error processing daily goal: %s", err)
+		b.send(logger, newReply(msg.chatID, techErrReply))
 		return
 	}
-	if fullDescShown {
-		b.send(
-			logger,
-			newEditKeyboardMsgFullDesc(logger, callbackMsg.chatID, callbackMsg.msgID, callbackData.EntryID, false),
-		)
+	b.send(logger, newReply(msg.chatID, createGoalReply(progress)))
+	logger.Info("Processed /goal command")
+}
+
+func createGoalReply(progress *domain.DailyProgress) string {
+	builder := new(strings.Builder)
+	if progress.Goal == 0 {
+		builder.WriteString(fmt.Sprintf(noGoalReply, progress.ReviewsToday))
 	} else {
-		b.send(
-			logger,
-			newEditKeyboardMsgShortDesc(logger, callbackMsg.chatID, callbackMsg.msgID, callbackData.EntryID, false),
-		)
+		builder.WriteString(fmt.Sprintf(goalProgressReply, progress.ReviewsToday, progress.Goal))
 	}
-	logger.Info("Processed remove from vocab callback command")
+	builder.WriteString(fmt.Sprintf(streakReply, progress.Streak))
+	builder.WriteString(goalUsageReply)
+	return builder.String()
 }
 
-func (b *Bot) processClearVocabAnswerCommand(logger log.Logger, callbackMsg *callbackMessage, accepted bool) {
-	logger.Infof("Received clear vocab answer callback command (%v)", accepted)
-	if !accepted {
-		b.send(logger, newEditText(callbackMsg.chatID, callbackMsg.msgID, clearVocabDeclinedReply))
-		logger.Info("Processed clear vocab answer callback command")
+func (b *Bot) processLangCommand(logger log.Logger, msg *message) {
+	logger.Info("Received /lang command")
+	args := strings.Fields(msg.text)[1:]
+	switch {
+	case len(args) == 0:
+		langPair, err := b.langService.GetLangPair(msg.userID)
+		if err != nil {
+			logger.Errorf("Error getting language pair: %s", err)
+			b.send(logger, newReply(msg.chatID, techErrReply))
+			return
+		}
+		b.send(logger, newReply(msg.chatID, fmt.Sprintf(langReply, langPairName(langPair))+langUsageReply).
+			withLangKeyboard(logger))
+	case len(args) == 1:
+		langPair, err := domain.ParseLangPair(args[0])
+		if err != nil {
+			logger.Infof("Processed /lang command (%s)", err)
+			b.send(logger, newReply(msg.chatID, unsupportedLangPairReply).withQuote(msg.id))
+			return
+		}
+		err = b.langService.SetLangPair(msg.userID, msg.chatID, langPair)
+		if err != nil {
+			logger.Errorf("Error setting language pair: %s", err)
+			b.send(logger, newReply(msg.chatID, techErrReply))
+			return
+		}
+		b.send(logger, newReply(msg.chatID, fmt.Sprintf(langSetReply, langPairName(langPair))))
+	default:
+		logger.Info("Processed /lang command (invalid arguments)")
+		b.send(logger, newReply(msg.chatID, unsupportedLangPairReply).withQuote(msg.id))
 		return
 	}
-	err := b.vocabService.ClearUserVocab(callbackMsg.userID)
+	logger.Info("Processed /lang command")
+}
+
+func (b *Bot) processSetLangCommand(logger log.Logger, callbackMsg *callbackMessage) {
+	logger.Info("Received set language callback command")
+	langPair, err := domain.ParseLangPair(callbackMsg.data.LangPair)
 	if err != nil {
-		logger.Errorf("Error clearing the user's vocab: %s", err)
+		logger.Errorf("Error parsing language pair: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, unsupportedLangPairReply))
+		return
+	}
+	err = b.langService.SetLangPair(callbackMsg.userID, callbackMsg.chatID, langPair)
+	if err != nil {
+		logger.Errorf("Error setting language pair: %s", err)
 		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
 		return
 	}
-	b.send(logger, newEditText(callbackMsg.chatID, callbackMsg.msgID, clearVocabAcceptedReply))
-	logger.Info("Processed clear vocab answer callback command")
+	b.send(logger, newEditText(callbackMsg.chatID, callbackMsg.msgID, fmt.Sprintf(langSetReply, langPairName(langPair))))
+	logger.Info("Processed set language callback command")
 }
 
-func (b *Bot) processRepeatCallbackCommand(logger log.Logger, callbackMsg *callbackMessage) {
-	logger.Info("Received repeat callback command")
-	entry, err := b.vocabService.GetRandomEntryFromUserVocab(callbackMsg.userID, callbackMsg.data.EntryID)
+// checkDailyGoal congratulates the user once a day when the daily goal is reached.
+// Errors are only logged since the review itself is already processed.
+func (b *Bot) checkDailyGoal(logger log.Logger, chatID int64, userID int) {
+	progress, reached, err := b.statsService.CheckDailyGoalReached(userID)
 	if err != nil {
-		logger.Errorf("Error getting random entry: %s", err)
-		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		logger.Errorf("Error checking daily goal: %s", err)
 		return
 	}
-	if entry == nil {
-		logger.Info("Processed repeat callback command (no entries)")
-		b.send(logger, newReply(callbackMsg.chatID, emptyVocabReply))
+	if !reached {
 		return
 	}
-	b.send(logger, newReply(callbackMsg.chatID, entry.FullDesc(true)).withRepeatKeyboard(logger, entry.ID))
-	logger.Info("Processed repeat callback command")
+	logger.WithField("dailyProgress", progress).Info("Daily goal reached")
+	b.send(logger, newReply(chatID, fmt.Sprintf(dailyGoalReachedReply, progress.Goal, progress.Streak)))
 }
 
-func (b *Bot) processContinueQuizCommand(logger log.Logger, callbackMsg *callbackMessage) {
-	logger.Info("Received continue quiz callback command")
-	entry, err := b.vocabService.GetRandomEntryFromUserVocab(callbackMsg.userID, callbackMsg.data.EntryID)
+// runReminders periodically sends reminders to users who have words to review.
+func (b *Bot) runReminders() {
+	b.logger.Info("Start sending reminders")
+	ticker := time.NewTicker(remindersCheckInterval)
+	defer ticker.Stop()
+	for range ticker.C {
+		b.sendReminders()
+	}
+}
+
+func (b *Bot) sendReminders() {
+	reminders, err := b.reminderService.GetRemindersToSend()
 	if err != nil {
-		logger.Errorf("Error getting random entry: %s", err)
-		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		b.logger.Errorf("Error getting reminders to send: %s", err)
 		return
 	}
-	if entry == nil {
-		logger.Info("Processed continue quiz callback command (no entries)")
-		b.send(logger, newReply(callbackMsg.chatID, emptyVocabReply))
-		return
+	for _, r := range reminders {
+		logger := b.logger.WithField("reminder", r)
+		marked, err := b.reminderService.MarkReminderSent(r)
+		if err != nil {
+			logger.Errorf("Error marking reminder as sent: %s", err)
+			continue
+		}
+		if !marked {
+			continue
+		}
+		msg := newReply(r.ChatID, fmt.Sprintf(reminderReply, r.DueQnt)).withReminderKeyboard(logger)
+		if _, err = b.api.Send(msg); err != nil {
+			logger.Errorf("Error sending reminder: %s", err)
+			continue
+		}
+		logger.Info("Reminder sent")
 	}
-	b.send(logger, newReply(callbackMsg.chatID, entry.Text).withQuizKeyboard(logger, entry.ID))
-	logger.Info("Processed continue quiz callback command")
 }
 
-func (b *Bot) processShowAnswerCommand(logger log.Logger, callbackMsg *callbackMessage) {
-	logger.Info("Received show answer callback command")
-	entry, err := b.vocabService.GetVocabEntryByID(callbackMsg.data.EntryID)
+func (b *Bot) processText(logger log.Logger, msg *message) {
+	logger.Info("Received text")
+	langPair, err := b.langService.GetLangPair(msg.userID)
+	if err != nil {
+		logger.Errorf("Error getting language pair: %s", err)
+		b.send(logger, newReply(msg.chatID, techErrReply))
+		return
+	}
+	if words := domain.SplitWordList(msg.text); len(words) > 1 {
+		b.processWordList(logger, msg, words, langPair)
+		return
+	}
+	text := strings.ToLower(msg.text)
+	if lookupPair := langPair.LookupPair(text); lookupPair != langPair {
+		b.processReverseLookup(logger, msg, text, lookupPair)
+		return
+	}
+	entry, err := b.vocabService.GetVocabEntryByText(text, langPair)
 	if err != nil {
 		logger.Errorf("Error getting vocab entry: %s", err)
-		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		b.send(logger, newReply(msg.chatID, techErrReply))
+		return
 	}
 	if entry == nil {
-		logger.Errorf("Vocab entry not found")
-		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		logger.Info("Text processed (not found)")
+		b.send(logger, newReply(msg.chatID, fmt.Sprintf(wordNotFoundReply, langPairName(langPair))).withQuote(msg.id))
 		return
 	}
-	logger.WithField("vocabEntry", entry)
+	inVocab, err := b.vocabService.CheckEntryInUserVocab(entry.ID, msg.userID)
+	if err != nil {
+		logger.Errorf("Error checking if entry is in the user's vocab: %s", err)
+		b.send(logger, newReply(msg.chatID, techErrReply))
+		return
+	}
+	reply := entry.ShortDesc()
+	if entry.Text != text {
+		// the inflected form was resolved to the headword
+		reply = fmt.Sprintf(lemmaReply, text, entry.Text) + reply
+	}
 	b.send(
 		logger,
-		newEditText(callbackMsg.chatID, callbackMsg.msgID, entry.FullDesc(true)).withQuizKeyboard(logger, entry.ID),
+		newReply(msg.chatID, reply).withQuote(msg.id).withShortDescKeyboard(logger, entry.ID, inVocab),
 	)
-	logger.Info("Processed show answer callback command")
+	logger.Info("Text processed")
 }
 
-func logProcessingTime(logger log.Logger, start time.Time) {
-	logger.Debugf("Processing time: %s", time.Since(start))
+// processWordList looks up each word of the list and replies with a single summary
+// offering to add the found words to the vocab.
+func (b *Bot) processWordList(logger log.Logger, msg *message, words []string, langPair domain.LangPair) {
+	logger.Infof("Received list of %v words", len(words))
+	truncated := len(words) > maxListedWordsQnt
+	if truncated {
+		words = words[:maxListedWordsQnt]
+	}
+	lookups, err := b.lookupWords(msg.userID, words, langPair)
+	if err != nil {
+		logger.Errorf("Error looking up words: %s", err)
+		b.send(logger, newReply(msg.chatID, techErrReply))
+		return
+	}
+	b.send(
+		logger,
+		newReply(msg.chatID, createWordListReply(lookups, truncated, true)).
+			withQuote(msg.id).
+			withWordListKeyboard(logger, lookups),
+	)
+	logger.Info("Processed list of words")
+}
+
+func (b *Bot) processToggleListedWordCommand(logger log.Logger, callbackMsg *callbackMessage) {
+	logger.Info("Received toggle listed word callback command")
+	var err error
+	if callbackMsg.data.Chosen == inVocabChosen {
+		err = b.vocabService.RemoveEntryFromUserVocab(callbackMsg.data.EntryID, callbackMsg.userID)
+	} else {
+		err = b.vocabService.AddEntryToUserVocab(callbackMsg.data.EntryID, callbackMsg.userID)
+	}
+	if err != nil {
+		logger.Errorf("Error toggling entry in vocab: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	b.editWordListSummary(logger, callbackMsg, false)
+	logger.Info("Processed toggle listed word callback command")
+}
+
+func (b *Bot) processAddAllListedCommand(logger log.Logger, callbackMsg *callbackMessage) {
+	logger.Info("Received add all listed words callback command")
+	b.editWordListSummary(logger, callbackMsg, true)
+	logger.Info("Processed add all listed words callback command")
+}
+
+// editWordListSummary looks up the words of the list quoted by the summary message again and replaces the summary.
+// The summary is built from the quoted message so it doesn't depend on the state kept by the bot.
+// If addAll is true then all found words which are not in the vocab yet are added before.
+func (b *Bot) editWordListSummary(logger log.Logger, callbackMsg *callbackMessage, addAll bool) {
+	words := domain.SplitWordList(callbackMsg.quotedText)
+	if len(words) < 2 {
+		logger.Info("Quoted list of words not found")
+		b.send(logger, newEditText(callbackMsg.chatID, callbackMsg.msgID, wordListOutdatedReply))
+		return
+	}
+	truncated := len(words) > maxListedWordsQnt
+	if truncated {
+		words = words[:maxListedWordsQnt]
+	}
+	langPair, err := b.langService.GetLangPair(callbackMsg.userID)
+	if err != nil {
+		logger.Errorf("Error getting language pair: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	lookups, err := b.lookupWords(callbackMsg.userID, words, langPair)
+	if err != nil {
+		logger.Errorf("Error looking up words: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	if addAll {
+		for _, l := range lookups {
+			if l.entry == nil || l.inVocab {
+				continue
+			}
+			err := b.vocabService.AddEntryToUserVocab(l.entry.ID, callbackMsg.userID)
+			if err != nil {
+				logger.Errorf("Error adding entry to vocab: %s", err)
+				b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+				return
+			}
+			l.inVocab = true
+		}
+	}
+	b.send(
+		logger,
+		newEditText(callbackMsg.chatID, callbackMsg.msgID, createWordListReply(lookups, truncated, true)).
+			withWordListKeyboard(logger, lookups),
+	)
+}
+
+// wordLookup is a result of looking up one word of the list.
+// Entry is nil if the word was not found.
+type wordLookup struct {
+	text    string
+	entry   *domain.VocabEntry
+	inVocab bool
+}
+
+// lookupWords looks up the words concurrently keeping their order.
+// At most maxLookupWorkers words are looked up at a time.
+func (b *Bot) lookupWords(userID int, words []string, langPair domain.LangPair) ([]*wordLookup, error) {
+	lookups := make([]*wordLookup, len(words))
+	errs := make([]error, len(words))
+	workers := make(chan struct{}, maxLookupWorkers)
+	var wg sync.WaitGroup
+	for i, word := range words {
+		wg.Add(1)
+		go func(i int, word string) {
+			defer wg.Done()
+			workers <- struct{}{}
+			defer func() { <-workers }()
+			lookups[i], errs[i] = b.lookupWord(userID, word, langPair)
+		}(i, word)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return nil, err
+		}
+	}
+	return lookups, nil
+}
+
+// lookupWord looks up the word in the direction detected by LookupPair as the single word lookup does.
+// The word written in the target language is resolved to the entry of its main translation,
+// so it can be added to the vocab of the user's language pair.
+func (b *Bot) lookupWord(userID int, word string, langPair domain.LangPair) (*wordLookup, error) {
+	lookup := &wordLookup{text: word}
+	text := word
+	if lookupPair := langPair.LookupPair(word); lookupPair != langPair {
+		reverseEntry, err := b.vocabService.GetVocabEntryByText(word, lookupPair)
+		if err != nil {
+			return nil, fmt.Errorf("getting reverse vocab entry for %s: %s", word, err)
+		}
+		if reverseEntry == nil || reverseEntry.MainTranslation == "" {
+			return lookup, nil
+		}
+		text = strings.ToLower(reverseEntry.MainTranslation)
+	}
+	entry, err := b.vocabService.GetVocabEntryByText(text, langPair)
+	if err != nil {
+		return nil, fmt.Errorf("getting vocab entry for %s: %s", text, err)
+	}
+	if entry == nil {
+		return lookup, nil
+	}
+	lookup.entry = entry
+	lookup.inVocab, err = b.vocabService.CheckEntryInUserVocab(entry.ID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("checking if entry is in the user's vocab: %s", err)
+	}
+	return lookup, nil
+}
+
+// createWordListReply returns the found words with their main translations followed by the words not found.
+// If hint is true then the hint about the word buttons is added.
+func createWordListReply(lookups []*wordLookup, truncated, hint bool) string {
+	builder := new(strings.Builder)
+	var found int
+	var notFound []string
+	for _, l := range lookups {
+		if l.entry != nil {
+			found++
+		} else {
+			notFound = append(notFound, l.text)
+		}
+	}
+	builder.WriteString(fmt.Sprintf(wordListReply, found, len(lookups)))
+	for _, l := range lookups {
+		if l.entry == nil {
+			continue
+		}
+		builder.WriteString(fmt.Sprintf("%s – %s", l.entry.Text, l.entry.MainTranslation))
+		if l.inVocab {
+			builder.WriteString(inVocabMark)
+		}
+		builder.WriteString("\n")
+	}
+	if len(notFound) != 0 {
+		builder.WriteString(fmt.Sprintf(wordsNotFoundReply, strings.Join(notFound, ", ")))
+	}
+	if truncated {
+		builder.WriteString(fmt.Sprintf(wordListTruncatedReply, maxListedWordsQnt))
+	}
+	if hint && found != 0 {
+		builder.WriteString(wordListHintReply)
+	}
+	return builder.String()
+}
+
+func (b *Bot) processExtractCommand(logger log.Logger, msg *message) {
+	logger.Info("Received /extract command")
+	text := strings.TrimSpace(msg.text[len(extractCommand):])
+	if text == "" {
+		b.states.set(msg.chatID, chatState{mode: extractMode})
+		b.send(logger, newReply(msg.chatID, extractPromptReply))
+		logger.Info("Processed /extract command (waiting for text)")
+		return
+	}
+	b.processExtractText(logger, msg, text)
+	logger.Info("Processed /extract command")
+}
+
+// processExtractText offers the words of the text which are not in the user's vocab as a checklist.
+func (b *Bot) processExtractText(logger log.Logger, msg *message, text string) {
+	logger.Info("Received text to extract words from")
+	langPair, err := b.langService.GetLangPair(msg.userID)
+	if err != nil {
+		logger.Errorf("Error getting language pair: %s", err)
+		b.send(logger, newReply(msg.chatID, techErrReply))
+		return
+	}
+	candidates, err := b.extractorService.ExtractUnknownWords(msg.userID, text, langPair)
+	if err != nil {
+		logger.Errorf("Error extracting words: %s", err)
+		b.send(logger, newReply(msg.chatID, techErrReply))
+		return
+	}
+	if len(candidates) == 0 {
+		logger.Info("Processed text to extract words from (no unknown words)")
+		b.send(logger, newReply(msg.chatID, noUnknownWordsReply).withQuote(msg.id))
+		return
+	}
+	if len(candidates) > maxExtractedQnt {
+		candidates = candidates[:maxExtractedQnt]
+	}
+	words := make([]string, 0, len(candidates))
+	for _, c := range candidates {
+		words = append(words, c.Text)
+	}
+	e := b.extractions.set(msg.chatID, words)
+	b.send(
+		logger,
+		newReply(msg.chatID, createExtractReply(&e, 0)).withQuote(msg.id).withExtractKeyboard(logger, &e, 0),
+	)
+	logger.Info("Processed text to extract words from")
+}
+
+// processExtractToggleCommand checks or unchecks the word of the checklist.
+// Chosen of the callback is the index of the word.
+func (b *Bot) processExtractToggleCommand(logger log.Logger, callbackMsg *callbackMessage) {
+	logger.Info("Received extract toggle callback command")
+	e, ok := b.extractions.toggle(callbackMsg.chatID, callbackMsg.data.Ref, callbackMsg.data.Chosen)
+	if !ok {
+		logger.Info("Processed extract toggle callback command (extraction is outdated)")
+		b.send(logger, newEditText(callbackMsg.chatID, callbackMsg.msgID, extractOutdatedReply))
+		return
+	}
+	pageNumber := callbackMsg.data.Chosen / extractPageSize
+	b.send(
+		logger,
+		newEditText(callbackMsg.chatID, callbackMsg.msgID, createExtractReply(&e, pageNumber)).
+			withExtractKeyboard(logger, &e, pageNumber),
+	)
+	logger.Info("Processed extract toggle callback command")
+}
+
+// processExtractPageCommand shows another page of the checklist.
+// Chosen of the callback is the page number.
+func (b *Bot) processExtractPageCommand(logger log.Logger, callbackMsg *callbackMessage) {
+	logger.Info("Received extract page callback command")
+	e, ok := b.extractions.get(callbackMsg.chatID, callbackMsg.data.Ref)
+	if !ok {
+		logger.Info("Processed extract page callback command (extraction is outdated)")
+		b.send(logger, newEditText(callbackMsg.chatID, callbackMsg.msgID, extractOutdatedReply))
+		return
+	}
+	b.send(
+		logger,
+		newEditText(callbackMsg.chatID, callbackMsg.msgID, createExtractReply(&e, callbackMsg.data.Chosen)).
+			withExtractKeyboard(logger, &e, callbackMsg.data.Chosen),
+	)
+	logger.Info("Processed extract page callback command")
+}
+
+// processExtractAddCommand looks up the checked words, adds the found ones to the vocab
+// and replaces the checklist with the summary.
+func (b *Bot) processExtractAddCommand(logger log.Logger, callbackMsg *callbackMessage) {
+	logger.Info("Received extract add callback command")
+	e, ok := b.extractions.get(callbackMsg.chatID, callbackMsg.data.Ref)
+	if !ok {
+		logger.Info("Processed extract add callback command (extraction is outdated)")
+		b.send(logger, newEditText(callbackMsg.chatID, callbackMsg.msgID, extractOutdatedReply))
+		return
+	}
+	words := e.selectedWords()
+	if len(words) == 0 {
+		logger.Info("Processed extract add callback command (nothing selected)")
+		b.send(logger, newReply(callbackMsg.chatID, extractNothingSelectedReply))
+		return
+	}
+	langPair, err := b.langService.GetLangPair(callbackMsg.userID)
+	if err != nil {
+		logger.Errorf("Error getting language pair: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	lookups, err := b.lookupWords(callbackMsg.userID, words, langPair)
+	if err != nil {
+		logger.Errorf("Error looking up words: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	for _, l := range lookups {
+		if l.entry == nil || l.inVocab {
+			continue
+		}
+		err := b.vocabService.AddEntryToUserVocab(l.entry.ID, callbackMsg.userID)
+		if err != nil {
+			logger.Errorf("Error adding entry to vocab: %s", err)
+			b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+			return
+		}
+		l.inVocab = true
+	}
+	b.extractions.reset(callbackMsg.chatID, e.id)
+	b.send(
+		logger,
+		newEditText(callbackMsg.chatID, callbackMsg.msgID, extractAddedReply+createWordListReply(lookups, false, false)),
+	)
+	logger.Info("Processed extract add callback command")
+}
+
+// createExtractReply returns the header of the checklist page.
+func createExtractReply(e *extraction, pageNumber int) string {
+	return fmt.Sprintf(extractPageReply, len(e.words), pageNumber+1, extractPagesQnt(e), len(e.selectedWords()))
+}
+
+// extractPagesQnt returns the number of pages the checklist words fit into.
+func extractPagesQnt(e *extraction) int {
+	return (len(e.words) + extractPageSize - 1) / extractPageSize
+}
+
+// processReverseLookup looks up the text written in the target language of the user's pair
+// and offers to add any of the found translations to the vocab.
+func (b *Bot) processReverseLookup(logger log.Logger, msg *message, text string, lookupPair domain.LangPair) {
+	logger = logger.WithField("lookupPair", lookupPair)
+	entry, err := b.vocabService.GetVocabEntryByText(text, lookupPair)
+	if err != nil {
+		logger.Errorf("Error getting vocab entry: %s", err)
+		b.send(logger, newReply(msg.chatID, techErrReply))
+		return
+	}
+	if entry == nil {
+		logger.Info("Text processed (not found by reverse lookup)")
+		b.send(logger, newReply(msg.chatID, fmt.Sprintf(wordNotFoundReply, langPairName(lookupPair))).withQuote(msg.id))
+		return
+	}
+	b.send(
+		logger,
+		newReply(msg.chatID, fmt.Sprintf(reverseLookupReply, entry.TranslationsDesc())).
+			withQuote(msg.id).withCandidatesKeyboard(logger, entry),
+	)
+	logger.Info("Text processed (reverse lookup)")
+}
+
+// processAddCandidateCommand adds the translation chosen from the reverse lookup results to the user's vocab.
+func (b *Bot) processAddCandidateCommand(logger log.Logger, callbackMsg *callbackMessage) {
+	logger.Info("Received add candidate callback command")
+	reverseEntry, err := b.vocabService.GetVocabEntryByID(callbackMsg.data.EntryID)
+	if err != nil {
+		logger.Errorf("Error getting vocab entry: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	var candidate string
+	if reverseEntry != nil {
+		for _, t := range reverseEntry.Translations {
+			if t.Position == callbackMsg.data.Chosen {
+				candidate = t.Text
+			}
+		}
+	}
+	if candidate == "" {
+		logger.Errorf("Candidate not found")
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	langPair := reverseEntry.LangPair.Reverse()
+	logger = logger.WithFields(map[string]interface{}{
+		"candidate": candidate,
+		"langPair":  langPair,
+	})
+	entry, err := b.vocabService.GetVocabEntryByText(strings.ToLower(candidate), langPair)
+	if err != nil {
+		logger.Errorf("Error getting vocab entry: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	if entry == nil {
+		logger.Info("Processed add candidate callback command (candidate not found)")
+		b.send(logger, newReply(callbackMsg.chatID, fmt.Sprintf(candidateNotFoundReply, candidate, langPairName(langPair))))
+		return
+	}
+	inVocab, err := b.vocabService.CheckEntryInUserVocab(entry.ID, callbackMsg.userID)
+	if err != nil {
+		logger.Errorf("Error checking if entry is in the user's vocab: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	if !inVocab {
+		err = b.vocabService.AddEntryToUserVocab(entry.ID, callbackMsg.userID)
+		if err != nil {
+			logger.Errorf("Error adding entry to vocab: %s", err)
+			b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+			return
+		}
+	}
+	b.send(
+		logger,
+		newReply(callbackMsg.chatID, fmt.Sprintf(candidateAddedReply, entry.Text, entry.ShortDesc())).
+			withShortDescKeyboard(logger, entry.ID, true),
+	)
+	logger.Info("Processed add candidate callback command")
+}
+
+func (b *Bot) processShowFullDescCommand(logger log.Logger, callbackMsg *callbackMessage) {
+	logger.Info("Received show full description callback command")
+	entry, err := b.vocabService.GetVocabEntryByID(callbackMsg.data.EntryID)
+	if err != nil {
+		logger.Errorf("Error getting vocab entry: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+	}
+	if entry == nil {
+		logger.Errorf("Vocab entry not found")
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	logger.WithField("vocabEntry", entry)
+	inVocab, err := b.vocabService.CheckEntryInUserVocab(entry.ID, callbackMsg.userID)
+	if err != nil {
+		logger.Errorf("Error checking if entry is in the user's vocab: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	b.send(
+		logger,
+		newEditText(callbackMsg.chatID, callbackMsg.msgID, entry.FullDesc(false)).withFullDescKeyboard(logger, entry.ID, inVocab),
+	)
+	logger.Info("Processed show full description callback command")
+}
+
+func (b *Bot) processAddToVocabCommand(logger log.Logger, callbackMsg *callbackMessage, fullDescShown bool) {
+	logger.Info("Received add to vocab callback command")
+	callbackData := callbackMsg.data
+	err := b.vocabService.AddEntryToUserVocab(callbackData.EntryID, callbackMsg.userID)
+	if err != nil {
+		logger.Errorf("Error adding entry to vocab: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	if fullDescShown {
+		b.send(
+			logger,
+			newEditKeyboardMsgFullDesc(logger, callbackMsg.chatID, callbackMsg.msgID, callbackData.EntryID, true),
+		)
+	} else {
+		b.send(
+			logger,
+			newEditKeyboardMsgShortDesc(logger, callbackMsg.chatID, callbackMsg.msgID, callbackData.EntryID, true),
+		)
+	}
+	logger.Info("Processed add to vocab callback command")
+}
+
+func (b *Bot) processRemoveFromVocabCommand(logger log.Logger, callbackMsg *callbackMessage, fullDescShown bool) {
+	logger.Info("Received remove from vocab callback command")
+	callbackData := callbackMsg.data
+	err := b.vocabService.RemoveEntryFromUserVocab(callbackData.EntryID, callbackMsg.userID)
+	if err != nil {
+		logger.Errorf("Error removing entry to vocab: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	if fullDescShown {
+		b.send(
+			logger,
+			newEditKeyboardMsgFullDesc(logger, callbackMsg.chatID, callbackMsg.msgID, callbackData.EntryID, false),
+		)
+	} else {
+		b.send(
+			logger,
+			newEditKeyboardMsgShortDesc(logger, callbackMsg.chatID, callbackMsg.msgID, callbackData.EntryID, false),
+		)
+	}
+	logger.Info("Processed remove from vocab callback command")
+}
+
+func (b *Bot) processEntriesPageCommand(logger log.Logger, callbackMsg *callbackMessage, search bool) {
+	logger.Infof("Received entries page callback command (search = %v)", search)
+	b.editEntriesPage(logger, callbackMsg, "", search)
+	logger.Info("Processed entries page callback command")
+}
+
+// processEntryCardCommand replaces the page of entries with the card of the chosen entry.
+// Chosen of the callback is the number of the page to return to.
+func (b *Bot) processEntryCardCommand(logger log.Logger, callbackMsg *callbackMessage, search bool) {
+	logger.Infof("Received entry card callback command (search = %v)", search)
+	entry, err := b.vocabService.GetVocabEntryByID(callbackMsg.data.EntryID)
+	if err != nil {
+		logger.Errorf("Error getting vocab entry: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	if entry == nil {
+		logger.Errorf("Vocab entry not found")
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	b.send(
+		logger,
+		newEditText(callbackMsg.chatID, callbackMsg.msgID, entry.FullDesc(true)).
+			withEntryCardKeyboard(logger, entry.ID, callbackMsg.data.Chosen, pageCallbacks(search), callbackMsg.data.Ref),
+	)
+	logger.Info("Processed entry card callback command")
+}
+
+func (b *Bot) processRemoveFromPageCommand(logger log.Logger, callbackMsg *callbackMessage, search bool) {
+	logger.Infof("Received remove from entries page callback command (search = %v)", search)
+	err := b.vocabService.RemoveEntryFromUserVocab(callbackMsg.data.EntryID, callbackMsg.userID)
+	if err != nil {
+		logger.Errorf("Error removing entry from vocab: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	b.editEntriesPage(logger, callbackMsg, listEntryRemovedReply, search)
+	logger.Info("Processed remove from entries page callback command")
+}
+
+// editEntriesPage replaces the message with the page of the user's vocab or the page of its search results.
+// Chosen of the callback is the number of the page. Prefix is added before the page text.
+func (b *Bot) editEntriesPage(logger log.Logger, callbackMsg *callbackMessage, prefix string, search bool) {
+	if search {
+		b.editFoundEntriesPage(logger, callbackMsg, prefix)
+		return
+	}
+	page, err := b.vocabService.GetEntriesPageFromUserVocab(callbackMsg.userID, callbackMsg.data.Chosen, listPageSize)
+	if err != nil {
+		logger.Errorf("Error getting page of entries: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	if page.Total == 0 {
+		b.send(logger, newEditText(callbackMsg.chatID, callbackMsg.msgID, prefix+emptyVocabReply))
+		return
+	}
+	b.send(
+		logger,
+		newEditText(callbackMsg.chatID, callbackMsg.msgID, prefix+createListReply(page)).
+			withEntriesPageKeyboard(logger, page, listCallbacks, 0),
+	)
+}
+
+func (b *Bot) editFoundEntriesPage(logger log.Logger, callbackMsg *callbackMessage, prefix string) {
+	query := b.searchQueries.get(callbackMsg.chatID, callbackMsg.data.Ref)
+	if query == "" {
+		logger.Info("Search query not found")
+		b.send(logger, newEditText(callbackMsg.chatID, callbackMsg.msgID, prefix+findOutdatedReply))
+		return
+	}
+	page, err := b.vocabService.FindEntriesInUserVocab(callbackMsg.userID, query, callbackMsg.data.Chosen, listPageSize)
+	if err != nil {
+		logger.Errorf("Error finding entries: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	if page.Total == 0 {
+		b.send(logger, newEditText(callbackMsg.chatID, callbackMsg.msgID, prefix+fmt.Sprintf(nothingFoundReply, query)))
+		return
+	}
+	b.send(
+		logger,
+		newEditText(callbackMsg.chatID, callbackMsg.msgID, prefix+createFindReply(query, page)).
+			withEntriesPageKeyboard(logger, page, findCallbacks, callbackMsg.data.Ref),
+	)
+}
+
+func (b *Bot) processClearVocabAnswerCommand(logger log.Logger, callbackMsg *callbackMessage, accepted bool) {
+	logger.Infof("Received clear vocab answer callback command (%v)", accepted)
+	if !accepted {
+		b.send(logger, newEditText(callbackMsg.chatID, callbackMsg.msgID, clearVocabDeclinedReply))
+		logger.Info("Processed clear vocab answer callback command")
+		return
+	}
+	err := b.vocabService.ClearUserVocab(callbackMsg.userID)
+	if err != nil {
+		logger.Errorf("Error clearing the user's vocab: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	b.send(logger, newEditText(callbackMsg.chatID, callbackMsg.msgID, clearVocabAcceptedReply))
+	logger.Info("Processed clear vocab answer callback command")
+}
+
+func (b *Bot) processRepeatCallbackCommand(logger log.Logger, callbackMsg *callbackMessage) {
+	logger.Info("Received repeat callback command")
+	err := b.schedulerService.ReviewEntry(
+		callbackMsg.data.EntryID, callbackMsg.userID, domain.DirectionForward, domain.GradeGood,
+	)
+	if err != nil {
+		logger.Errorf("Error reviewing entry: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	b.checkDailyGoal(logger, callbackMsg.chatID, callbackMsg.userID)
+	entry, err := b.schedulerService.GetNextEntryToReview(
+		callbackMsg.userID, callbackMsg.data.EntryID, domain.DirectionForward,
+	)
+	if err != nil {
+		logger.Errorf("Error getting next entry to review: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	if entry == nil {
+		logger.Info("Processed repeat callback command (no entries)")
+		b.send(logger, newReply(callbackMsg.chatID, emptyVocabReply))
+		return
+	}
+	b.send(logger, newReply(callbackMsg.chatID, entry.FullDesc(true)).withRepeatKeyboard(logger, entry.ID))
+	logger.Info("Processed repeat callback command")
+}
+
+func (b *Bot) processStartQuizCommand(logger log.Logger, callbackMsg *callbackMessage) {
+	logger.Info("Received start quiz callback command")
+	direction := callbackMsg.data.Direction
+	b.send(logger, newEditText(callbackMsg.chatID, callbackMsg.msgID, quizDirectionButtons[direction]))
+	b.startQuizSession(logger, callbackMsg.chatID, callbackMsg.userID, direction)
+	logger.Info("Processed start quiz callback command")
+}
+
+func (b *Bot) processStartReminderQuizCommand(logger log.Logger, callbackMsg *callbackMessage) {
+	logger.Info("Received start reminder quiz callback command")
+	b.send(logger, newEditKeyboardMsgRemoved(callbackMsg.chatID, callbackMsg.msgID))
+	b.startQuizSession(logger, callbackMsg.chatID, callbackMsg.userID, domain.DirectionForward)
+	logger.Info("Processed start reminder quiz callback command")
+}
+
+func (b *Bot) startQuizSession(logger log.Logger, chatID int64, userID int, direction domain.Direction) {
+	session, err := b.quizService.StartQuizSession(userID, direction, quizSessionSize)
+	if err != nil {
+		logger.Errorf("Error starting quiz session: %s", err)
+		b.send(logger, newReply(chatID, techErrReply))
+		return
+	}
+	if session == nil {
+		logger.Info("User's vocab is empty")
+		b.send(logger, newReply(chatID, emptyVocabReply))
+		return
+	}
+	b.sendNextQuizEntry(logger, chatID, session)
+}
+
+// processContinueQuizCommand handles buttons of the quiz messages sent before quiz sessions were introduced.
+func (b *Bot) processContinueQuizCommand(logger log.Logger, callbackMsg *callbackMessage) {
+	logger.Info("Received continue quiz callback command")
+	b.send(logger, newEditKeyboardMsgRemoved(callbackMsg.chatID, callbackMsg.msgID))
+	b.send(logger, newReply(callbackMsg.chatID, quizOutdatedReply))
+	logger.Info("Processed continue quiz callback command")
+}
+
+func (b *Bot) processStopQuizCommand(logger log.Logger, callbackMsg *callbackMessage) {
+	logger.Info("Received stop quiz callback command")
+	session, err := b.quizService.StopQuizSession(callbackMsg.userID)
+	if err != nil {
+		logger.Errorf("Error stopping quiz session: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	b.send(logger, newEditKeyboardMsgRemoved(callbackMsg.chatID, callbackMsg.msgID))
+	if session == nil {
+		logger.Info("Processed stop quiz callback command (no active session)")
+		b.send(logger, newReply(callbackMsg.chatID, quizOutdatedReply))
+		return
+	}
+	b.sendQuizSummary(logger, callbackMsg.chatID, session)
+	logger.Info("Processed stop quiz callback command")
+}
+
+func (b *Bot) processGradeCommand(logger log.Logger, callbackMsg *callbackMessage, grade domain.Grade) {
+	logger.Infof("Received grade callback command (%s)", grade)
+	data := callbackMsg.data
+	session, err := b.quizService.AnswerQuizSessionEntry(data.EntryID, callbackMsg.userID, data.Direction, grade)
+	if err != nil {
+		logger.Errorf("Error answering quiz session entry: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	if session == nil {
+		logger.Info("Processed grade callback command (entry is not waiting for an answer in the active session)")
+		return
+	}
+	b.send(logger, newEditKeyboardMsgGraded(logger, callbackMsg.chatID, callbackMsg.msgID, grade))
+	b.checkDailyGoal(logger, callbackMsg.chatID, callbackMsg.userID)
+	if session.FinishedAt != nil {
+		b.sendQuizSummary(logger, callbackMsg.chatID, session)
+	} else {
+		b.sendNextQuizEntry(logger, callbackMsg.chatID, session)
+	}
+	logger.Info("Processed grade callback command")
+}
+
+func (b *Bot) sendNextQuizEntry(logger log.Logger, chatID int64, session *domain.QuizSession) {
+	entryID, ok := session.NextEntryID()
+	if !ok {
+		logger.Errorf("Quiz session has no entries to answer")
+		b.send(logger, newReply(chatID, techErrReply))
+		return
+	}
+	entry, err := b.vocabService.GetVocabEntryByID(entryID)
+	if err != nil {
+		logger.Errorf("Error getting vocab entry: %s", err)
+		b.send(logger, newReply(chatID, techErrReply))
+		return
+	}
+	if entry == nil {
+		logger.Errorf("Vocab entry not found")
+		b.send(logger, newReply(chatID, techErrReply))
+		return
+	}
+	text := fmt.Sprintf(quizProgressReply, session.AnsweredQnt()+1, len(session.Entries)) +
+		quizQuestion(entry, session.Direction)
+	b.send(logger, newReply(chatID, text).withQuizKeyboard(logger, entry.ID, session.Direction))
+}
+
+func (b *Bot) sendQuizSummary(logger log.Logger, chatID int64, session *domain.QuizSession) {
+	summary, err := b.quizService.GetQuizSummary(session)
+	if err != nil {
+		logger.Errorf("Error getting quiz summary: %s", err)
+		b.send(logger, newReply(chatID, techErrReply))
+		return
+	}
+	b.send(logger, newReply(chatID, createQuizSummaryReply(summary)))
+}
+
+func createQuizSummaryReply(summary *domain.QuizSummary) string {
+	builder := new(strings.Builder)
+	builder.WriteString(fmt.Sprintf(quizSummaryReply, len(summary.Known), len(summary.Known)+len(summary.Missed)))
+	if len(summary.Known) != 0 {
+		builder.WriteString(quizKnownEntriesReply)
+		for _, entry := range summary.Known {
+			builder.WriteString(fmt.Sprintf("%s – %s\n", entry.Text, entry.MainTranslation))
+		}
+	}
+	if len(summary.Missed) != 0 {
+		builder.WriteString(quizMissedEntriesReply)
+		for _, entry := range summary.Missed {
+			builder.WriteString(fmt.Sprintf("%s – %s\n", entry.Text, entry.MainTranslation))
+		}
+	}
+	return builder.String()
+}
+
+func quizQuestion(entry *domain.VocabEntry, direction domain.Direction) string {
+	if direction == domain.DirectionReverse {
+		return reverseQuizPrompt + entry.TranslationsDesc()
+	}
+	return entry.Text
+}
+
+func (b *Bot) processShowAnswerCommand(logger log.Logger, callbackMsg *callbackMessage) {
+	logger.Info("Received show answer callback command")
+	entry, err := b.vocabService.GetVocabEntryByID(callbackMsg.data.EntryID)
+	if err != nil {
+		logger.Errorf("Error getting vocab entry: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+	}
+	if entry == nil {
+		logger.Errorf("Vocab entry not found")
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	logger.WithField("vocabEntry", entry)
+	b.send(
+		logger,
+		newEditText(callbackMsg.chatID, callbackMsg.msgID, entry.FullDesc(true)).
+			withGradeKeyboard(logger, entry.ID, callbackMsg.data.Direction),
+	)
+	logger.Info("Processed show answer callback command")
+}
+
+func (b *Bot) processTypeQuizGiveUpCommand(logger log.Logger, callbackMsg *callbackMessage) {
+	logger.Info("Received type quiz give up callback command")
+	state := chatState{mode: typeQuizMode, entryID: callbackMsg.data.EntryID}
+	if !b.states.take(callbackMsg.chatID, state) {
+		logger.Info("Processed type quiz give up callback command (question is outdated)")
+		return
+	}
+	entry, err := b.vocabService.GetVocabEntryByID(state.entryID)
+	if err != nil {
+		logger.Errorf("Error getting vocab entry: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	if entry == nil {
+		logger.Errorf("Vocab entry not found")
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	err = b.schedulerService.ReviewEntry(entry.ID, callbackMsg.userID, domain.DirectionForward, domain.GradeAgain)
+	if err != nil {
+		logger.Errorf("Error reviewing entry: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	b.send(logger, newEditText(callbackMsg.chatID, callbackMsg.msgID, entry.FullDesc(true)))
+	b.checkDailyGoal(logger, callbackMsg.chatID, callbackMsg.userID)
+	b.sendNextTypeQuizEntry(logger, callbackMsg.chatID, callbackMsg.userID, entry.ID)
+	logger.Info("Processed type quiz give up callback command")
+}
+
+func (b *Bot) processTypeQuizStopCommand(logger log.Logger, callbackMsg *callbackMessage) {
+	logger.Info("Received type quiz stop callback command")
+	b.states.reset(callbackMsg.chatID)
+	b.send(logger, newEditKeyboardMsgRemoved(callbackMsg.chatID, callbackMsg.msgID))
+	b.send(logger, newReply(callbackMsg.chatID, typeQuizStoppedReply))
+	logger.Info("Processed type quiz stop callback command")
+}
+
+func (b *Bot) processChoiceAnswerCommand(logger log.Logger, callbackMsg *callbackMessage) {
+	logger.Info("Received choice answer callback command")
+	data := callbackMsg.data
+	if !b.choiceQuestions.take(callbackMsg.chatID, data.EntryID) {
+		logger.Info("Processed choice answer callback command (question is already answered or outdated)")
+		return
+	}
+	entry, err := b.vocabService.GetVocabEntryByID(data.EntryID)
+	if err != nil {
+		logger.Errorf("Error getting vocab entry: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	if entry == nil {
+		logger.Errorf("Vocab entry not found")
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	grade := domain.GradeGood
+	result := correctAnswerReply
+	if data.Chosen != data.EntryID {
+		grade = domain.GradeAgain
+		result = wrongAnswerReply
+		chosen, err := b.vocabService.GetVocabEntryByID(data.Chosen)
+		if err != nil {
+			logger.Errorf("Error getting chosen vocab entry: %s", err)
+			b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+			return
+		}
+		if chosen != nil {
+			result += fmt.Sprintf(" %s – %s", chosen.MainTranslation, chosen.Text)
+		}
+	}
+	err = b.schedulerService.ReviewEntry(entry.ID, callbackMsg.userID, domain.DirectionForward, grade)
+	if err != nil {
+		logger.Errorf("Error reviewing entry: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	b.send(
+		logger,
+		newEditText(callbackMsg.chatID, callbackMsg.msgID, result+"\n\n"+entry.FullDesc(true)).
+			withNextChoiceKeyboard(logger, entry.ID),
+	)
+	b.checkDailyGoal(logger, callbackMsg.chatID, callbackMsg.userID)
+	logger.Infof("Processed choice answer callback command (%s)", grade)
+}
+
+func (b *Bot) processNextChoiceCommand(logger log.Logger, callbackMsg *callbackMessage) {
+	logger.Info("Received next choice callback command")
+	b.send(logger, newEditKeyboardMsgRemoved(callbackMsg.chatID, callbackMsg.msgID))
+	b.sendNextChoiceQuestion(logger, callbackMsg.chatID, callbackMsg.userID, callbackMsg.data.EntryID)
+	logger.Info("Processed next choice callback command")
+}
+
+func logProcessingTime(logger log.Logger, start time.Time) {
+	logger.Debugf("Processing time: %s", time.Since(start))
+}
+
+// importResult is the outcome of the import of the document rows.
+type importResult struct {
+	mu        sync.Mutex
+	processed int
+	added     int
+	custom    int
+	inVocab   int
+	failed    int
+	notFound  []string
+	truncated bool
+}
+
+func (r *importResult) getProcessed() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.processed
+}
+
+// processDocument imports the words of the uploaded .txt or .csv document to the user's vocab.
+// The rows are looked up concurrently, the progress message is updated while the import goes.
+func (b *Bot) processDocument(logger log.Logger, msg *message) {
+	logger.Info("Received document")
+	ext := strings.ToLower(filepath.Ext(msg.document.FileName))
+	if ext != ".txt" && ext != ".csv" || msg.document.FileSize > maxImportFileSize {
+		logger.Info("Processed document (unsupported document)")
+		b.send(logger, newReply(msg.chatID, fmt.Sprintf(importUnsupportedReply, maxImportFileSize/1024)).withQuote(msg.id))
+		return
+	}
+	content, err := b.downloadDocument(msg.document)
+	if err != nil {
+		logger.Errorf("Error downloading document: %s", err)
+		b.send(logger, newReply(msg.chatID, techErrReply))
+		return
+	}
+	rows := domain.ParseImportRows(content)
+	if len(rows) == 0 {
+		logger.Info("Processed document (no words)")
+		b.send(logger, newReply(msg.chatID, importEmptyReply).withQuote(msg.id))
+		return
+	}
+	langPair, err := b.langService.GetLangPair(msg.userID)
+	if err != nil {
+		logger.Errorf("Error getting language pair: %s", err)
+		b.send(logger, newReply(msg.chatID, techErrReply))
+		return
+	}
+	result := new(importResult)
+	if len(rows) > maxImportRowsQnt {
+		rows = rows[:maxImportRowsQnt]
+		result.truncated = true
+	}
+	progressMsgID := b.sendWithID(
+		logger,
+		newReply(msg.chatID, fmt.Sprintf(importProgressReply, 0, len(rows))).withQuote(msg.id),
+	)
+	done := make(chan struct{})
+	var wg sync.WaitGroup
+	if progressMsgID != 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			b.reportImportProgress(logger, msg.chatID, progressMsgID, result, len(rows), done)
+		}()
+	}
+	b.importRows(logger, msg.userID, rows, langPair, result)
+	close(done)
+	// the progress must not overwrite the summary
+	wg.Wait()
+	summary := createImportSummaryReply(result)
+	if progressMsgID != 0 {
+		b.send(logger, newEditText(msg.chatID, progressMsgID, summary))
+	} else {
+		b.send(logger, newReply(msg.chatID, summary).withQuote(msg.id))
+	}
+	logger.Info("Processed document")
+}
+
+// downloadDocument downloads the document through the bot API file endpoint.
+func (b *Bot) downloadDocument(document *tgbotapi.Document) (string, error) {
+	url, err := b.api.GetFileDirectURL(document.FileID)
+	if err != nil {
+		return "", fmt.Errorf("getting file URL: %s", err)
+	}
+	client := http.Client{Timeout: importDownloadTimeout}
+	resp, err := client.Get(url)
+	if err != nil {
+		return "", fmt.Errorf("downloading file: %s", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("downloading file: unexpected status %s", resp.Status)
+	}
+	content, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxImportFileSize))
+	if err != nil {
+		return "", fmt.Errorf("reading file: %s", err)
+	}
+	return string(content), nil
+}
+
+// importRows adds the rows to the user's vocab using not more than maxImportWorkers goroutines.
+// The errors of the rows are logged and counted in the result, they don't stop the import.
+func (b *Bot) importRows(
+	logger log.Logger, userID int, rows []*domain.ImportRow, langPair domain.LangPair, result *importResult,
+) {
+	workers := make(chan struct{}, maxImportWorkers)
+	var wg sync.WaitGroup
+	for _, row := range rows {
+		wg.Add(1)
+		go func(row *domain.ImportRow) {
+			defer wg.Done()
+			workers <- struct{}{}
+			defer func() { <-workers }()
+			b.importRow(logger.WithField("row", row), userID, row, langPair, result)
+		}(row)
+	}
+	wg.Wait()
+}
+
+func (b *Bot) importRow(
+	logger log.Logger, userID int, row *domain.ImportRow, langPair domain.LangPair, result *importResult,
+) {
+	var added, custom, inVocab, failed, notFound bool
+	defer func() {
+		result.mu.Lock()
+		defer result.mu.Unlock()
+		result.processed++
+		switch {
+		case failed:
+			result.failed++
+		case notFound:
+			result.notFound = append(result.notFound, row.Text)
+		case inVocab:
+			result.inVocab++
+		case added:
+			result.added++
+			if custom {
+				result.custom++
+			}
+		}
+	}()
+	entry, err := b.vocabService.GetVocabEntryByText(row.Text, langPair)
+	if err != nil {
+		logger.Errorf("Error getting vocab entry: %s", err)
+		failed = true
+		return
+	}
+	if entry == nil && row.Translation != "" {
+		entry, err = b.vocabService.AddCustomVocabEntry(row.Text, row.Translation, langPair, userID)
+		if err != nil {
+			logger.Errorf("Error adding custom vocab entry: %s", err)
+			failed = true
+			return
+		}
+		custom = true
+	}
+	if entry == nil {
+		notFound = true
+		return
+	}
+	inVocab, err = b.vocabService.CheckEntryInUserVocab(entry.ID, userID)
+	if err != nil {
+		logger.Errorf("Error checking if entry is in the user's vocab: %s", err)
+		failed = true
+		return
+	}
+	if inVocab {
+		return
+	}
+	err = b.vocabService.AddEntryToUserVocab(entry.ID, userID)
+	if err != nil {
+		logger.Errorf("Error adding entry to vocab: %s", err)
+		failed = true
+		return
+	}
+	added = true
+}
+
+// reportImportProgress updates the progress message every importProgressInterval until done is closed.
+func (b *Bot) reportImportProgress(
+	logger log.Logger, chatID int64, msgID int, result *importResult, total int, done <-chan struct{},
+) {
+	ticker := time.NewTicker(importProgressInterval)
+	defer ticker.Stop()
+	var reported int
+	for {
+		select {
+		case <-done:
+			return
+		case <-ticker.C:
+			processed := result.getProcessed()
+			if processed == reported {
+				continue
+			}
+			reported = processed
+			b.send(logger, newEditText(chatID, msgID, fmt.Sprintf(importProgressReply, processed, total)))
+		}
+	}
+}
+
+func createImportSummaryReply(result *importResult) string {
+	result.mu.Lock()
+	defer result.mu.Unlock()
+	builder := new(strings.Builder)
+	builder.WriteString(fmt.Sprintf(importSummaryReply, result.added, result.custom, result.inVocab))
+	if len(result.notFound) != 0 {
+		sort.Strings(result.notFound)
+		shown := result.notFound
+		if len(shown) > maxImportNotFoundShown {
+			shown = shown[:maxImportNotFoundShown]
+		}
+		notFound := strings.Join(shown, ", ")
+		if len(result.notFound) > len(shown) {
+			notFound += fmt.Sprintf(importMoreNotFoundReply, len(result.notFound)-len(shown))
+		}
+		builder.WriteString(fmt.Sprintf(importNotFoundReply, notFound))
+	}
+	if result.failed != 0 {
+		builder.WriteString(fmt.Sprintf(importFailedReply, result.failed))
+	}
+	if result.truncated {
+		builder.WriteString(fmt.Sprintf(importTruncatedReply, maxImportRowsQnt))
+	}
+	return builder.String()
+}
+
+func (b *Bot) processExportCommand(logger log.Logger, msg *message) {
+	logger.Info("Received /export command")
+	b.send(logger, newReply(msg.chatID, exportFormatReply).withExportKeyboard(logger))
+	logger.Info("Processed /export command")
+}
+
+// processExportFormatCommand sends the user's vocab as a document in the chosen format.
+// Chosen of the callback is the export format.
+func (b *Bot) processExportFormatCommand(logger log.Logger, callbackMsg *callbackMessage) {
+	logger.Info("Received export format callback command")
+	format := export.Format(callbackMsg.data.Chosen)
+	entries, err := b.vocabService.GetExportEntriesFromUserVocab(callbackMsg.userID)
+	if err != nil {
+		logger.Errorf("Error getting entries to export: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	if len(entries) == 0 {
+		logger.Info("Processed export format callback command (empty vocab)")
+		b.send(logger, newEditText(callbackMsg.chatID, callbackMsg.msgID, emptyVocabReply))
+		return
+	}
+	buf := new(bytes.Buffer)
+	err = export.Write(buf, format, entries)
+	if err != nil {
+		logger.Errorf("Error serializing entries: %s", err)
+		b.send(logger, newReply(callbackMsg.chatID, techErrReply))
+		return
+	}
+	b.send(logger, newDocument(
+		callbackMsg.chatID,
+		exportFileName(format, time.Now()),
+		buf.Bytes(),
+		fmt.Sprintf(exportCaptionReply, len(entries)),
+	))
+	b.send(logger, newEditText(callbackMsg.chatID, callbackMsg.msgID, fmt.Sprintf(exportSentReply, format)))
+	logger.Info("Processed export format callback command")
+}
+
+// exportFileName returns the name of the exported file, e.g. "pimpmyvocab-2020-05-17.csv".
+func exportFileName(format export.Format, now time.Time) string {
+	return "pimpmyvocab-" + now.Format("2006-01-02") + format.FileExtension()
 }
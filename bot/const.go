@@ -1,39 +1,229 @@
 package bot
 
+import "time"
+
 const (
-	startCommand  = "/start"
-	helpCommand   = "/help"
-	listCommand   = "/list"
-	clearCommand  = "/clear"
-	repeatCommand = "/repeat"
-	quizCommand   = "/quiz"
-
-	helpReply = "Теперь у вас в телеграме есть личный словарь для изучения английского языка!\n\n" +
-		"Пришлите боту английское слово, чтобы получить по нему краткую словарную статью " +
+	startCommand   = "/start"
+	helpCommand    = "/help"
+	listCommand    = "/list"
+	clearCommand   = "/clear"
+	repeatCommand  = "/repeat"
+	quizCommand    = "/quiz"
+	typeCommand    = "/type"
+	choiceCommand  = "/choice"
+	statsCommand   = "/stats"
+	remindCommand  = "/remind"
+	goalCommand    = "/goal"
+	langCommand    = "/lang"
+	decksCommand   = "/decks"
+	newDeckCommand = "/newdeck"
+	useDeckCommand = "/usedeck"
+	findCommand    = "/find"
+	extractCommand = "/extract"
+	exportCommand  = "/export"
+
+	remindOnArg  = "on"
+	remindOffArg = "off"
+	goalOffArg   = "off"
+
+	helpReply = "Теперь у вас в телеграме есть личный словарь для изучения иностранных языков!\n\n" +
+		"Пришлите боту слово на изучаемом языке, чтобы получить по нему краткую словарную статью " +
 		"с возможностью добавить её в свой словарь.\n\n" +
-		"Используйте команду /list для просмотра словаря.\n\n" +
+		"По умолчанию бот ищет английские слова с переводом на русский. " +
+		"Выбрать другую языковую пару, например немецкий → русский или английский → украинский, " +
+		"можно командой /lang\n\n" +
+		"Можно прислать сразу несколько слов через запятую или каждое с новой строки: " +
+		"бот найдёт их все и предложит добавить в словарь одним нажатием.\n\n" +
+		"Читаете статью или книгу? Отправьте /extract и пришлите отрывок текста: бот найдёт в нём слова, " +
+		"которых ещё нет в вашем словаре, начиная с самых редких, и добавит отмеченные.\n\n" +
+		"Чтобы выгрузить словарь в CSV, JSON или колоду Anki, отправьте /export\n\n" +
+		"Чтобы добавить много слов сразу, пришлите файл .txt или .csv, где каждое слово записано на отдельной строке. " +
+		"Рядом со словом через точку с запятой можно указать свой перевод, например «hedgehog;ёж». " +
+		"Он пригодится, если бот не найдёт слово в словаре.\n\n" +
+		"Если прислать слово на русском (или украинском) языке, бот покажет варианты перевода, " +
+		"и любой из них можно сразу добавить в словарь.\n\n" +
+		"Используйте команду /list для просмотра словаря. Слова в нём сгруппированы по тому, насколько хорошо вы их знаете. " +
+		"Нажмите на слово в списке, чтобы открыть его карточку и при необходимости удалить из словаря. " +
+		"Команда /find поможет найти слова в словаре по началу слова, части перевода или части речи, например /find verb. " +
+		"Слова, которые вы правильно вспомнили %v раз подряд, считаются выученными: " +
+		"бот будет изредка напоминать о них, чтобы они не забылись.\n\n" +
 		"Команда /repeat поможет вам закрепить знания.\n\n" +
-		"Команду /quiz используйте для проверки своих знаний.\n\n" +
+		"Команду /quiz используйте для проверки своих знаний: в начале проверки можно выбрать, " +
+		"вспоминать перевод слов или слова по переводу. " +
+		"Одна проверка – %v слов, в конце бот покажет, какие слова вы помните, а какие стоит повторить. " +
+		"Оцените, насколько легко вы вспомнили перевод, и бот покажет слово снова в нужный момент.\n\n" +
+		"В режиме /type бот присылает слово, а вы пишете его перевод. Небольшие опечатки бот простит.\n\n" +
+		"В режиме /choice нужно выбрать правильный перевод из нескольких вариантов.\n\n" +
+		"Команда /stats покажет вашу статистику: сколько слов в словаре, сколько повторений вы сделали " +
+		"и сколько дней подряд занимаетесь.\n\n" +
+		"Команда /remind настроит ежедневное напоминание о словах, которые пора повторить.\n\n" +
+		"Команда /goal поможет поставить цель – сколько слов повторять каждый день.\n\n" +
+		"Словарей может быть несколько: команда /newdeck создаст новый словарь, /usedeck переключит на другой, " +
+		"а /decks покажет список. Новые слова, /list, /repeat, /quiz и /clear работают с текущим словарём.\n\n" +
 		"Команда /clear – очистка словаря. Не волнуйтесь, бот уточнит ваше намерение начать всё с чистого листа.\n\n" +
 		"Если слово не отображается в словаре после добавления, повторно отправьте команду /start"
 	techErrReply = "Кажется, у бота технические проблемы :(\n" +
 		"Попробуйте повторить запрос позже. А мы пока поменяем ему масло."
 	offlineReply = "Наверное, вы заметили, что какое-то время наш бот отдыхал и не мог обрабатывать ваши запросы.\n" +
 		"Теперь он снова в строю!"
+	listPageReply     = "Слов в словаре: %v. Страница %v из %v.\n\n"
+	listHintReply     = "\nНажмите на слово, чтобы открыть его карточку."
+	findPageReply     = "Найдено по запросу «%s»: %v. Страница %v из %v.\n\n"
+	nothingFoundReply = "По запросу «%s» в словаре ничего не нашлось."
+	findUsageReply    = "Чтобы найти слова в словаре, отправьте начало слова, часть перевода " +
+		"или часть речи, например:\n/find hed\n/find ёж\n/find verb"
+	findOutdatedReply      = "Результаты поиска устарели. Повторите поиск командой /find"
+	wordListReply          = "Найдено слов: %v из %v.\n\n"
+	inVocabMark            = " (уже в словаре)"
+	wordsNotFoundReply     = "\nНе найдены: %s\n"
+	wordListTruncatedReply = "\nБот обработал только первые %v слов."
+	wordListHintReply      = "\nНажмите на слово, чтобы добавить его в словарь или удалить из него."
+	wordListOutdatedReply  = "Бот не нашёл исходный список слов. Пришлите его ещё раз."
+	extractPromptReply     = "Пришлите текст на изучаемом языке, например абзац из статьи или книги, " +
+		"и бот предложит незнакомые слова из него."
+	noUnknownWordsReply = "Бот не нашёл в тексте слов, которых ещё нет в вашем словаре."
+	extractPageReply    = "Новых слов в тексте: %v, сначала самые редкие. Страница %v из %v. Отмечено: %v.\n\n" +
+		"Отметьте слова, которые хотите выучить, и добавьте их в словарь."
+	extractOutdatedReply        = "Этот список слов устарел. Чтобы получить новый, отправьте /extract"
+	extractAddedReply           = "Отмеченные слова добавлены в словарь!\n"
+	extractNothingSelectedReply = "Сначала отметьте в списке слова, которые хотите добавить."
+	exportFormatReply           = "В каком формате выгрузить словарь?\n\n" +
+		"CSV откроется в Excel или Google Таблицах, JSON пригодится программистам, " +
+		"а Anki APKG — готовая колода Anki с карточками в обе стороны. " +
+		"Anki TXT подойдёт, если вы хотите сами выбрать тип записей при импорте в Anki."
+	exportCaptionReply     = "Слов в словаре: %v."
+	exportSentReply        = "Словарь выгружен в формате %s."
+	importUnsupportedReply = "Бот умеет импортировать слова только из файлов .txt и .csv размером до %v КБ."
+	importEmptyReply       = "В файле не нашлось ни одного слова. Пришлите файл, где каждое слово " +
+		"записано на отдельной строке, например «hedgehog» или «hedgehog;ёж»."
+	importProgressReply = "Импорт слов из файла: обработано %v из %v."
+	importSummaryReply  = "Импорт завершён!\nДобавлено в словарь: %v, из них с вашим переводом: %v.\n" +
+		"Уже были в словаре: %v.\n"
+	importNotFoundReply         = "Не найдены: %s\n"
+	importMoreNotFoundReply     = " и ещё %v"
+	importFailedReply           = "Не удалось обработать из-за технических проблем: %v.\n"
+	importTruncatedReply        = "Бот обработал только первые %v строк файла.\n"
+	listEntryRemovedReply       = "Слово удалено из словаря.\n\n"
 	emptyVocabReply             = "В вашем словаре пока нет записей.\nНо ведь это легко исправить ;)"
 	clearVocabConfirmationReply = "Вы уверены, что хотите удалить все записи из своего словаря?"
 	clearVocabDeclinedReply     = "Вот и правильно, отличный же словарь!"
 	clearVocabAcceptedReply     = "Готово! Начните с чистого листа!"
-	wordNotFoundReply           = "А вы точно продюсер? А это точно английское слово?\n" +
-		"Просто бот по нему ничего не нашёл :("
+	wordNotFoundReply           = "А вы точно продюсер? А это точно слово из пары %s?\n" +
+		"Просто бот по нему ничего не нашёл :(\n" +
+		"Сменить языковую пару – /lang"
+	lemmaReply                     = "%s → %s\n"
+	reverseLookupReply             = "%s\n\nНажмите на слово, чтобы добавить его в словарь."
+	candidateNotFoundReply         = "Бот не нашёл слово «%s» в словаре %s :("
+	candidateAddedReply            = "Слово добавлено в словарь:\n%s\n%s"
+	quizDirectionReply             = "Что будем вспоминать?"
+	reverseQuizPrompt              = "Вспомните слово:\n\n"
+	typeQuizPrompt                 = "Напишите перевод слова:\n"
+	correctAnswerReply             = "Верно!"
+	closeAnswerReply               = "Почти верно, но проверьте написание."
+	wrongAnswerReply               = "Неверно."
+	typeQuizStoppedReply           = "Проверка завершена. Теперь бот снова ищет присланные слова в словаре."
+	notEnoughEntriesForChoiceReply = "Чтобы было из чего выбирать, добавьте в словарь хотя бы два слова с разными переводами."
+	quizProgressReply              = "Слово %v из %v\n\n"
+	quizSummaryReply               = "Проверка завершена! Вспомнили %v из %v."
+	quizKnownEntriesReply          = "\n\nВы помните:\n"
+	quizMissedEntriesReply         = "\n\nСтоит повторить:\n"
+	quizOutdatedReply              = "Эта проверка уже завершена. Чтобы начать новую, отправьте /quiz"
+	remindersEnabledReply          = "Бот напомнит о повторении слов каждый день в %s (%s).\n\n"
+	remindersDisabledReply         = "Напоминания выключены.\n\n"
+	remindUsageReply               = "Чтобы настроить напоминание, отправьте время и часовой пояс, например:\n" +
+		"/remind 9:00 Europe/Moscow\n" +
+		"Часовой пояс можно не указывать, если он уже настроен.\n" +
+		"Выключить напоминания – /remind off, включить снова – /remind on"
+	unknownTimezoneReply = "Бот не знает такого часового пояса. " +
+		"Укажите его так же, как в базе часовых поясов IANA, например Europe/Moscow или Asia/Novosibirsk."
+	goalProgressReply = "Сегодня повторено слов: %v из %v.\n"
+	noGoalReply       = "Сегодня повторено слов: %v. Цель на день не задана.\n"
+	streakReply       = "Дней подряд: %v\n\n"
+	goalUsageReply    = "Чтобы задать цель, отправьте количество повторений в день, например:\n" +
+		"/goal 20\n" +
+		"Убрать цель – /goal off"
+	langReply      = "Сейчас бот ищет слова в паре %s.\n\n"
+	langUsageReply = "Чтобы сменить языковую пару, выберите её ниже или отправьте, например:\n" +
+		"/lang de-ru"
+	unsupportedLangPairReply = "Бот пока не умеет работать с такой языковой парой. " +
+		"Отправьте /lang, чтобы увидеть доступные пары."
+	langSetReply    = "Готово! Теперь бот ищет слова в паре %s."
+	decksReply      = "Ваши словари:\n"
+	activeDeckMark  = " (текущий)"
+	decksUsageReply = "\nСоздать новый словарь – /newdeck название\n" +
+		"Переключиться на другой – /usedeck название"
+	deckNameUsageReply    = "Укажите название словаря не длиннее %v символов, например:\n%s Глаголы"
+	deckCreatedReply      = "Словарь «%s» создан. Теперь новые слова будут добавляться в него."
+	deckExistsReply       = "Словарь «%s» уже есть. Переключиться на него – /usedeck %s"
+	deckSwitchedReply     = "Теперь вы работаете со словарём «%s»."
+	deckNotFoundReply     = "Словаря «%s» нет. Список ваших словарей – /decks"
+	dailyGoalReachedReply = "Поздравляем! Цель на сегодня выполнена: %v повторений.\nДней подряд: %v"
+	reminderReply         = "Пора повторить слова! Ждут повторения: %v"
+	statsReply            = "Слов в словаре: %v\n" +
+		"Добавлено за неделю: %v\n\n" +
+		"Повторений сегодня: %v\n" +
+		"Повторений за 7 дней: %v\n" +
+		"Повторений за 30 дней: %v\n" +
+		"Доля вспомненных слов за 30 дней: %v%%\n\n" +
+		"Дней подряд: %v"
 
 	showFullDescButton    = "Все варианты перевода"
 	addToVocabButton      = "Добавить в словарь"
 	removeFromVocabButton = "Удалить из словаря"
 	yesButton             = "Да"
 	noButton              = "Нет"
-	newWordButton         = "Новое слово"
+	repeatGoodButton      = "Вспомнил, новое слово"
 	showAnswerButton      = "Показать перевод"
+	gradeAgainButton      = "Не вспомнил"
+	gradeHardButton       = "С трудом"
+	gradeGoodButton       = "Вспомнил"
+	gradeEasyButton       = "Легко"
+	gradedMark            = "✓ "
+	stopButton            = "Закончить"
+	forwardQuizButton     = "Перевод слов"
+	reverseQuizButton     = "Слова по переводу"
+	nextQuestionButton    = "Следующий вопрос"
+	startReviewButton     = "Начать повторение"
+	prevPageButton        = "← Назад"
+	nextPageButton        = "Вперёд →"
+	backToListButton      = "К списку"
+	addAllListedButton    = "Добавить все найденные"
+	notInVocabButtonMark  = "+ "
+	addSelectedButton     = "Добавить отмеченные (%v)"
+	checkedMark           = "☑ "
+	uncheckedMark         = "☐ "
+	newLevelHeader        = "Новые"
+	learningLevelHeader   = "Изучаются"
+	familiarLevelHeader   = "Почти выучены"
+	learnedLevelHeader    = "Выучены"
+
+	choiceOptionsQnt  = 4
+	maxCandidatesQnt  = 8
+	quizSessionSize   = 10
+	maxDailyGoal      = 1000
+	maxDeckNameLen    = 32
+	listPageSize      = 10
+	listRowSize       = 2
+	maxFindQueryLen   = 50
+	maxSearchQueries  = 1000
+	maxListedWordsQnt = 20
+	maxLookupWorkers  = 5
+	maxExtractedQnt   = 60
+	extractPageSize   = 12
+	maxImportFileSize = 512 * 1024
+	maxImportRowsQnt  = 1000
+	maxImportWorkers  = 5
+	// maxImportNotFoundShown is a number of not found words listed in the import summary.
+	maxImportNotFoundShown = 30
+
+	importDownloadTimeout  = 30 * time.Second
+	importProgressInterval = 3 * time.Second
+
+	remindersCheckInterval = time.Minute
+	// maxCallbackDataLen is a limit of callback data length set by telegram bot API.
+	maxCallbackDataLen = 64
+	// inVocabChosen is Chosen of the toggle callback of the listed word which is already in the vocab.
+	inVocabChosen = 1
 )
 
 type CallbackCommand int
@@ -49,4 +239,31 @@ const (
 	repeatCallbackCmd
 	continueQuizCallbackCmd
 	showAnswerCallbackCmd
+	continueQuizAfterAnswerCallbackCmd
+	gradeAgainCallbackCmd
+	gradeHardCallbackCmd
+	gradeGoodCallbackCmd
+	gradeEasyCallbackCmd
+	noopCallbackCmd
+	typeQuizGiveUpCallbackCmd
+	typeQuizStopCallbackCmd
+	startQuizCallbackCmd
+	choiceAnswerCallbackCmd
+	nextChoiceCallbackCmd
+	stopQuizCallbackCmd
+	startReminderQuizCallbackCmd
+	setLangCallbackCmd
+	addCandidateCallbackCmd
+	listPageCallbackCmd
+	listEntryCallbackCmd
+	rmFromListCallbackCmd
+	findPageCallbackCmd
+	findEntryCallbackCmd
+	rmFromFindCallbackCmd
+	toggleListedWordCallbackCmd
+	addAllListedCallbackCmd
+	extractToggleCallbackCmd
+	extractPageCallbackCmd
+	extractAddCallbackCmd
+	exportCallbackCmd
 )
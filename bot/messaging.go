@@ -3,8 +3,11 @@ package bot
 import (
 	"encoding/json"
 	"fmt"
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"github.com/dmalyar/pimpmyvocab/export"
 	"github.com/dmalyar/pimpmyvocab/log"
 	"github.com/go-telegram-bot-api/telegram-bot-api"
+	"strings"
 )
 
 type message struct {
@@ -13,34 +16,60 @@ type message struct {
 	userID   int
 	userName string
 	text     string
+	document *tgbotapi.Document
 }
 
 func (m *message) String() string {
+	if m.document != nil {
+		return fmt.Sprintf("id: %v; chatID: %v; userID: %v; userName: %v; document: %s",
+			m.id, m.chatID, m.userID, m.userName, m.document.FileName)
+	}
 	return fmt.Sprintf("id: %v; chatID: %v; userID: %v; userName: %v; text: %s",
 		m.id, m.chatID, m.userID, m.userName, m.text)
 }
 
+// callbackMessage is a callback from a message keyboard.
+// QuotedText is the text of the message quoted by the message with the keyboard.
 type callbackMessage struct {
-	id       string
-	msgID    int
-	chatID   int64
-	userID   int
-	userName string
-	data     *CallbackData
+	id         string
+	msgID      int
+	chatID     int64
+	userID     int
+	userName   string
+	quotedText string
+	data       *CallbackData
 }
 
 func (m *callbackMessage) String() string {
-	return fmt.Sprintf("id: %s; msgID: %v; chatID: %v; userID: %v; userName: %v; data: {%s}",
-		m.id, m.msgID, m.chatID, m.userID, m.userName, m.data)
+	return fmt.Sprintf("id: %s; msgID: %v; chatID: %v; userID: %v; userName: %v; quotedText: %s; data: {%s}",
+		m.id, m.msgID, m.chatID, m.userID, m.userName, m.quotedText, m.data)
 }
 
 type CallbackData struct {
-	Command CallbackCommand
-	EntryID int
+	Command   CallbackCommand
+	EntryID   int
+	Direction domain.Direction `json:",omitempty"`
+	Chosen    int              `json:",omitempty"`
+	LangPair  string           `json:",omitempty"`
+	// Ref is the ID of the state kept by the bot for the message, e.g. the search query or the extraction.
+	Ref int `json:",omitempty"`
 }
 
 func (c *CallbackData) String() string {
-	return fmt.Sprintf("Command: %v; EntryID: %v", c.Command, c.EntryID)
+	return fmt.Sprintf("Command: %v; EntryID: %v; Direction: %s; Chosen: %v; LangPair: %s; Ref: %v",
+		c.Command, c.EntryID, c.Direction, c.Chosen, c.LangPair, c.Ref)
+}
+
+// marshalCallbackData returns callback data json checking that it fits telegram bot API limit.
+func marshalCallbackData(data CallbackData) (string, error) {
+	callback, err := json.Marshal(data)
+	if err != nil {
+		return "", err
+	}
+	if len(callback) > maxCallbackDataLen {
+		return "", fmt.Errorf("callback data %s is longer than %v bytes", callback, maxCallbackDataLen)
+	}
+	return string(callback), nil
 }
 
 func (b *Bot) send(logger log.Logger, msg tgbotapi.Chattable) {
@@ -53,6 +82,19 @@ func (b *Bot) send(logger log.Logger, msg tgbotapi.Chattable) {
 	logger.Debug("Message sent")
 }
 
+// sendWithID sends the message and returns the ID of the sent message.
+// Returns 0 if the message wasn't sent.
+func (b *Bot) sendWithID(logger log.Logger, msg tgbotapi.Chattable) int {
+	logger = logger.WithField("msgToSend", msg)
+	sent, err := b.api.Send(msg)
+	if err != nil {
+		logger.Errorf("Error sending message: %s", err)
+		return 0
+	}
+	logger.Debug("Message sent")
+	return sent.MessageID
+}
+
 func (b *Bot) answerCallback(logger log.Logger, id string) {
 	_, err := b.api.AnswerCallbackQuery(tgbotapi.NewCallback(id, ""))
 	if err != nil {
@@ -93,22 +135,22 @@ func (m *replyMsg) withShortDescKeyboard(logger log.Logger, entryID int, inVocab
 }
 
 func (m *replyMsg) withClearConfirmationKeyboard(logger log.Logger) *replyMsg {
-	clearVocabAcceptCallback, err := json.Marshal(CallbackData{Command: clearVocabAcceptCallbackCmd})
+	clearVocabAcceptCallback, err := marshalCallbackData(CallbackData{Command: clearVocabAcceptCallbackCmd})
 	if err != nil {
 		logger.Errorf("Error generating clear confirmation keyboard: %s", err)
 		return m
 	}
-	clearVocabDeclineCallback, err := json.Marshal(CallbackData{Command: clearVocabDeclineCallbackCmd})
+	clearVocabDeclineCallback, err := marshalCallbackData(CallbackData{Command: clearVocabDeclineCallbackCmd})
 	if err != nil {
 		logger.Errorf("Error generating clear confirmation keyboard: %s", err)
 		return m
 	}
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(yesButton, string(clearVocabAcceptCallback)),
+			tgbotapi.NewInlineKeyboardButtonData(yesButton, clearVocabAcceptCallback),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(noButton, string(clearVocabDeclineCallback)),
+			tgbotapi.NewInlineKeyboardButtonData(noButton, clearVocabDeclineCallback),
 		),
 	)
 	m.ReplyMarkup = keyboard
@@ -117,7 +159,7 @@ func (m *replyMsg) withClearConfirmationKeyboard(logger log.Logger) *replyMsg {
 }
 
 func (m *replyMsg) withRepeatKeyboard(logger log.Logger, entryID int) *replyMsg {
-	callback, err := json.Marshal(CallbackData{
+	callback, err := marshalCallbackData(CallbackData{
 		Command: repeatCallbackCmd,
 		EntryID: entryID,
 	})
@@ -127,7 +169,7 @@ func (m *replyMsg) withRepeatKeyboard(logger log.Logger, entryID int) *replyMsg
 	}
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(newWordButton, string(callback)),
+			tgbotapi.NewInlineKeyboardButtonData(repeatGoodButton, callback),
 		),
 	)
 	m.ReplyMarkup = keyboard
@@ -135,18 +177,171 @@ func (m *replyMsg) withRepeatKeyboard(logger log.Logger, entryID int) *replyMsg
 	return m
 }
 
-func (m *replyMsg) withQuizKeyboard(logger log.Logger, entryID int) *replyMsg {
-	continueCallback, err := json.Marshal(CallbackData{
-		Command: continueQuizCallbackCmd,
-		EntryID: entryID,
+func (m *replyMsg) withQuizDirectionKeyboard(logger log.Logger) *replyMsg {
+	forwardCallback, err := marshalCallbackData(CallbackData{
+		Command:   startQuizCallbackCmd,
+		Direction: domain.DirectionForward,
+	})
+	if err != nil {
+		logger.Errorf("Error generating quiz direction keyboard: %s", err)
+		return m
+	}
+	reverseCallback, err := marshalCallbackData(CallbackData{
+		Command:   startQuizCallbackCmd,
+		Direction: domain.DirectionReverse,
 	})
+	if err != nil {
+		logger.Errorf("Error generating quiz direction keyboard: %s", err)
+		return m
+	}
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(forwardQuizButton, forwardCallback),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(reverseQuizButton, reverseCallback),
+		),
+	)
+	m.ReplyMarkup = keyboard
+	m.keyboardFlag = true
+	return m
+}
+
+func (m *replyMsg) withReminderKeyboard(logger log.Logger) *replyMsg {
+	callback, err := marshalCallbackData(CallbackData{Command: startReminderQuizCallbackCmd})
+	if err != nil {
+		logger.Errorf("Error generating reminder keyboard: %s", err)
+		return m
+	}
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(startReviewButton, callback),
+		),
+	)
+	m.ReplyMarkup = keyboard
+	m.keyboardFlag = true
+	return m
+}
+
+func (m *replyMsg) withLangKeyboard(logger log.Logger) *replyMsg {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(domain.SupportedLangPairs))
+	for _, pair := range domain.SupportedLangPairs {
+		callback, err := marshalCallbackData(CallbackData{Command: setLangCallbackCmd, LangPair: pair.String()})
+		if err != nil {
+			logger.Errorf("Error generating language keyboard: %s", err)
+			return m
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(langPairName(pair), callback),
+		))
+	}
+	m.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	m.keyboardFlag = true
+	return m
+}
+
+// withExportKeyboard adds a button for each export format. Chosen of the button callback is the format.
+func (m *replyMsg) withExportKeyboard(logger log.Logger) *replyMsg {
+	row := make([]tgbotapi.InlineKeyboardButton, 0, len(export.Formats))
+	for _, format := range export.Formats {
+		callback, err := marshalCallbackData(CallbackData{Command: exportCallbackCmd, Chosen: int(format)})
+		if err != nil {
+			logger.Errorf("Error generating export keyboard: %s", err)
+			return m
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(format.String(), callback))
+	}
+	m.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(row)
+	m.keyboardFlag = true
+	return m
+}
+
+// withCandidatesKeyboard adds a button for each distinct translation of the entry found by reverse lookup.
+// Chosen of the button callback is the position of the translation.
+func (m *replyMsg) withCandidatesKeyboard(logger log.Logger, entry *domain.VocabEntry) *replyMsg {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	added := make(map[string]struct{})
+	for _, t := range entry.Translations {
+		if len(rows) == maxCandidatesQnt {
+			break
+		}
+		text := strings.ToLower(t.Text)
+		if _, ok := added[text]; ok {
+			continue
+		}
+		added[text] = struct{}{}
+		callback, err := marshalCallbackData(CallbackData{
+			Command: addCandidateCallbackCmd,
+			EntryID: entry.ID,
+			Chosen:  t.Position,
+		})
+		if err != nil {
+			logger.Errorf("Error generating candidates keyboard: %s", err)
+			return m
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(t.Text, callback),
+		))
+	}
+	if len(rows) == 0 {
+		return m
+	}
+	m.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	m.keyboardFlag = true
+	return m
+}
+
+func (m *replyMsg) withEntriesPageKeyboard(
+	logger log.Logger,
+	page *domain.EntriesPage,
+	callbacks pageCallbackCommands,
+	ref int,
+) *replyMsg {
+	keyboard, err := entriesPageKeyboard(page, callbacks, ref)
+	if err != nil {
+		logger.Errorf("Error generating entries page keyboard: %s", err)
+		return m
+	}
+	m.ReplyMarkup = keyboard
+	m.keyboardFlag = true
+	return m
+}
+
+func (m *replyMsg) withWordListKeyboard(logger log.Logger, lookups []*wordLookup) *replyMsg {
+	keyboard, err := wordListKeyboard(lookups)
+	if err != nil {
+		logger.Errorf("Error generating word list keyboard: %s", err)
+		return m
+	}
+	if keyboard == nil {
+		return m
+	}
+	m.ReplyMarkup = keyboard
+	m.keyboardFlag = true
+	return m
+}
+
+func (m *replyMsg) withExtractKeyboard(logger log.Logger, e *extraction, pageNumber int) *replyMsg {
+	keyboard, err := extractKeyboard(e, pageNumber)
+	if err != nil {
+		logger.Errorf("Error generating extract keyboard: %s", err)
+		return m
+	}
+	m.ReplyMarkup = keyboard
+	m.keyboardFlag = true
+	return m
+}
+
+func (m *replyMsg) withQuizKeyboard(logger log.Logger, entryID int, direction domain.Direction) *replyMsg {
+	stopCallback, err := marshalCallbackData(CallbackData{Command: stopQuizCallbackCmd})
 	if err != nil {
 		logger.Errorf("Error generating quiz keyboard: %s", err)
 		return m
 	}
-	showAnswerCallback, err := json.Marshal(CallbackData{
-		Command: showAnswerCallbackCmd,
-		EntryID: entryID,
+	showAnswerCallback, err := marshalCallbackData(CallbackData{
+		Command:   showAnswerCallbackCmd,
+		EntryID:   entryID,
+		Direction: direction,
 	})
 	if err != nil {
 		logger.Errorf("Error generating quiz keyboard: %s", err)
@@ -154,10 +349,10 @@ func (m *replyMsg) withQuizKeyboard(logger log.Logger, entryID int) *replyMsg {
 	}
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(showAnswerButton, string(showAnswerCallback)),
+			tgbotapi.NewInlineKeyboardButtonData(showAnswerButton, showAnswerCallback),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(newWordButton, string(continueCallback)),
+			tgbotapi.NewInlineKeyboardButtonData(stopButton, stopCallback),
 		),
 	)
 	m.ReplyMarkup = keyboard
@@ -165,6 +360,69 @@ func (m *replyMsg) withQuizKeyboard(logger log.Logger, entryID int) *replyMsg {
 	return m
 }
 
+func (m *replyMsg) withTypeQuizKeyboard(logger log.Logger, entryID int) *replyMsg {
+	giveUpCallback, err := marshalCallbackData(CallbackData{
+		Command: typeQuizGiveUpCallbackCmd,
+		EntryID: entryID,
+	})
+	if err != nil {
+		logger.Errorf("Error generating type quiz keyboard: %s", err)
+		return m
+	}
+	stopCallback, err := marshalCallbackData(CallbackData{Command: typeQuizStopCallbackCmd})
+	if err != nil {
+		logger.Errorf("Error generating type quiz keyboard: %s", err)
+		return m
+	}
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(showAnswerButton, giveUpCallback),
+			tgbotapi.NewInlineKeyboardButtonData(stopButton, stopCallback),
+		),
+	)
+	m.ReplyMarkup = keyboard
+	m.keyboardFlag = true
+	return m
+}
+
+func (m *replyMsg) withChoiceKeyboard(logger log.Logger, question *domain.ChoiceQuestion) *replyMsg {
+	rows := make([][]tgbotapi.InlineKeyboardButton, 0, len(question.Options))
+	for _, o := range question.Options {
+		callback, err := marshalCallbackData(CallbackData{
+			Command: choiceAnswerCallbackCmd,
+			EntryID: question.Entry.ID,
+			Chosen:  o.ID,
+		})
+		if err != nil {
+			logger.Errorf("Error generating choice keyboard: %s", err)
+			return m
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(o.MainTranslation, callback),
+		))
+	}
+	m.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	m.keyboardFlag = true
+	return m
+}
+
+type documentMsg struct {
+	*tgbotapi.DocumentConfig
+	name string
+	size int
+}
+
+func (m *documentMsg) String() string {
+	return fmt.Sprintf("New document (name = %s; size = %v): %s", m.name, m.size, m.Caption)
+}
+
+// newDocument returns the message uploading the file with the given content.
+func newDocument(chatID int64, name string, content []byte, caption string) *documentMsg {
+	msg := tgbotapi.NewDocumentUpload(chatID, tgbotapi.FileBytes{Name: name, Bytes: content})
+	msg.Caption = caption
+	return &documentMsg{DocumentConfig: &msg, name: name, size: len(content)}
+}
+
 type editTextMsg struct {
 	*tgbotapi.EditMessageTextConfig
 	keyboardFlag bool
@@ -190,18 +448,109 @@ func (m *editTextMsg) withFullDescKeyboard(logger log.Logger, entryID int, inVoc
 	return m
 }
 
-func (m *editTextMsg) withQuizKeyboard(logger log.Logger, entryID int) *editTextMsg {
-	callback, err := json.Marshal(CallbackData{
-		Command: continueQuizCallbackCmd,
+func (m *editTextMsg) withEntriesPageKeyboard(
+	logger log.Logger,
+	page *domain.EntriesPage,
+	callbacks pageCallbackCommands,
+	ref int,
+) *editTextMsg {
+	keyboard, err := entriesPageKeyboard(page, callbacks, ref)
+	if err != nil {
+		logger.Errorf("Error generating entries page keyboard: %s", err)
+		return m
+	}
+	m.ReplyMarkup = keyboard
+	m.keyboardFlag = true
+	return m
+}
+
+// withEntryCardKeyboard adds buttons to remove the entry opened from the page of entries and to return to the page.
+func (m *editTextMsg) withEntryCardKeyboard(
+	logger log.Logger,
+	entryID, pageNumber int,
+	callbacks pageCallbackCommands,
+	ref int,
+) *editTextMsg {
+	removeCallback, err := marshalCallbackData(CallbackData{
+		Command: callbacks.remove,
 		EntryID: entryID,
+		Chosen:  pageNumber,
+		Ref:     ref,
 	})
 	if err != nil {
-		logger.Errorf("Error generating quiz keyboard: %s", err)
+		logger.Errorf("Error generating entry card keyboard: %s", err)
+		return m
+	}
+	backCallback, err := marshalCallbackData(CallbackData{
+		Command: callbacks.page,
+		Chosen:  pageNumber,
+		Ref:     ref,
+	})
+	if err != nil {
+		logger.Errorf("Error generating entry card keyboard: %s", err)
+		return m
+	}
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(removeFromVocabButton, removeCallback),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(backToListButton, backCallback),
+		),
+	)
+	m.ReplyMarkup = &keyboard
+	m.keyboardFlag = true
+	return m
+}
+
+func (m *editTextMsg) withWordListKeyboard(logger log.Logger, lookups []*wordLookup) *editTextMsg {
+	keyboard, err := wordListKeyboard(lookups)
+	if err != nil {
+		logger.Errorf("Error generating word list keyboard: %s", err)
+		return m
+	}
+	if keyboard == nil {
+		return m
+	}
+	m.ReplyMarkup = keyboard
+	m.keyboardFlag = true
+	return m
+}
+
+func (m *editTextMsg) withExtractKeyboard(logger log.Logger, e *extraction, pageNumber int) *editTextMsg {
+	keyboard, err := extractKeyboard(e, pageNumber)
+	if err != nil {
+		logger.Errorf("Error generating extract keyboard: %s", err)
+		return m
+	}
+	m.ReplyMarkup = keyboard
+	m.keyboardFlag = true
+	return m
+}
+
+func (m *editTextMsg) withGradeKeyboard(logger log.Logger, entryID int, direction domain.Direction) *editTextMsg {
+	keyboard, err := gradeKeyboard(entryID, direction)
+	if err != nil {
+		logger.Errorf("Error generating grade keyboard: %s", err)
+		return m
+	}
+	m.ReplyMarkup = keyboard
+	m.keyboardFlag = true
+	return m
+}
+
+func (m *editTextMsg) withNextChoiceKeyboard(logger log.Logger, entryID int) *editTextMsg {
+	callback, err := marshalCallbackData(CallbackData{
+		Command: nextChoiceCallbackCmd,
+		EntryID: entryID,
+	})
+	if err != nil {
+		logger.Errorf("Error generating next choice keyboard: %s", err)
 		return m
 	}
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(newWordButton, string(callback)),
+			tgbotapi.NewInlineKeyboardButtonData(nextQuestionButton, callback),
 		),
 	)
 	m.ReplyMarkup = &keyboard
@@ -239,19 +588,40 @@ func newEditKeyboardMsgFullDesc(logger log.Logger, chatID int64, msgID, entryID
 	return &editKeyboardMsg{EditMessageReplyMarkupConfig: &msg}
 }
 
+func newEditKeyboardMsgRemoved(chatID int64, msgID int) *editKeyboardMsg {
+	msg := tgbotapi.NewEditMessageReplyMarkup(chatID, msgID, tgbotapi.NewInlineKeyboardMarkup())
+	return &editKeyboardMsg{EditMessageReplyMarkupConfig: &msg}
+}
+
+func newEditKeyboardMsgGraded(logger log.Logger, chatID int64, msgID int, grade domain.Grade) *editKeyboardMsg {
+	callback, err := marshalCallbackData(CallbackData{Command: noopCallbackCmd})
+	if err != nil {
+		logger.Errorf("Error generating graded keyboard: %s", err)
+		msg := tgbotapi.NewEditMessageReplyMarkup(chatID, msgID, tgbotapi.InlineKeyboardMarkup{})
+		return &editKeyboardMsg{EditMessageReplyMarkupConfig: &msg}
+	}
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(gradedMark+gradeButtons[grade], callback),
+		),
+	)
+	msg := tgbotapi.NewEditMessageReplyMarkup(chatID, msgID, keyboard)
+	return &editKeyboardMsg{EditMessageReplyMarkupConfig: &msg}
+}
+
 func shortDescKeyboard(entryID int, inVocab bool) (*tgbotapi.InlineKeyboardMarkup, error) {
 	var vocabActionButton string
-	var vocabActionCallback []byte
+	var vocabActionCallback string
 	var err error
 	if inVocab {
 		vocabActionButton = removeFromVocabButton
-		vocabActionCallback, err = json.Marshal(CallbackData{
+		vocabActionCallback, err = marshalCallbackData(CallbackData{
 			EntryID: entryID,
 			Command: rmFromVocabCallbackCmd,
 		})
 	} else {
 		vocabActionButton = addToVocabButton
-		vocabActionCallback, err = json.Marshal(CallbackData{
+		vocabActionCallback, err = marshalCallbackData(CallbackData{
 			EntryID: entryID,
 			Command: addToVocabCallbackCmd,
 		})
@@ -259,7 +629,7 @@ func shortDescKeyboard(entryID int, inVocab bool) (*tgbotapi.InlineKeyboardMarku
 	if err != nil {
 		return nil, fmt.Errorf("error marshalling vocab action callback json for short desc keyboard: %s", err)
 	}
-	showFullDescCallback, err := json.Marshal(CallbackData{
+	showFullDescCallback, err := marshalCallbackData(CallbackData{
 		EntryID: entryID,
 		Command: showFullDescCallbackCmd,
 	})
@@ -268,10 +638,10 @@ func shortDescKeyboard(entryID int, inVocab bool) (*tgbotapi.InlineKeyboardMarku
 	}
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(showFullDescButton, string(showFullDescCallback)),
+			tgbotapi.NewInlineKeyboardButtonData(showFullDescButton, showFullDescCallback),
 		),
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(vocabActionButton, string(vocabActionCallback)),
+			tgbotapi.NewInlineKeyboardButtonData(vocabActionButton, vocabActionCallback),
 		),
 	)
 	return &keyboard, nil
@@ -279,17 +649,17 @@ func shortDescKeyboard(entryID int, inVocab bool) (*tgbotapi.InlineKeyboardMarku
 
 func fullDescKeyboard(entryID int, inVocab bool) (*tgbotapi.InlineKeyboardMarkup, error) {
 	var vocabActionButton string
-	var vocabActionCallback []byte
+	var vocabActionCallback string
 	var err error
 	if inVocab {
 		vocabActionButton = removeFromVocabButton
-		vocabActionCallback, err = json.Marshal(CallbackData{
+		vocabActionCallback, err = marshalCallbackData(CallbackData{
 			EntryID: entryID,
 			Command: rmFromVocabFullDescCallbackCmd,
 		})
 	} else {
 		vocabActionButton = addToVocabButton
-		vocabActionCallback, err = json.Marshal(CallbackData{
+		vocabActionCallback, err = marshalCallbackData(CallbackData{
 			EntryID: entryID,
 			Command: addToVocabFullDescCallbackCmd,
 		})
@@ -299,8 +669,263 @@ func fullDescKeyboard(entryID int, inVocab bool) (*tgbotapi.InlineKeyboardMarkup
 	}
 	keyboard := tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData(vocabActionButton, string(vocabActionCallback)),
+			tgbotapi.NewInlineKeyboardButtonData(vocabActionButton, vocabActionCallback),
 		),
 	)
 	return &keyboard, nil
 }
+
+// pageCallbackCommands are the callback commands of the page of entries keyboard
+// and of the card of the entry opened from the page.
+type pageCallbackCommands struct {
+	page   CallbackCommand
+	entry  CallbackCommand
+	remove CallbackCommand
+}
+
+var listCallbacks = pageCallbackCommands{
+	page:   listPageCallbackCmd,
+	entry:  listEntryCallbackCmd,
+	remove: rmFromListCallbackCmd,
+}
+
+var findCallbacks = pageCallbackCommands{
+	page:   findPageCallbackCmd,
+	entry:  findEntryCallbackCmd,
+	remove: rmFromFindCallbackCmd,
+}
+
+// pageCallbacks returns the callback commands of the search results page if search is true
+// and of the vocab list page otherwise.
+func pageCallbacks(search bool) pageCallbackCommands {
+	if search {
+		return findCallbacks
+	}
+	return listCallbacks
+}
+
+// entriesPageKeyboard returns a button for each entry of the page followed by the buttons to switch pages.
+// Chosen of the callbacks is the page number, ref is the ID of the search query or zero for the vocab list.
+func entriesPageKeyboard(
+	page *domain.EntriesPage,
+	callbacks pageCallbackCommands,
+	ref int,
+) (*tgbotapi.InlineKeyboardMarkup, error) {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for _, entry := range page.Entries {
+		callback, err := marshalCallbackData(CallbackData{
+			Command: callbacks.entry,
+			EntryID: entry.ID,
+			Chosen:  page.Number,
+			Ref:     ref,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("marshalling entry callback json for entries page keyboard: %s", err)
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(entry.Text, callback))
+		if len(row) == listRowSize {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) != 0 {
+		rows = append(rows, row)
+	}
+	var navRow []tgbotapi.InlineKeyboardButton
+	if page.HasPrev() {
+		callback, err := marshalCallbackData(CallbackData{Command: callbacks.page, Chosen: page.Number - 1, Ref: ref})
+		if err != nil {
+			return nil, fmt.Errorf("marshalling prev page callback json for entries page keyboard: %s", err)
+		}
+		navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData(prevPageButton, callback))
+	}
+	if page.HasNext() {
+		callback, err := marshalCallbackData(CallbackData{Command: callbacks.page, Chosen: page.Number + 1, Ref: ref})
+		if err != nil {
+			return nil, fmt.Errorf("marshalling next page callback json for entries page keyboard: %s", err)
+		}
+		navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData(nextPageButton, callback))
+	}
+	if len(navRow) != 0 {
+		rows = append(rows, navRow)
+	}
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return &keyboard, nil
+}
+
+// wordListKeyboard returns a button toggling presence in the vocab for each found word
+// followed by the button adding all found words which are not in the vocab.
+// Chosen of the toggle callback is inVocabChosen if the word is in the vocab.
+// Returns nil if no words were found.
+func wordListKeyboard(lookups []*wordLookup) (*tgbotapi.InlineKeyboardMarkup, error) {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	var notInVocab bool
+	for _, l := range lookups {
+		if l.entry == nil {
+			continue
+		}
+		data := CallbackData{Command: toggleListedWordCallbackCmd, EntryID: l.entry.ID}
+		label := notInVocabButtonMark + l.entry.Text
+		if l.inVocab {
+			data.Chosen = inVocabChosen
+			label = gradedMark + l.entry.Text
+		} else {
+			notInVocab = true
+		}
+		callback, err := marshalCallbackData(data)
+		if err != nil {
+			return nil, fmt.Errorf("marshalling toggle callback json for word list keyboard: %s", err)
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(label, callback))
+		if len(row) == listRowSize {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) != 0 {
+		rows = append(rows, row)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+	if notInVocab {
+		callback, err := marshalCallbackData(CallbackData{Command: addAllListedCallbackCmd})
+		if err != nil {
+			return nil, fmt.Errorf("marshalling add all callback json for word list keyboard: %s", err)
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(addAllListedButton, callback),
+		))
+	}
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return &keyboard, nil
+}
+
+// extractKeyboard returns a checklist button for each word of the page followed by the buttons to switch pages
+// and the button adding the checked words. Chosen of the checklist callback is the index of the word,
+// Ref of all the callbacks is the ID of the extraction.
+func extractKeyboard(e *extraction, pageNumber int) (*tgbotapi.InlineKeyboardMarkup, error) {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for i := pageNumber * extractPageSize; i < len(e.words) && i < (pageNumber+1)*extractPageSize; i++ {
+		callback, err := marshalCallbackData(CallbackData{Command: extractToggleCallbackCmd, Chosen: i, Ref: e.id})
+		if err != nil {
+			return nil, fmt.Errorf("marshalling toggle callback json for extract keyboard: %s", err)
+		}
+		label := uncheckedMark + e.words[i]
+		if e.selected[i] {
+			label = checkedMark + e.words[i]
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(label, callback))
+		if len(row) == listRowSize {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) != 0 {
+		rows = append(rows, row)
+	}
+	var navRow []tgbotapi.InlineKeyboardButton
+	if pageNumber > 0 {
+		callback, err := marshalCallbackData(CallbackData{
+			Command: extractPageCallbackCmd,
+			Chosen:  pageNumber - 1,
+			Ref:     e.id,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("marshalling prev page callback json for extract keyboard: %s", err)
+		}
+		navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData(prevPageButton, callback))
+	}
+	if pageNumber < extractPagesQnt(e)-1 {
+		callback, err := marshalCallbackData(CallbackData{
+			Command: extractPageCallbackCmd,
+			Chosen:  pageNumber + 1,
+			Ref:     e.id,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("marshalling next page callback json for extract keyboard: %s", err)
+		}
+		navRow = append(navRow, tgbotapi.NewInlineKeyboardButtonData(nextPageButton, callback))
+	}
+	if len(navRow) != 0 {
+		rows = append(rows, navRow)
+	}
+	addCallback, err := marshalCallbackData(CallbackData{Command: extractAddCallbackCmd, Ref: e.id})
+	if err != nil {
+		return nil, fmt.Errorf("marshalling add callback json for extract keyboard: %s", err)
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(fmt.Sprintf(addSelectedButton, len(e.selectedWords())), addCallback),
+	))
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(rows...)
+	return &keyboard, nil
+}
+
+var gradeButtons = map[domain.Grade]string{
+	domain.GradeAgain: gradeAgainButton,
+	domain.GradeHard:  gradeHardButton,
+	domain.GradeGood:  gradeGoodButton,
+	domain.GradeEasy:  gradeEasyButton,
+}
+
+var quizDirectionButtons = map[domain.Direction]string{
+	domain.DirectionForward: forwardQuizButton,
+	domain.DirectionReverse: reverseQuizButton,
+}
+
+var gradeCallbackCommands = map[domain.Grade]CallbackCommand{
+	domain.GradeAgain: gradeAgainCallbackCmd,
+	domain.GradeHard:  gradeHardCallbackCmd,
+	domain.GradeGood:  gradeGoodCallbackCmd,
+	domain.GradeEasy:  gradeEasyCallbackCmd,
+}
+
+func gradeKeyboard(entryID int, direction domain.Direction) (*tgbotapi.InlineKeyboardMarkup, error) {
+	row := make([]tgbotapi.InlineKeyboardButton, 0, len(gradeButtons))
+	for _, grade := range []domain.Grade{domain.GradeAgain, domain.GradeHard, domain.GradeGood, domain.GradeEasy} {
+		callback, err := marshalCallbackData(CallbackData{
+			EntryID:   entryID,
+			Command:   gradeCallbackCommands[grade],
+			Direction: direction,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("marshalling callback json for grade keyboard: %s", err)
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(gradeButtons[grade], callback))
+	}
+	keyboard := tgbotapi.NewInlineKeyboardMarkup(row)
+	return &keyboard, nil
+}
+
+var masteryLevelHeaders = map[domain.MasteryLevel]string{
+	domain.MasteryNew:      newLevelHeader,
+	domain.MasteryLearning: learningLevelHeader,
+	domain.MasteryFamiliar: familiarLevelHeader,
+	domain.MasteryLearned:  learnedLevelHeader,
+}
+
+var langNames = map[string]string{
+	"en": "английский",
+	"de": "немецкий",
+	"fr": "французский",
+	"es": "испанский",
+	"it": "итальянский",
+	"ru": "русский",
+	"uk": "украинский",
+}
+
+// langPairName returns a human readable name of the language pair, e.g. "английский → русский".
+func langPairName(pair domain.LangPair) string {
+	source, ok := langNames[pair.Source]
+	if !ok {
+		source = pair.Source
+	}
+	target, ok := langNames[pair.Target]
+	if !ok {
+		target = pair.Target
+	}
+	return source + " → " + target
+}
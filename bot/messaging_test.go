@@ -0,0 +1,87 @@
+package bot
+
+import (
+	"encoding/json"
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestMarshalCallbackData(t *testing.T) {
+	testCases := []struct {
+		name      string
+		data      CallbackData
+		expectErr bool
+	}{
+		{
+			name: "Command only",
+			data: CallbackData{Command: stopQuizCallbackCmd},
+		},
+		{
+			name: "Grade of the entry",
+			data: CallbackData{
+				Command:   gradeEasyCallbackCmd,
+				EntryID:   math.MaxInt32,
+				Direction: domain.DirectionReverse,
+			},
+		},
+		{
+			name: "Entry of the page",
+			data: CallbackData{
+				Command: extractAddCallbackCmd,
+				EntryID: math.MaxInt32,
+				Chosen:  math.MaxInt32,
+			},
+		},
+		{
+			name: "Entry of the search results page",
+			data: CallbackData{
+				Command: rmFromFindCallbackCmd,
+				EntryID: math.MaxInt32,
+				Chosen:  99,
+				Ref:     math.MaxInt32,
+			},
+		},
+		{
+			name: "Language pair",
+			data: CallbackData{
+				Command:  setLangCallbackCmd,
+				LangPair: domain.DefaultLangPair.String(),
+			},
+		},
+		{
+			name: "Longer than 64 bytes",
+			data: CallbackData{
+				Command:  setLangCallbackCmd,
+				EntryID:  math.MaxInt32,
+				LangPair: "english-russian-english-russian",
+			},
+			expectErr: true,
+		},
+	}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			callback, err := marshalCallbackData(c.data)
+			if c.expectErr {
+				if err == nil {
+					t.Errorf("Expected error, but got callback %s", callback)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Expected no error, but got %s", err)
+			}
+			if len(callback) > maxCallbackDataLen {
+				t.Errorf("Expected callback length <= %v;Actual:%v", maxCallbackDataLen, len(callback))
+			}
+			actual := CallbackData{}
+			if err = json.Unmarshal([]byte(callback), &actual); err != nil {
+				t.Fatalf("Expected no error, but got %s", err)
+			}
+			if !reflect.DeepEqual(c.data, actual) {
+				t.Errorf("Expected data:%v;Actual:%v", &c.data, &actual)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package bot
+
+import (
+	"fmt"
+	"sync"
+)
+
+type chatMode int
+
+const (
+	idleMode chatMode = iota
+	typeQuizMode
+	extractMode
+)
+
+// chatState keeps what the bot expects from the chat in the next message.
+type chatState struct {
+	mode    chatMode
+	entryID int
+}
+
+func (s *chatState) String() string {
+	return fmt.Sprintf("mode: %v; entryID: %v", s.mode, s.entryID)
+}
+
+// chatStates is a concurrent safe storage of chat states.
+// Chats without stored state are considered to be in idle mode.
+type chatStates struct {
+	mu     sync.Mutex
+	states map[int64]chatState
+}
+
+func newChatStates() *chatStates {
+	return &chatStates{
+		states: make(map[int64]chatState),
+	}
+}
+
+func (s *chatStates) get(chatID int64) chatState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.states[chatID]
+}
+
+func (s *chatStates) set(chatID int64, state chatState) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.states[chatID] = state
+}
+
+func (s *chatStates) reset(chatID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.states, chatID)
+}
+
+// take resets the chat state if it's still the given one, so the answer to the question is processed only once.
+// Returns false if the state has already been taken or changed.
+func (s *chatStates) take(chatID int64, state chatState) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current, ok := s.states[chatID]
+	if !ok || current != state {
+		return false
+	}
+	delete(s.states, chatID)
+	return true
+}
+
+// searchQueries is a concurrent safe storage of the recent /find queries.
+// Search results are paged using the stored query since it doesn't fit into callback data,
+// the callbacks keep the ID of the query instead, so each results message pages its own query.
+// The oldest queries are evicted when there are more than maxSearchQueries of them.
+type searchQueries struct {
+	mu      sync.Mutex
+	lastID  int
+	queries map[int]searchQuery
+	ids     []int
+}
+
+type searchQuery struct {
+	chatID int64
+	text   string
+}
+
+func newSearchQueries() *searchQueries {
+	return &searchQueries{
+		queries: make(map[int]searchQuery),
+	}
+}
+
+// get returns the query with the given ID made in the chat. Returns empty string if the query is evicted.
+func (s *searchQueries) get(chatID int64, id int) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	q, ok := s.queries[id]
+	if !ok || q.chatID != chatID {
+		return ""
+	}
+	return q.text
+}
+
+// add stores the query made in the chat and returns its ID.
+func (s *searchQueries) add(chatID int64, query string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastID++
+	s.queries[s.lastID] = searchQuery{chatID: chatID, text: query}
+	s.ids = append(s.ids, s.lastID)
+	if len(s.ids) > maxSearchQueries {
+		delete(s.queries, s.ids[0])
+		s.ids = s.ids[1:]
+	}
+	return s.lastID
+}
+
+// choiceQuestions is a concurrent safe storage of the entry of the latest unanswered choice question of each chat.
+// The answer is reviewed only once even if the user taps the options several times.
+type choiceQuestions struct {
+	mu      sync.Mutex
+	entries map[int64]int
+}
+
+func newChoiceQuestions() *choiceQuestions {
+	return &choiceQuestions{
+		entries: make(map[int64]int),
+	}
+}
+
+func (s *choiceQuestions) set(chatID int64, entryID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[chatID] = entryID
+}
+
+// take removes the question of the chat if it's about the given entry.
+// Returns false if the question is already answered or isn't the latest one.
+func (s *choiceQuestions) take(chatID int64, entryID int) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	pending, ok := s.entries[chatID]
+	if !ok || pending != entryID {
+		return false
+	}
+	delete(s.entries, chatID)
+	return true
+}
+
+// extraction is a checklist of the words extracted from the text the user sent.
+// Selected keeps the checklist marks, it has the same length as words.
+// ID is referenced from the callbacks of the checklist message, so the buttons of older checklists are rejected.
+type extraction struct {
+	id       int
+	words    []string
+	selected []bool
+}
+
+// selectedWords returns the checked words in the checklist order.
+func (e *extraction) selectedWords() []string {
+	var words []string
+	for i, w := range e.words {
+		if e.selected[i] {
+			words = append(words, w)
+		}
+	}
+	return words
+}
+
+// extractions is a concurrent safe storage of the latest extraction of each chat.
+// Checklists don't fit into callback data so they are kept by the bot until the words are added.
+type extractions struct {
+	mu          sync.Mutex
+	lastID      int
+	extractions map[int64]*extraction
+}
+
+func newExtractions() *extractions {
+	return &extractions{
+		extractions: make(map[int64]*extraction),
+	}
+}
+
+// get returns a copy of the chat extraction with the given ID.
+// Returns false if the chat has none or the latest extraction of the chat has another ID.
+func (s *extractions) get(chatID int64, id int) (extraction, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.extractions[chatID]
+	if !ok || e.id != id {
+		return extraction{}, false
+	}
+	return e.copy(), true
+}
+
+// set replaces the chat extraction with the new one of the given words and returns its copy.
+func (s *extractions) set(chatID int64, words []string) extraction {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.lastID++
+	e := &extraction{
+		id:       s.lastID,
+		words:    words,
+		selected: make([]bool, len(words)),
+	}
+	s.extractions[chatID] = e
+	return e.copy()
+}
+
+// toggle switches the checklist mark of the word with the given index and returns a copy of the chat extraction.
+// Returns false if the chat has no extraction with the given ID or the index is out of range.
+func (s *extractions) toggle(chatID int64, id, i int) (extraction, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	e, ok := s.extractions[chatID]
+	if !ok || e.id != id || i < 0 || i >= len(e.words) {
+		return extraction{}, false
+	}
+	e.selected[i] = !e.selected[i]
+	return e.copy(), true
+}
+
+// reset removes the chat extraction if it has the given ID.
+func (s *extractions) reset(chatID int64, id int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if e, ok := s.extractions[chatID]; ok && e.id == id {
+		delete(s.extractions, chatID)
+	}
+}
+
+func (e *extraction) copy() extraction {
+	selected := make([]bool, len(e.selected))
+	copy(selected, e.selected)
+	return extraction{id: e.id, words: e.words, selected: selected}
+}
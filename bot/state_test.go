@@ -0,0 +1,82 @@
+package bot
+
+import "testing"
+
+func TestSearchQueries(t *testing.T) {
+	queries := newSearchQueries()
+	first := queries.add(1, "verb")
+	second := queries.add(1, "noun")
+	other := queries.add(2, "verb")
+	if first == second || second == other {
+		t.Errorf("Expected unique IDs;Actual:%v, %v, %v", first, second, other)
+	}
+	if q := queries.get(1, first); q != "verb" {
+		t.Errorf("Expected query:%q;Actual:%q", "verb", q)
+	}
+	if q := queries.get(1, second); q != "noun" {
+		t.Errorf("Expected query:%q;Actual:%q", "noun", q)
+	}
+	if q := queries.get(1, other); q != "" {
+		t.Errorf("Expected no query of other chat;Actual:%q", q)
+	}
+	for i := 0; i < maxSearchQueries; i++ {
+		queries.add(3, "adj")
+	}
+	if q := queries.get(1, first); q != "" {
+		t.Errorf("Expected the oldest query to be evicted;Actual:%q", q)
+	}
+	if len(queries.queries) != maxSearchQueries {
+		t.Errorf("Expected stored queries:%v;Actual:%v", maxSearchQueries, len(queries.queries))
+	}
+}
+
+func TestChatStates_Take(t *testing.T) {
+	states := newChatStates()
+	question := chatState{mode: typeQuizMode, entryID: 1}
+	states.set(1, question)
+	if states.take(1, chatState{mode: typeQuizMode, entryID: 2}) {
+		t.Errorf("Expected the state of other entry not to be taken")
+	}
+	if !states.take(1, question) {
+		t.Errorf("Expected the state to be taken")
+	}
+	if states.take(1, question) {
+		t.Errorf("Expected the state to be taken only once")
+	}
+	if state := states.get(1); state.mode != idleMode {
+		t.Errorf("Expected mode:%v;Actual:%v", idleMode, state.mode)
+	}
+}
+
+func TestExtractions(t *testing.T) {
+	extractions := newExtractions()
+	old := extractions.set(1, []string{"verb", "noun"})
+	latest := extractions.set(1, []string{"adj"})
+	if old.id == latest.id {
+		t.Errorf("Expected unique IDs;Actual:%v, %v", old.id, latest.id)
+	}
+	if _, ok := extractions.get(1, old.id); ok {
+		t.Error("Expected the replaced extraction to be outdated")
+	}
+	if _, ok := extractions.toggle(1, old.id, 0); ok {
+		t.Error("Expected toggle of the replaced extraction to be rejected")
+	}
+	e, ok := extractions.toggle(1, latest.id, 0)
+	if !ok {
+		t.Fatal("Expected toggle of the latest extraction")
+	}
+	if words := e.selectedWords(); len(words) != 1 || words[0] != "adj" {
+		t.Errorf("Expected selected words:[adj];Actual:%v", words)
+	}
+	if _, ok := extractions.toggle(1, latest.id, 1); ok {
+		t.Error("Expected toggle of the index out of range to be rejected")
+	}
+	extractions.reset(1, old.id)
+	if _, ok := extractions.get(1, latest.id); !ok {
+		t.Error("Expected reset of the replaced extraction to keep the latest one")
+	}
+	extractions.reset(1, latest.id)
+	if _, ok := extractions.get(1, latest.id); ok {
+		t.Error("Expected the extraction to be reset")
+	}
+}
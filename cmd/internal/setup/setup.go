@@ -0,0 +1,56 @@
+// Package setup contains the initialization shared by the commands: reading the config file and creating the logger.
+package setup
+
+import (
+	"fmt"
+	"github.com/dmalyar/pimpmyvocab/log"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// The config keys read by all commands.
+const (
+	LogLevelKey    = "log.level"
+	LogFilePathKey = "log.file"
+	DBUrlKey       = "db.url"
+)
+
+// ReadConfig reads the config file of the bot. The defaults must be set in viper before the call.
+func ReadConfig() {
+	viper.SetConfigName("config")
+	viper.AddConfigPath("$HOME/.pimpmyvocab") // local
+	viper.AddConfigPath("/pimpmyvocab")       // docker
+	err := viper.ReadInConfig()
+	if err != nil {
+		panic(fmt.Sprintf("Error reading the config file: %s", err))
+	}
+}
+
+// InitLogger returns the logger with the level set in the config, the given level is used if it can't be parsed.
+// If the log file path is given then the log is written to the file rotated by size.
+func InitLogger(fallbackLevel logrus.Level, logFilePath string) *log.LoggerLogrus {
+	logger := logrus.StandardLogger()
+
+	var lumberjackLogger *lumberjack.Logger
+	if logFilePath != "" {
+		lumberjackLogger = &lumberjack.Logger{
+			Filename:   logFilePath,
+			MaxSize:    50,
+			MaxBackups: 10,
+			MaxAge:     0,
+			LocalTime:  true,
+		}
+		logrus.SetOutput(lumberjackLogger)
+	}
+
+	level, err := logrus.ParseLevel(viper.GetString(LogLevelKey))
+	if err != nil {
+		logger.Errorf("Error parsing log level: %s", err)
+		level = fallbackLevel
+	}
+	logger.SetLevel(level)
+
+	logger.Info("Logger initialized")
+	return log.New(logger, lumberjackLogger)
+}
@@ -4,7 +4,9 @@ import (
 	"context"
 	"fmt"
 	"github.com/dmalyar/pimpmyvocab/bot"
+	"github.com/dmalyar/pimpmyvocab/cmd/internal/setup"
 	"github.com/dmalyar/pimpmyvocab/dictionary"
+	"github.com/dmalyar/pimpmyvocab/domain"
 	"github.com/dmalyar/pimpmyvocab/log"
 	"github.com/dmalyar/pimpmyvocab/repo"
 	"github.com/dmalyar/pimpmyvocab/service"
@@ -15,22 +17,23 @@ import (
 	"github.com/jackc/pgx/v4/pgxpool"
 	"github.com/sirupsen/logrus"
 	"github.com/spf13/viper"
-	"gopkg.in/natefinch/lumberjack.v2"
 	"math/rand"
 	"net/http"
 	"net/url"
+	"path/filepath"
 	"time"
 )
 
 const (
-	logLevelKey        = "log.level"
-	logFilePathKey     = "log.file"
-	tokenKey           = "bot.token"
-	useProxyKey        = "bot.use-proxy"
-	proxyURLKey        = "bot.proxy-url"
-	dbUrlKey           = "db.url"
-	dbMigrationPathKey = "db.migration-path"
-	dictionaryTokenKey = "dictionary.token"
+	tokenKey             = "bot.token"
+	useProxyKey          = "bot.use-proxy"
+	proxyURLKey          = "bot.proxy-url"
+	dbMigrationPathKey   = "db.migration-path"
+	dictionaryTokenKey   = "dictionary.token"
+	dictionaryTimeoutKey = "dictionary.timeout"
+	dictionaryMergeKey   = "dictionary.merge"
+	offlineDictPathKey   = "dictionary.offline.path"
+	offlineDictLangKey   = "dictionary.offline.lang-pair"
 )
 
 func main() {
@@ -38,7 +41,7 @@ func main() {
 
 	initViper()
 
-	logger := initLogger()
+	logger := setup.InitLogger(logrus.DebugLevel, viper.GetString(setup.LogFilePathKey))
 	defer logger.Close()
 
 	botAPI := initBotAPI(logger)
@@ -47,48 +50,24 @@ func main() {
 	defer vocabRepo.ClosePool()
 	vocabEntryService := initVocabEntryService(logger)
 	vocabService := initVocabService(logger, vocabRepo, vocabEntryService)
-
-	b := bot.New(logger, botAPI, vocabService)
+	schedulerService := initSchedulerService(logger, vocabRepo)
+	quizService := initQuizService(logger, vocabRepo, schedulerService)
+	statsService := initStatsService(logger, vocabRepo)
+	reminderService := initReminderService(logger, vocabRepo)
+	langService := initLangService(logger, vocabRepo)
+	extractorService := initExtractorService(logger, vocabRepo)
+
+	b := bot.New(logger, botAPI, vocabService, schedulerService, quizService, statsService, reminderService, langService,
+		extractorService)
 	b.Run()
 }
 
 func initViper() {
-	viper.SetDefault(logLevelKey, "debug")
+	viper.SetDefault(setup.LogLevelKey, "debug")
+	viper.SetDefault(dictionaryTimeoutKey, 5*time.Second)
+	viper.SetDefault(offlineDictLangKey, domain.DefaultLangPair.String())
 
-	viper.SetConfigName("config")
-	viper.AddConfigPath("$HOME/.pimpmyvocab") // local
-	viper.AddConfigPath("/pimpmyvocab")       // docker
-	err := viper.ReadInConfig()
-	if err != nil {
-		panic(fmt.Sprintf("Error reading the config file: %s", err))
-	}
-}
-
-func initLogger() *log.LoggerLogrus {
-	logger := logrus.StandardLogger()
-
-	logFilePath := viper.GetString(logFilePathKey)
-	var lumberjackLogger *lumberjack.Logger
-	if logFilePath != "" {
-		lumberjackLogger = &lumberjack.Logger{
-			Filename:   logFilePath,
-			MaxSize:    50,
-			MaxBackups: 10,
-			MaxAge:     0,
-			LocalTime:  true,
-		}
-		logrus.SetOutput(lumberjackLogger)
-	}
-
-	level, err := logrus.ParseLevel(viper.GetString(logLevelKey))
-	if err != nil {
-		logger.Errorf("Error parsing log level: %s", err)
-		level = logrus.DebugLevel
-	}
-	logger.SetLevel(level)
-
-	logger.Info("Logger initialized")
-	return log.New(logger, lumberjackLogger)
+	setup.ReadConfig()
 }
 
 func initBotAPI(logger log.Logger) *tgbotapi.BotAPI {
@@ -127,7 +106,7 @@ func initBotAPI(logger log.Logger) *tgbotapi.BotAPI {
 
 func initVocabRepo(logger log.Logger) *repo.Postgres {
 	logger.Info("Initializing repo")
-	dbUrl := viper.GetString(dbUrlKey)
+	dbUrl := viper.GetString(setup.DBUrlKey)
 	if dbUrl == "" {
 		logger.Panic("DB URL not found in the config file")
 	}
@@ -164,21 +143,76 @@ func migrateSchema(logger log.Logger, dbUrl string) {
 	logger.Info("DB schema migrated")
 }
 
-func initVocabEntryService(logger log.Logger) *dictionary.Yandex {
+func initVocabEntryService(logger log.Logger) *dictionary.Composite {
 	logger.Info("Initializing vocab entry service")
-	client := &http.Client{
-		Timeout: 5 * time.Second,
-	}
 	dictionaryToken := viper.GetString(dictionaryTokenKey)
 	if dictionaryToken == "" {
 		logger.Panic("Dictionary token not found in the config file")
 	}
 	dictionaryURL := fmt.Sprintf(dictionary.URL, dictionaryToken)
+	var providers []*dictionary.Provider
+	if offline := initOfflineDict(logger); offline != nil {
+		providers = append(providers, offline)
+	}
+	providers = append(providers, &dictionary.Provider{
+		Name:    dictionary.YandexName,
+		Dict:    dictionary.NewYandexDict(logger, &http.Client{}, dictionaryURL),
+		Timeout: viper.GetDuration(dictionaryTimeoutKey),
+	})
+	mode := dictionary.FirstFound
+	if viper.GetBool(dictionaryMergeKey) {
+		mode = dictionary.MergeAll
+	}
 	logger.Info("Vocab entry service initialized")
-	return dictionary.NewYandexDict(logger, client, dictionaryURL)
+	return dictionary.NewComposite(logger, mode, providers...)
+}
+
+// initOfflineDict loads the offline DSL dictionary asked before Yandex.Dictionary.
+// Returns nil if the dictionary file is not configured.
+func initOfflineDict(logger log.Logger) *dictionary.Provider {
+	path := viper.GetString(offlineDictPathKey)
+	if path == "" {
+		return nil
+	}
+	langPair, err := domain.ParseLangPair(viper.GetString(offlineDictLangKey))
+	if err != nil {
+		logger.Panicf("Error parsing offline dictionary language pair: %s", err)
+	}
+	dict, err := dictionary.LoadDSLDict(logger, path, langPair)
+	if err != nil {
+		logger.Panicf("Error loading offline dictionary: %s", err)
+	}
+	return &dictionary.Provider{
+		Name: dictionary.DSLName + filepath.Base(path),
+		Dict: dict,
+	}
 }
 
 func initVocabService(logger log.Logger, vocabRepo repo.Vocab, vocabEntryService service.VocabEntry) *service.ConcurrentVocab {
 	localRepoService := service.NewVocabWithLocalRepo(logger, vocabRepo, vocabEntryService)
 	return service.NewConcurrentVocab(localRepoService)
 }
+
+func initSchedulerService(logger log.Logger, vocabRepo *repo.Postgres) *service.ConcurrentScheduler {
+	return service.NewConcurrentScheduler(service.NewSchedulerWithLocalRepo(logger, vocabRepo, vocabRepo))
+}
+
+func initQuizService(logger log.Logger, vocabRepo *repo.Postgres, schedulerService service.Scheduler) *service.QuizWithLocalRepo {
+	return service.NewQuizWithLocalRepo(logger, vocabRepo, vocabRepo, vocabRepo, schedulerService)
+}
+
+func initStatsService(logger log.Logger, vocabRepo *repo.Postgres) *service.StatsWithLocalRepo {
+	return service.NewStatsWithLocalRepo(logger, vocabRepo, vocabRepo)
+}
+
+func initReminderService(logger log.Logger, vocabRepo *repo.Postgres) *service.ReminderWithLocalRepo {
+	return service.NewReminderWithLocalRepo(logger, vocabRepo, vocabRepo)
+}
+
+func initLangService(logger log.Logger, vocabRepo *repo.Postgres) *service.LangWithLocalRepo {
+	return service.NewLangWithLocalRepo(logger, vocabRepo)
+}
+
+func initExtractorService(logger log.Logger, vocabRepo *repo.Postgres) *service.ExtractorWithLocalRepo {
+	return service.NewExtractorWithLocalRepo(logger, vocabRepo)
+}
@@ -0,0 +1,102 @@
+// Command pmv_import loads a Wiktionary extract made by wiktextract into the DB of the bot,
+// so the words found in it are never looked up in the online dictionaries.
+// It uses the config file of the bot and expects the DB schema migrated by the bot.
+//
+// Usage:
+//
+//	pmv_import -file kaikki.org-dictionary-English.jsonl.gz [-lang-pair en-ru] [-batch 500]
+package main
+
+import (
+	"compress/gzip"
+	"context"
+	"flag"
+	"fmt"
+	"github.com/dmalyar/pimpmyvocab/cmd/internal/setup"
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"github.com/dmalyar/pimpmyvocab/log"
+	"github.com/dmalyar/pimpmyvocab/repo"
+	"github.com/dmalyar/pimpmyvocab/wiktionary"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/viper"
+	"io"
+	"os"
+	"strings"
+)
+
+func main() {
+	filePath := flag.String("file", "", "wiktextract JSONL file, gzipped if ends with .gz, - for stdin")
+	langPairText := flag.String("lang-pair", domain.DefaultLangPair.String(), "language pair of the imported entries")
+	batchSize := flag.Int("batch", wiktionary.DefaultBatchSize, "number of entries inserted in one transaction")
+	flag.Parse()
+	if *filePath == "" {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	initViper()
+
+	logger := setup.InitLogger(logrus.InfoLevel, "")
+	defer logger.Close()
+
+	langPair, err := domain.ParseLangPair(*langPairText)
+	if err != nil {
+		logger.Panicf("Error parsing language pair: %s", err)
+	}
+	r, closeFn := openExtract(logger, *filePath)
+	defer closeFn()
+	dictRepo := initDictionaryRepo(logger)
+	defer dictRepo.ClosePool()
+
+	importer := wiktionary.NewImporter(logger, dictRepo, *batchSize)
+	result, err := importer.Import(r, langPair)
+	if err != nil {
+		logger.Panicf("Error importing extract (%v entries inserted): %s", result.Inserted, err)
+	}
+	fmt.Printf("%v entries parsed, %v inserted\n", result.Parsed, result.Inserted)
+}
+
+func initViper() {
+	viper.SetDefault(setup.LogLevelKey, "info")
+	setup.ReadConfig()
+}
+
+// openExtract returns the reader of the extract and the func closing it.
+func openExtract(logger log.Logger, path string) (io.Reader, func()) {
+	if path == "-" {
+		return os.Stdin, func() {}
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		logger.Panicf("Error opening extract: %s", err)
+	}
+	if !strings.HasSuffix(path, ".gz") {
+		return f, func() { f.Close() }
+	}
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		f.Close()
+		logger.Panicf("Error opening gzipped extract: %s", err)
+	}
+	return gz, func() {
+		gz.Close()
+		f.Close()
+	}
+}
+
+func initDictionaryRepo(logger log.Logger) *repo.Postgres {
+	dbUrl := viper.GetString(setup.DBUrlKey)
+	if dbUrl == "" {
+		logger.Panic("DB URL not found in the config file")
+	}
+	connConfig, err := pgxpool.ParseConfig(dbUrl)
+	if err != nil {
+		logger.Panicf("Error parsing DB URL: %s", err)
+	}
+	dbPool, err := pgxpool.ConnectConfig(context.Background(), connConfig)
+	if err != nil {
+		logger.Panicf("Error connecting to DB: %s", err)
+	}
+	return repo.NewPostgresRepo(logger, dbPool)
+}
@@ -0,0 +1,192 @@
+package dictionary
+
+import (
+	"context"
+	"fmt"
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"github.com/dmalyar/pimpmyvocab/log"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Dict is a dictionary asked by Composite. The lookup must be abandoned when the context is done.
+type Dict interface {
+	GetVocabEntryByTextContext(ctx context.Context, text string, langPair domain.LangPair) (*domain.VocabEntry, error)
+}
+
+// Provider is a dictionary used by Composite.
+// Name is recorded as the source of the entries the provider produces.
+// Timeout limits the lookup in the provider, zero means no limit.
+type Provider struct {
+	Name    string
+	Dict    Dict
+	Timeout time.Duration
+}
+
+// CompositeMode defines how Composite combines the results of its providers.
+type CompositeMode int
+
+const (
+	// FirstFound asks the providers one by one and returns the entry of the first provider which found it.
+	FirstFound CompositeMode = iota
+	// MergeAll asks all providers at once and merges the translations of all found entries
+	// in the order of the providers.
+	MergeAll
+)
+
+// Composite looks entries up in the ordered chain of dictionaries.
+// The failing providers are skipped, the error is returned only if the entry wasn't found
+// and some of the providers failed.
+type Composite struct {
+	logger    log.Logger
+	mode      CompositeMode
+	providers []*Provider
+}
+
+type lookupResult struct {
+	entry *domain.VocabEntry
+	err   error
+}
+
+func NewComposite(logger log.Logger, mode CompositeMode, providers ...*Provider) *Composite {
+	return &Composite{
+		logger:    logger,
+		mode:      mode,
+		providers: providers,
+	}
+}
+
+// GetVocabEntryByText returns the entry found by the providers for the given language pair.
+// Source of the entry is the name of the provider which found it or the names of all providers
+// whose entries were merged joined with "+".
+// Returns nil if entry was not found.
+func (c *Composite) GetVocabEntryByText(text string, langPair domain.LangPair) (*domain.VocabEntry, error) {
+	logger := c.logger.WithFields(map[string]interface{}{
+		"text":     text,
+		"langPair": langPair,
+	})
+	logger.Debug("Getting vocab entry from composite dictionary")
+	var results []*lookupResult
+	if c.mode == MergeAll {
+		results = c.lookupAll(text, langPair)
+	} else {
+		results = c.lookupFirst(text, langPair)
+	}
+	var found []*domain.VocabEntry
+	var errs []string
+	for i, r := range results {
+		switch {
+		case r.err != nil:
+			logger.Errorf("Error getting vocab entry from %s: %s", c.providers[i].Name, r.err)
+			errs = append(errs, fmt.Sprintf("%s: %s", c.providers[i].Name, r.err))
+		case r.entry != nil:
+			logger.Debugf("Vocab entry found in %s", c.providers[i].Name)
+			found = append(found, r.entry)
+		}
+	}
+	if len(found) == 0 {
+		if len(errs) != 0 {
+			return nil, fmt.Errorf("getting vocab entry from dictionaries: %s", strings.Join(errs, "; "))
+		}
+		logger.Debug("Vocab entry not found in any dictionary")
+		return nil, nil
+	}
+	return mergeEntries(found), nil
+}
+
+// lookupFirst asks the providers one by one until the entry is found.
+// Returns the results of the asked providers.
+func (c *Composite) lookupFirst(text string, langPair domain.LangPair) []*lookupResult {
+	results := make([]*lookupResult, 0, len(c.providers))
+	for _, p := range c.providers {
+		r := p.lookup(text, langPair)
+		results = append(results, r)
+		if r.entry != nil {
+			break
+		}
+	}
+	return results
+}
+
+// lookupAll asks all providers concurrently and returns their results in the order of the providers.
+func (c *Composite) lookupAll(text string, langPair domain.LangPair) []*lookupResult {
+	results := make([]*lookupResult, len(c.providers))
+	var wg sync.WaitGroup
+	for i, p := range c.providers {
+		wg.Add(1)
+		go func(i int, p *Provider) {
+			defer wg.Done()
+			results[i] = p.lookup(text, langPair)
+		}(i, p)
+	}
+	wg.Wait()
+	return results
+}
+
+// lookup gets the entry from the provider and sets its source.
+// If the provider doesn't respond in time then the lookup is abandoned and the timeout error is returned.
+func (p *Provider) lookup(text string, langPair domain.LangPair) *lookupResult {
+	ctx := context.Background()
+	if p.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, p.Timeout)
+		defer cancel()
+	}
+	entry, err := p.Dict.GetVocabEntryByTextContext(ctx, text, langPair)
+	if err != nil {
+		if ctx.Err() == context.DeadlineExceeded {
+			return &lookupResult{err: fmt.Errorf("timed out after %s", p.Timeout)}
+		}
+		return &lookupResult{err: err}
+	}
+	if entry != nil {
+		entry.Source = p.Name
+	}
+	return &lookupResult{entry: entry}
+}
+
+// mergeEntries adds the translations of the other entries missing in the first one to its end.
+// The first non-empty transcription is used.
+func mergeEntries(entries []*domain.VocabEntry) *domain.VocabEntry {
+	merged := entries[0]
+	if len(entries) == 1 {
+		return merged
+	}
+	sources := []string{merged.Source}
+	added := make(map[string]struct{})
+	position := 0
+	for _, t := range merged.Translations {
+		added[translationKey(t)] = struct{}{}
+		if t.Position >= position {
+			position = t.Position + 1
+		}
+	}
+	for _, e := range entries[1:] {
+		sources = append(sources, e.Source)
+		if merged.Transcription == "" {
+			merged.Transcription = e.Transcription
+		}
+		for _, t := range e.Translations {
+			key := translationKey(t)
+			if _, ok := added[key]; ok {
+				continue
+			}
+			added[key] = struct{}{}
+			translation := *t
+			translation.Position = position
+			merged.Translations = append(merged.Translations, &translation)
+			position++
+		}
+	}
+	merged.Source = strings.Join(sources, "+")
+	return merged
+}
+
+func translationKey(t *domain.Translation) string {
+	return t.Class + ":" + strings.ToLower(t.Text)
+}
+
+func (c *Composite) GetVocabEntryByID(_ int) (*domain.VocabEntry, error) {
+	return nil, fmt.Errorf("GetVocabEntryByID is not supported")
+}
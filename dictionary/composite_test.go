@@ -0,0 +1,151 @@
+package dictionary
+
+import (
+	"context"
+	"fmt"
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"github.com/dmalyar/pimpmyvocab/mock"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type lookupFn func(context.Context, string, domain.LangPair) (*domain.VocabEntry, error)
+
+// testDict registers invocation of the lookup func and calls it.
+type testDict struct {
+	lookup  lookupFn
+	invoked bool
+}
+
+func (d *testDict) GetVocabEntryByTextContext(
+	ctx context.Context,
+	text string,
+	langPair domain.LangPair,
+) (*domain.VocabEntry, error) {
+	d.invoked = true
+	return d.lookup(ctx, text, langPair)
+}
+
+func foundIn(translations ...*domain.Translation) lookupFn {
+	return func(_ context.Context, text string, langPair domain.LangPair) (*domain.VocabEntry, error) {
+		return &domain.VocabEntry{Text: text, LangPair: langPair, Translations: translations}, nil
+	}
+}
+
+func notFound(context.Context, string, domain.LangPair) (*domain.VocabEntry, error) {
+	return nil, nil
+}
+
+func failing(context.Context, string, domain.LangPair) (*domain.VocabEntry, error) {
+	return nil, fmt.Errorf("error")
+}
+
+func slow(ctx context.Context, _ string, _ domain.LangPair) (*domain.VocabEntry, error) {
+	select {
+	case <-time.After(time.Second):
+		return &domain.VocabEntry{Text: "slow"}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func TestComposite_GetVocabEntryByText(t *testing.T) {
+	langPair := domain.LangPair{Source: "en", Target: "ru"}
+	noun := &domain.Translation{Text: "ёж", Class: "noun", Position: 0}
+	testCases := []struct {
+		name            string
+		mode            CompositeMode
+		lookups         []lookupFn
+		expectedEntry   *domain.VocabEntry
+		expectedInvoked []bool
+		expectErr       bool
+	}{
+		{
+			name:    "First found: found by the first provider",
+			lookups: []lookupFn{foundIn(noun), foundIn(noun)},
+			expectedEntry: &domain.VocabEntry{
+				Text: "hedgehog", LangPair: langPair, Source: "first", Translations: []*domain.Translation{noun},
+			},
+			expectedInvoked: []bool{true, false},
+		},
+		{
+			name:    "First found: falls back to the next provider",
+			lookups: []lookupFn{notFound, failing, foundIn(noun)},
+			expectedEntry: &domain.VocabEntry{
+				Text: "hedgehog", LangPair: langPair, Source: "third", Translations: []*domain.Translation{noun},
+			},
+			expectedInvoked: []bool{true, true, true},
+		},
+		{
+			name:    "First found: provider timed out",
+			lookups: []lookupFn{slow, foundIn(noun)},
+			expectedEntry: &domain.VocabEntry{
+				Text: "hedgehog", LangPair: langPair, Source: "second", Translations: []*domain.Translation{noun},
+			},
+			expectedInvoked: []bool{true, true},
+		},
+		{
+			name:            "Not found",
+			lookups:         []lookupFn{notFound, notFound},
+			expectedInvoked: []bool{true, true},
+		},
+		{
+			name:            "Not found and provider failed",
+			lookups:         []lookupFn{notFound, failing},
+			expectedInvoked: []bool{true, true},
+			expectErr:       true,
+		},
+		{
+			name: "Merge all",
+			mode: MergeAll,
+			lookups: []lookupFn{
+				foundIn(noun),
+				failing,
+				foundIn(
+					&domain.Translation{Text: "Ёж", Class: "noun", Position: 0},
+					&domain.Translation{Text: "колючка", Class: "noun", Position: 1},
+				),
+			},
+			expectedEntry: &domain.VocabEntry{
+				Text:     "hedgehog",
+				LangPair: langPair,
+				Source:   "first+third",
+				Translations: []*domain.Translation{
+					noun,
+					{Text: "колючка", Class: "noun", Position: 1},
+				},
+			},
+			expectedInvoked: []bool{true, true, true},
+		},
+	}
+	names := []string{"first", "second", "third"}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			var dicts []*testDict
+			var providers []*Provider
+			for i, lookup := range c.lookups {
+				dict := &testDict{lookup: lookup}
+				dicts = append(dicts, dict)
+				providers = append(providers, &Provider{Name: names[i], Dict: dict, Timeout: 50 * time.Millisecond})
+			}
+			composite := NewComposite(mock.Logger{}, c.mode, providers...)
+			entry, err := composite.GetVocabEntryByText("hedgehog", langPair)
+			if c.expectErr && err == nil {
+				t.Errorf("Expected error, but got nothing")
+			}
+			if !c.expectErr && err != nil {
+				t.Errorf("Expected no error, but got %s", err)
+			}
+			if !reflect.DeepEqual(c.expectedEntry, entry) {
+				t.Errorf("Expected entry:%v;Actual:%v", c.expectedEntry, entry)
+			}
+			for i, dict := range dicts {
+				if dict.invoked != c.expectedInvoked[i] {
+					t.Errorf("Expected invocation of provider %v: %v;Actual:%v",
+						names[i], c.expectedInvoked[i], dict.invoked)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,324 @@
+package dictionary
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"fmt"
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"github.com/dmalyar/pimpmyvocab/log"
+	"io"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"strings"
+	"unicode/utf16"
+)
+
+// DSLName is the prefix of the name of the offline DSL dictionary provider, the file name follows it.
+const DSLName = "dsl:"
+
+// DSL is an offline dictionary in ABBYY Lingvo DSL format.
+// The whole dictionary is read at startup and indexed by the headwords, the cards are parsed on lookup.
+// The dictionary serves the only language pair.
+type DSL struct {
+	logger   log.Logger
+	name     string
+	langPair domain.LangPair
+	index    map[string]*dslCard
+}
+
+// dslCard is a dictionary card: one or more headwords followed by the indented body.
+type dslCard struct {
+	body string
+}
+
+var (
+	dslCommentRe = regexp.MustCompile(`\{\{.*?\}\}`)
+	dslTagRe     = regexp.MustCompile(`\[/?[^\]]*\]`)
+	dslEscapeRe  = regexp.MustCompile(`\\(.)`)
+	dslTrnRe     = regexp.MustCompile(`\[trn\](.*?)\[/trn\]`)
+	dslTransRe   = regexp.MustCompile(`\[t\](.*?)\[/t\]`)
+	dslClassRe   = regexp.MustCompile(`\[p\](.*?)\[/p\]`)
+	dslNumberRe  = regexp.MustCompile(`^(\d+[.)>]|[a-zа-я][)>])\s*`)
+	// dslNotTranslationRes match the sections which are not translations: examples, comments, references etc.
+	dslNotTranslationRes = dslSectionRes("ex", "com", "*", "s", "ref", "url", "t", "p")
+	// dslEscapedBrackets replaces the escaped brackets so that they are not taken for tags.
+	// The placeholders are the Unicode private use characters which don't occur in the dictionary text.
+	dslEscapedBrackets = strings.NewReplacer(`\\`, "\ue000", `\[`, "\ue001", `\]`, "\ue002")
+	dslBrackets        = strings.NewReplacer("\ue000", `\`, "\ue001", "[", "\ue002", "]")
+)
+
+// dslClasses maps the part of speech abbreviations of the DSL dictionaries to the classes Yandex.Dictionary uses.
+var dslClasses = map[string]string{
+	"n": "noun", "noun": "noun", "сущ": "noun", "с": "noun",
+	"v": "verb", "vt": "verb", "vi": "verb", "verb": "verb", "гл": "verb",
+	"a": "adjective", "adj": "adjective", "adjective": "adjective", "прил": "adjective",
+	"adv": "adverb", "adverb": "adverb", "нареч": "adverb", "нар": "adverb",
+	"pron": "pronoun", "pronoun": "pronoun", "мест": "pronoun",
+	"prep": "preposition", "preposition": "preposition", "предл": "preposition",
+	"conj": "conjunction", "conjunction": "conjunction", "союз": "conjunction",
+	"int": "interjection", "interj": "interjection", "interjection": "interjection", "межд": "interjection",
+	"num": "numeral", "numeral": "numeral", "числ": "numeral",
+	"part": "particle", "particle": "particle", "частица": "particle",
+	"art": "article", "article": "article",
+}
+
+func dslSectionRes(tags ...string) []*regexp.Regexp {
+	res := make([]*regexp.Regexp, 0, len(tags))
+	for _, t := range tags {
+		tag := regexp.QuoteMeta(t)
+		res = append(res, regexp.MustCompile(`\[`+tag+`\].*?\[/`+tag+`\]`))
+	}
+	return res
+}
+
+// LoadDSLDict reads the DSL dictionary file serving the given language pair.
+// Files compressed with dictzip (.dsl.dz) are supported as well.
+func LoadDSLDict(logger log.Logger, path string, langPair domain.LangPair) (*DSL, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening dictionary file: %s", err)
+	}
+	defer f.Close()
+	var r io.Reader = f
+	if strings.HasSuffix(path, ".dz") {
+		gz, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, fmt.Errorf("opening compressed dictionary file: %s", err)
+		}
+		defer gz.Close()
+		r = gz
+	}
+	return NewDSLDict(logger, r, langPair)
+}
+
+// NewDSLDict reads the DSL dictionary serving the given language pair and builds its index.
+// UTF-8 and UTF-16 encodings are supported.
+func NewDSLDict(logger log.Logger, r io.Reader, langPair domain.LangPair) (*DSL, error) {
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("reading dictionary: %s", err)
+	}
+	d := &DSL{
+		logger:   logger,
+		langPair: langPair,
+		index:    make(map[string]*dslCard),
+	}
+	var headwords, body []string
+	addCard := func() {
+		if len(headwords) != 0 && len(body) != 0 {
+			card := &dslCard{body: strings.Join(body, "\n")}
+			for _, h := range headwords {
+				key := dslKey(h)
+				if _, ok := d.index[key]; !ok && key != "" {
+					d.index[key] = card
+				}
+			}
+		}
+		headwords, body = nil, nil
+	}
+	for _, line := range strings.Split(decodeDSL(content), "\n") {
+		line = strings.TrimRight(line, "\r")
+		switch {
+		case strings.TrimSpace(line) == "":
+			continue
+		case line[0] == ' ' || line[0] == '\t':
+			body = append(body, line)
+		case len(headwords) == 0 && len(body) == 0 && strings.HasPrefix(line, "#"):
+			if strings.HasPrefix(line, "#NAME") {
+				d.name = strings.Trim(strings.TrimSpace(strings.TrimPrefix(line, "#NAME")), `"`)
+			}
+		default:
+			if len(body) != 0 {
+				addCard()
+			}
+			headwords = append(headwords, line)
+		}
+	}
+	addCard()
+	logger.Infof("DSL dictionary %q loaded with %v headword(s)", d.name, len(d.index))
+	return d, nil
+}
+
+// decodeDSL returns the content of the dictionary as UTF-8 text.
+// Lingvo saves dictionaries in UTF-16LE, the byte order mark tells the encoding.
+func decodeDSL(content []byte) string {
+	var bigEndian bool
+	switch {
+	case bytes.HasPrefix(content, []byte{0xff, 0xfe}):
+		content = content[2:]
+	case bytes.HasPrefix(content, []byte{0xfe, 0xff}):
+		content = content[2:]
+		bigEndian = true
+	case len(content) > 1 && content[0] != 0 && content[1] == 0:
+		// UTF-16LE without the byte order mark
+	default:
+		return string(bytes.TrimPrefix(content, []byte{0xef, 0xbb, 0xbf}))
+	}
+	units := make([]uint16, len(content)/2)
+	for i := range units {
+		if bigEndian {
+			units[i] = uint16(content[2*i])<<8 | uint16(content[2*i+1])
+		} else {
+			units[i] = uint16(content[2*i+1])<<8 | uint16(content[2*i])
+		}
+	}
+	return string(utf16.Decode(units))
+}
+
+// dslKey returns the index key of the headword: the unsorted parts in braces are removed,
+// spaces are collapsed and the text is lowercased.
+func dslKey(headword string) string {
+	var builder strings.Builder
+	var depth int
+	escaped := false
+	for _, r := range headword {
+		switch {
+		case escaped:
+			escaped = false
+		case r == '\\':
+			escaped = true
+			continue
+		case r == '{':
+			depth++
+			continue
+		case r == '}' && depth > 0:
+			depth--
+			continue
+		}
+		if depth == 0 {
+			builder.WriteRune(r)
+		}
+	}
+	return strings.ToLower(strings.Join(strings.Fields(builder.String()), " "))
+}
+
+// GetVocabEntryByText returns the entry found in the dictionary.
+// Returns nil if entry was not found or the dictionary doesn't serve the language pair.
+func (d *DSL) GetVocabEntryByText(text string, langPair domain.LangPair) (*domain.VocabEntry, error) {
+	logger := d.logger.WithFields(map[string]interface{}{
+		"text":     text,
+		"langPair": langPair,
+	})
+	logger.Debugf("Getting vocab entry from DSL dictionary %q", d.name)
+	if langPair != d.langPair {
+		return nil, nil
+	}
+	card, ok := d.index[dslKey(text)]
+	if !ok {
+		return nil, nil
+	}
+	return card.toVocabEntry(text, langPair), nil
+}
+
+// GetVocabEntryByTextContext is GetVocabEntryByText, the context is ignored since the dictionary is kept in memory.
+func (d *DSL) GetVocabEntryByTextContext(
+	_ context.Context,
+	text string,
+	langPair domain.LangPair,
+) (*domain.VocabEntry, error) {
+	return d.GetVocabEntryByText(text, langPair)
+}
+
+// toVocabEntry converts the card to the entry. The class set by the part of speech label applies
+// to the translations after it. If the card marks translations with [trn] tags then only they are taken,
+// otherwise all text of the card except examples and comments is taken.
+// Returns nil if the card has no translations.
+func (c *dslCard) toVocabEntry(text string, langPair domain.LangPair) *domain.VocabEntry {
+	entry := &domain.VocabEntry{
+		Text:     text,
+		LangPair: langPair,
+	}
+	markedTranslations := strings.Contains(c.body, "[trn]")
+	var class string
+	for _, line := range strings.Split(c.body, "\n") {
+		line = dslCommentRe.ReplaceAllString(dslEscapedBrackets.Replace(line), "")
+		if m := dslTransRe.FindStringSubmatch(line); m != nil && entry.Transcription == "" {
+			entry.Transcription = stripDSL(m[1])
+		}
+		if m := dslClassRe.FindStringSubmatch(line); m != nil {
+			class = dslClass(stripDSL(m[1]))
+		}
+		var parts []string
+		if markedTranslations {
+			for _, m := range dslTrnRe.FindAllStringSubmatch(line, -1) {
+				parts = append(parts, m[1])
+			}
+		} else {
+			parts = []string{line}
+		}
+		for _, p := range parts {
+			for _, t := range splitTranslations(dslTranslationText(p)) {
+				entry.Translations = append(entry.Translations, &domain.Translation{
+					Text:     t,
+					Class:    class,
+					Position: len(entry.Translations),
+				})
+			}
+		}
+	}
+	if len(entry.Translations) == 0 {
+		return nil
+	}
+	entry.MainTranslation = entry.Translations[0].Text
+	return entry
+}
+
+// dslTranslationText removes the sections which are not translations, markup and numbering from the text.
+func dslTranslationText(text string) string {
+	for _, re := range dslNotTranslationRes {
+		text = re.ReplaceAllString(text, "")
+	}
+	text = stripDSL(text)
+	for i := 0; i < 2; i++ {
+		text = dslNumberRe.ReplaceAllString(text, "")
+	}
+	return text
+}
+
+// stripDSL removes the markup tags and escapes from the text and collapses spaces.
+func stripDSL(text string) string {
+	text = dslTagRe.ReplaceAllString(text, "")
+	text = dslEscapeRe.ReplaceAllString(text, "$1")
+	text = dslBrackets.Replace(text)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// splitTranslations splits the text on commas and semicolons which are not in parentheses.
+func splitTranslations(text string) []string {
+	var res []string
+	var depth, start int
+	add := func(t string) {
+		t = strings.TrimSpace(t)
+		if t != "" {
+			res = append(res, t)
+		}
+	}
+	for i, r := range text {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')' && depth > 0:
+			depth--
+		case (r == ',' || r == ';') && depth == 0:
+			add(text[start:i])
+			start = i + 1
+		}
+	}
+	add(text[start:])
+	return res
+}
+
+// dslClass returns the class of the part of speech label, e.g. "noun" for "n." or "сущ.".
+func dslClass(label string) string {
+	label = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(label)), ".")
+	if class, ok := dslClasses[label]; ok {
+		return class
+	}
+	return label
+}
+
+func (d *DSL) GetVocabEntryByID(_ int) (*domain.VocabEntry, error) {
+	return nil, fmt.Errorf("GetVocabEntryByID is not supported")
+}
@@ -0,0 +1,174 @@
+package dictionary
+
+import (
+	"bytes"
+	"compress/gzip"
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"github.com/dmalyar/pimpmyvocab/mock"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+const dsl = `#NAME "English-Russian"
+#INDEX_LANGUAGE "English"
+#CONTENTS_LANGUAGE "Russian"
+
+hedgehog
+	[m0][t]ˈhedʒhɒg[/t][/m]
+	[m1]1) [p]n[/p] [trn]ёж[/trn], [trn]ёжик[/trn][/m]
+	[m2][ex][lang id=1033]a ~ rolled up[/lang] — ёж свернулся[/ex][/m]
+	[m1]2) [trn]колючая проволока {{obsolete}}[/trn][/m]
+	[m1]3) [p]v[/p] [trn]окружать \[колючками\][/trn][/m]
+
+head
+{(}to{)} head up
+	[m0][t]hed[/t][/m]
+	[m1]1. [p]сущ.[/p] голова; курс (корабля, самолёта)[/m]
+	[m2][ex]off one's ~ — не в себе[/ex][/m]
+	[m1]2. [p]гл.[/p] возглавлять[/m]
+
+nothing
+	[m1][com]see also[/com] [ref]none[/ref][/m]
+`
+
+func TestDSL_GetVocabEntryByText(t *testing.T) {
+	langPair := domain.LangPair{Source: "en", Target: "ru"}
+	testCases := []struct {
+		name          string
+		text          string
+		langPair      domain.LangPair
+		expectedEntry *domain.VocabEntry
+	}{
+		{
+			name:     "Translations marked with trn tags",
+			text:     "Hedgehog",
+			langPair: langPair,
+			expectedEntry: &domain.VocabEntry{
+				Text:            "Hedgehog",
+				Transcription:   "ˈhedʒhɒg",
+				MainTranslation: "ёж",
+				LangPair:        langPair,
+				Translations: []*domain.Translation{
+					{Text: "ёж", Class: "noun", Position: 0},
+					{Text: "ёжик", Class: "noun", Position: 1},
+					{Text: "колючая проволока", Class: "noun", Position: 2},
+					{Text: "окружать [колючками]", Class: "verb", Position: 3},
+				},
+			},
+		},
+		{
+			name:     "Translations without tags",
+			text:     "head",
+			langPair: langPair,
+			expectedEntry: &domain.VocabEntry{
+				Text:            "head",
+				Transcription:   "hed",
+				MainTranslation: "голова",
+				LangPair:        langPair,
+				Translations: []*domain.Translation{
+					{Text: "голова", Class: "noun", Position: 0},
+					{Text: "курс (корабля, самолёта)", Class: "noun", Position: 1},
+					{Text: "возглавлять", Class: "verb", Position: 2},
+				},
+			},
+		},
+		{
+			name:     "Second headword of the card with unsorted parts",
+			text:     "To head up",
+			langPair: langPair,
+			expectedEntry: &domain.VocabEntry{
+				Text:            "To head up",
+				Transcription:   "hed",
+				MainTranslation: "голова",
+				LangPair:        langPair,
+				Translations: []*domain.Translation{
+					{Text: "голова", Class: "noun", Position: 0},
+					{Text: "курс (корабля, самолёта)", Class: "noun", Position: 1},
+					{Text: "возглавлять", Class: "verb", Position: 2},
+				},
+			},
+		},
+		{
+			name:     "Card without translations",
+			text:     "nothing",
+			langPair: langPair,
+		},
+		{
+			name:     "Not found",
+			text:     "tail",
+			langPair: langPair,
+		},
+		{
+			name:     "Language pair not served",
+			text:     "hedgehog",
+			langPair: domain.LangPair{Source: "de", Target: "ru"},
+		},
+	}
+	dict, err := NewDSLDict(mock.Logger{}, strings.NewReader(dsl), langPair)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			entry, err := dict.GetVocabEntryByText(c.text, c.langPair)
+			if err != nil {
+				t.Errorf("Expected no error, but got %s", err)
+			}
+			if !reflect.DeepEqual(c.expectedEntry, entry) {
+				t.Errorf("Expected entry:%v;Actual:%v", c.expectedEntry, entry)
+			}
+		})
+	}
+}
+
+func TestLoadDSLDict(t *testing.T) {
+	langPair := domain.LangPair{Source: "en", Target: "ru"}
+	// Lingvo saves dictionaries in UTF-16LE with the byte order mark and compresses them with dictzip
+	units := utf16.Encode([]rune(strings.ReplaceAll(dsl, "\n", "\r\n")))
+	content := []byte{0xff, 0xfe}
+	for _, u := range units {
+		content = append(content, byte(u), byte(u>>8))
+	}
+	compressed := new(bytes.Buffer)
+	gz := gzip.NewWriter(compressed)
+	if _, err := gz.Write(content); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	dir, err := ioutil.TempDir("", "dsl")
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "en-ru.dsl.dz")
+	if err = ioutil.WriteFile(path, compressed.Bytes(), 0644); err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+
+	dict, err := LoadDSLDict(mock.Logger{}, path, langPair)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if dict.name != "English-Russian" {
+		t.Errorf("Expected name:%q;Actual:%q", "English-Russian", dict.name)
+	}
+	entry, err := dict.GetVocabEntryByText("hedgehog", langPair)
+	if err != nil {
+		t.Errorf("Expected no error, but got %s", err)
+	}
+	if entry == nil || entry.Transcription != "ˈhedʒhɒg" || len(entry.Translations) != 4 {
+		t.Errorf("Expected hedgehog entry with transcription and 4 translations;Actual:%v", entry)
+	}
+
+	_, err = LoadDSLDict(mock.Logger{}, filepath.Join(dir, "missing.dsl"), langPair)
+	if err == nil {
+		t.Errorf("Expected error, but got nothing")
+	}
+}
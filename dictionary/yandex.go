@@ -1,6 +1,7 @@
 package dictionary
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/dmalyar/pimpmyvocab/domain"
@@ -11,7 +12,11 @@ import (
 	"strings"
 )
 
-const URL = "https://dictionary.yandex.net/api/v1/dicservice.json/lookup?key=%s&lang=en-ru&text="
+// URL is a lookup URL of the Yandex.Dictionary service without the language pair and the text parameters.
+const URL = "https://dictionary.yandex.net/api/v1/dicservice.json/lookup?key=%s"
+
+// YandexName is the name of the Yandex.Dictionary provider recorded as the source of its entries.
+const YandexName = "yandex"
 
 type Yandex struct {
 	logger log.Logger
@@ -30,8 +35,32 @@ type Definition struct {
 	Tr   []Translation
 }
 
+// Translation is a translation of the definition. Gen and Asp are the gender and the aspect of the translation,
+// Mean are the meanings of the definition text the translation corresponds to.
 type Translation struct {
 	Text string
+	Gen  string
+	Asp  string
+	Syn  []Synonym
+	Mean []Meaning
+	Ex   []Example
+}
+
+type Synonym struct {
+	Text string
+}
+
+type Meaning struct {
+	Text string
+}
+
+type Example struct {
+	Text string
+	Tr   []ExampleTranslation
+}
+
+type ExampleTranslation struct {
+	Text string
 }
 
 func NewYandexDict(logger log.Logger, client *http.Client, url string) *Yandex {
@@ -42,12 +71,27 @@ func NewYandexDict(logger log.Logger, client *http.Client, url string) *Yandex {
 	}
 }
 
-// GetVocabEntryByText returns an entry found in the Yandex.Dictionary service.
+// GetVocabEntryByText returns an entry found in the Yandex.Dictionary service for the given language pair.
 // Returns nil if entry was not found.
-func (y *Yandex) GetVocabEntryByText(text string) (*domain.VocabEntry, error) {
-	logger := y.logger.WithField("text", text)
+func (y *Yandex) GetVocabEntryByText(text string, langPair domain.LangPair) (*domain.VocabEntry, error) {
+	return y.GetVocabEntryByTextContext(context.Background(), text, langPair)
+}
+
+// GetVocabEntryByTextContext is GetVocabEntryByText which cancels the request when the context is done.
+func (y *Yandex) GetVocabEntryByTextContext(
+	ctx context.Context,
+	text string,
+	langPair domain.LangPair,
+) (*domain.VocabEntry, error) {
+	logger := y.logger.WithFields(map[string]interface{}{
+		"text":     text,
+		"langPair": langPair,
+	})
 	logger.Debug("Getting vocab entry from yandex dictionary")
-	req, err := http.NewRequest(http.MethodGet, y.url+url.PathEscape(text), nil)
+	query := url.Values{}
+	query.Set("lang", langPair.String())
+	query.Set("text", text)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, y.url+"&"+query.Encode(), nil)
 	if err != nil {
 		return nil, fmt.Errorf("creating http request: %s", err)
 	}
@@ -55,6 +99,7 @@ func (y *Yandex) GetVocabEntryByText(text string) (*domain.VocabEntry, error) {
 	if err != nil {
 		return nil, fmt.Errorf("calling yandex.dictionary: %s", err)
 	}
+	defer res.Body.Close()
 	body, err := ioutil.ReadAll(res.Body)
 	if err != nil {
 		return nil, fmt.Errorf("reading http response body: %s", err)
@@ -67,15 +112,16 @@ func (y *Yandex) GetVocabEntryByText(text string) (*domain.VocabEntry, error) {
 	if err != nil {
 		return nil, fmt.Errorf("parsing http response: %s", err)
 	}
-	return convertToVocabEntry(text, parsedRes), nil
+	return convertToVocabEntry(text, langPair, parsedRes), nil
 }
 
-func convertToVocabEntry(text string, res *Response) *domain.VocabEntry {
+func convertToVocabEntry(text string, langPair domain.LangPair, res *Response) *domain.VocabEntry {
 	if len(res.Def) == 0 {
 		return nil
 	}
 	entry := new(domain.VocabEntry)
 	entry.Text = text
+	entry.LangPair = langPair
 	position := 0
 	for _, d := range res.Def {
 		if strings.ToLower(d.Text) != strings.ToLower(text) {
@@ -89,9 +135,8 @@ func convertToVocabEntry(text string, res *Response) *domain.VocabEntry {
 			continue
 		}
 		for _, t := range d.Tr {
-			translation := new(domain.Translation)
+			translation := convertToTranslation(t)
 			entry.Translations = append(entry.Translations, translation)
-			translation.Text = t.Text
 			translation.Class = class
 			translation.Position = position
 			position++
@@ -104,6 +149,31 @@ func convertToVocabEntry(text string, res *Response) *domain.VocabEntry {
 	return entry
 }
 
+func convertToTranslation(t Translation) *domain.Translation {
+	translation := &domain.Translation{
+		Text:   t.Text,
+		Gender: t.Gen,
+		Aspect: t.Asp,
+	}
+	for _, s := range t.Syn {
+		translation.Synonyms = append(translation.Synonyms, s.Text)
+	}
+	for _, m := range t.Mean {
+		translation.Meanings = append(translation.Meanings, m.Text)
+	}
+	for _, e := range t.Ex {
+		texts := make([]string, 0, len(e.Tr))
+		for _, tr := range e.Tr {
+			texts = append(texts, tr.Text)
+		}
+		translation.Examples = append(translation.Examples, &domain.Example{
+			Text:        e.Text,
+			Translation: strings.Join(texts, ", "),
+		})
+	}
+	return translation
+}
+
 func parseResponse(res []byte) (*Response, error) {
 	parsedRes := new(Response)
 	err := json.Unmarshal(res, parsedRes)
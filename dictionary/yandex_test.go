@@ -56,6 +56,59 @@ const (
       ]
     }
   ]
+}`
+	timeJson = `{
+  "def": [
+    {
+      "text": "time",
+      "pos": "noun",
+      "ts": "taɪm",
+      "tr": [
+        {
+          "text": "время",
+          "pos": "noun",
+          "gen": "ср",
+          "syn": [
+            {
+              "text": "пора",
+              "pos": "noun",
+              "gen": "ж"
+            }
+          ],
+          "mean": [
+            {
+              "text": "period"
+            },
+            {
+              "text": "hour"
+            }
+          ],
+          "ex": [
+            {
+              "text": "prime time",
+              "tr": [
+                {
+                  "text": "прайм-тайм"
+                }
+              ]
+            }
+          ]
+        }
+      ]
+    },
+    {
+      "text": "time",
+      "pos": "verb",
+      "ts": "taɪm",
+      "tr": [
+        {
+          "text": "засекать",
+          "pos": "verb",
+          "asp": "несов"
+        }
+      ]
+    }
+  ]
 }`
 	emptyJson  = `{}`
 	brokenJson = `{{}`
@@ -64,14 +117,17 @@ const (
 func TestYandex_GetVocabEntryByText(t *testing.T) {
 	testCases := []struct {
 		text          string
+		langPair      domain.LangPair
 		expectedEntry *domain.VocabEntry
 		expectErr     bool
 	}{
 		{
-			text: "Positive",
+			text:     "Positive",
+			langPair: domain.LangPair{Source: "en", Target: "ru"},
 			expectedEntry: &domain.VocabEntry{
 				ID:              0,
 				Text:            "Positive",
+				LangPair:        domain.LangPair{Source: "en", Target: "ru"},
 				Transcription:   "ˈpɒzɪtɪv",
 				MainTranslation: "положительный",
 				Translations: []*domain.Translation{
@@ -97,32 +153,74 @@ func TestYandex_GetVocabEntryByText(t *testing.T) {
 			},
 		},
 		{
-			text: "Different text",
+			text:     "time",
+			langPair: domain.LangPair{Source: "en", Target: "ru"},
+			expectedEntry: &domain.VocabEntry{
+				Text:            "time",
+				LangPair:        domain.LangPair{Source: "en", Target: "ru"},
+				Transcription:   "taɪm",
+				MainTranslation: "время",
+				Translations: []*domain.Translation{
+					{
+						Text:     "время",
+						Class:    "noun",
+						Position: 0,
+						Gender:   "ср",
+						Synonyms: []string{"пора"},
+						Meanings: []string{"period", "hour"},
+						Examples: []*domain.Example{{Text: "prime time", Translation: "прайм-тайм"}},
+					},
+					{
+						Text:     "засекать",
+						Class:    "verb",
+						Position: 1,
+						Aspect:   "несов",
+					},
+				},
+			},
+		},
+		{
+			text:     "Positive",
+			langPair: domain.LangPair{Source: "de", Target: "ru"},
 		},
 		{
-			text: "Empty json",
+			text:     "Different text",
+			langPair: domain.LangPair{Source: "en", Target: "ru"},
+		},
+		{
+			text:     "Empty json",
+			langPair: domain.LangPair{Source: "en", Target: "ru"},
 		},
 		{
 			text:      "Broken json",
+			langPair:  domain.LangPair{Source: "en", Target: "ru"},
 			expectErr: true,
 		},
 		{
 			text:      "Code not 200",
+			langPair:  domain.LangPair{Source: "en", Target: "ru"},
 			expectErr: true,
 		},
 	}
 
 	mockServer := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
-		switch req.URL.Path {
-		case "/Positive":
+		query := req.URL.Query()
+		if query.Get("key") != "token" || query.Get("lang") != "en-ru" {
+			rw.Write([]byte(emptyJson))
+			return
+		}
+		switch query.Get("text") {
+		case "Positive":
 			rw.Write([]byte(positiveJson))
-		case "/Different text":
+		case "time":
+			rw.Write([]byte(timeJson))
+		case "Different text":
 			rw.Write([]byte(diffTextJson))
-		case "/Empty json":
+		case "Empty json":
 			rw.Write([]byte(emptyJson))
-		case "/Broken json":
+		case "Broken json":
 			rw.Write([]byte(brokenJson))
-		case "/Code not 200":
+		case "Code not 200":
 			rw.WriteHeader(http.StatusInternalServerError)
 		}
 	}))
@@ -131,11 +229,11 @@ func TestYandex_GetVocabEntryByText(t *testing.T) {
 	ya := NewYandexDict(
 		&mock.Logger{},
 		http.DefaultClient,
-		mockServer.URL+"/",
+		mockServer.URL+"/?key=token",
 	)
 	for _, c := range testCases {
-		t.Run(c.text, func(t *testing.T) {
-			entry, err := ya.GetVocabEntryByText(c.text)
+		t.Run(c.langPair.String()+" "+c.text, func(t *testing.T) {
+			entry, err := ya.GetVocabEntryByText(c.text, c.langPair)
 			if c.expectErr == false && err != nil {
 				t.Errorf("Expected no error, but got %s", err)
 			}
@@ -0,0 +1,104 @@
+package domain
+
+import (
+	"strings"
+	"unicode"
+)
+
+// AnswerMatch is a result of matching the user's answer against the expected ones.
+type AnswerMatch int
+
+const (
+	AnswerWrong AnswerMatch = iota
+	AnswerClose
+	AnswerExact
+)
+
+// MatchTranslation matches the user's answer against all translations of the entry.
+// Ignores case, difference between ё and е and small typos.
+func (e *VocabEntry) MatchTranslation(answer string) AnswerMatch {
+	expected := make([]string, 0, len(e.Translations))
+	for _, t := range e.Translations {
+		expected = append(expected, t.Text)
+	}
+	if len(expected) == 0 && e.MainTranslation != "" {
+		expected = append(expected, e.MainTranslation)
+	}
+	return matchAnswer(answer, expected)
+}
+
+func matchAnswer(answer string, expected []string) AnswerMatch {
+	answer = normalizeAnswer(answer)
+	if answer == "" {
+		return AnswerWrong
+	}
+	res := AnswerWrong
+	for _, e := range expected {
+		e = normalizeAnswer(e)
+		if e == answer {
+			return AnswerExact
+		}
+		if editDistance(e, answer) <= allowedTypos(e) {
+			res = AnswerClose
+		}
+	}
+	return res
+}
+
+// normalizeAnswer lowercases the text, replaces ё with е, drops punctuation and collapses spaces.
+func normalizeAnswer(text string) string {
+	text = strings.ToLower(text)
+	text = strings.ReplaceAll(text, "ё", "е")
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' {
+			return r
+		}
+		return ' '
+	}, text)
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// allowedTypos returns the number of typos tolerated for the expected answer depending on its length.
+func allowedTypos(expected string) int {
+	l := len([]rune(expected))
+	switch {
+	case l <= 3:
+		return 0
+	case l <= 7:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// editDistance returns Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	cur := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ar); i++ {
+		cur[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			cur[j] = minInt(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(br)]
+}
+
+func minInt(values ...int) int {
+	res := values[0]
+	for _, v := range values[1:] {
+		if v < res {
+			res = v
+		}
+	}
+	return res
+}
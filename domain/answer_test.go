@@ -0,0 +1,60 @@
+package domain
+
+import "testing"
+
+func TestVocabEntry_MatchTranslation(t *testing.T) {
+	entry := &VocabEntry{
+		Text:            "hedgehog",
+		MainTranslation: "ёж",
+		Translations: []*Translation{
+			{Text: "ёж", Class: "noun", Position: 0},
+			{Text: "ежовый", Class: "adjective", Position: 1},
+			{Text: "колючее заграждение", Class: "noun", Position: 2},
+		},
+	}
+	testCases := []struct {
+		answer   string
+		expected AnswerMatch
+	}{
+		{answer: "ёж", expected: AnswerExact},
+		{answer: "Еж", expected: AnswerExact},
+		{answer: " ежовый! ", expected: AnswerExact},
+		{answer: "колючее   заграждение", expected: AnswerExact},
+		{answer: "ежовй", expected: AnswerClose},
+		{answer: "колючее заграждене", expected: AnswerClose},
+		{answer: "уж", expected: AnswerWrong},
+		{answer: "кот", expected: AnswerWrong},
+		{answer: "", expected: AnswerWrong},
+		{answer: "?!", expected: AnswerWrong},
+	}
+	for _, c := range testCases {
+		t.Run(c.answer, func(t *testing.T) {
+			res := entry.MatchTranslation(c.answer)
+			if res != c.expected {
+				t.Errorf("Expected match:%v;Actual:%v", c.expected, res)
+			}
+		})
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	testCases := []struct {
+		a, b     string
+		expected int
+	}{
+		{a: "", b: "", expected: 0},
+		{a: "кот", b: "", expected: 3},
+		{a: "кот", b: "кот", expected: 0},
+		{a: "кот", b: "кит", expected: 1},
+		{a: "собака", b: "сабак", expected: 2},
+		{a: "kitten", b: "sitting", expected: 3},
+	}
+	for _, c := range testCases {
+		t.Run(c.a+"-"+c.b, func(t *testing.T) {
+			res := editDistance(c.a, c.b)
+			if res != c.expected {
+				t.Errorf("Expected distance:%v;Actual:%v", c.expected, res)
+			}
+		})
+	}
+}
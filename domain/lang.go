@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// LangPair is a direction of translation, e.g. en-ru means English words translated into Russian.
+type LangPair struct {
+	Source string
+	Target string
+}
+
+// DefaultLangPair is used for the users who have never chosen a language pair.
+var DefaultLangPair = LangPair{Source: "en", Target: "ru"}
+
+// SupportedLangPairs are the language pairs the user can choose from.
+var SupportedLangPairs = []LangPair{
+	{Source: "en", Target: "ru"},
+	{Source: "de", Target: "ru"},
+	{Source: "fr", Target: "ru"},
+	{Source: "es", Target: "ru"},
+	{Source: "it", Target: "ru"},
+	{Source: "en", Target: "uk"},
+}
+
+// langScripts are the scripts the languages are written in.
+var langScripts = map[string]*unicode.RangeTable{
+	"en": unicode.Latin,
+	"de": unicode.Latin,
+	"fr": unicode.Latin,
+	"es": unicode.Latin,
+	"it": unicode.Latin,
+	"ru": unicode.Cyrillic,
+	"uk": unicode.Cyrillic,
+}
+
+// ParseLangPair parses one of the supported language pairs written as "source-target", e.g. "de-ru".
+func ParseLangPair(text string) (LangPair, error) {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(text)), "-")
+	if len(parts) == 2 {
+		pair := LangPair{Source: parts[0], Target: parts[1]}
+		if pair.Supported() {
+			return pair, nil
+		}
+	}
+	return LangPair{}, fmt.Errorf("unsupported language pair: %s", text)
+}
+
+// Supported checks if the pair is one of the supported language pairs.
+func (p LangPair) Supported() bool {
+	for _, s := range SupportedLangPairs {
+		if s == p {
+			return true
+		}
+	}
+	return false
+}
+
+// Reverse returns the pair with swapped source and target languages.
+func (p LangPair) Reverse() LangPair {
+	return LangPair{Source: p.Target, Target: p.Source}
+}
+
+// LookupPair detects the direction in which the text should be looked up.
+// Returns the reversed pair if the text is written in the script of the target language
+// and the source language uses another script, e.g. Cyrillic text for en-ru. Otherwise returns the pair itself.
+func (p LangPair) LookupPair(text string) LangPair {
+	sourceScript, targetScript := langScripts[p.Source], langScripts[p.Target]
+	if targetScript == nil || sourceScript == targetScript {
+		return p
+	}
+	if !writtenIn(text, targetScript) {
+		return p
+	}
+	return p.Reverse()
+}
+
+// writtenIn checks if all letters of the text belong to the script. Text without letters belongs to no script.
+func writtenIn(text string, script *unicode.RangeTable) bool {
+	hasLetters := false
+	for _, r := range text {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		if !unicode.Is(script, r) {
+			return false
+		}
+		hasLetters = true
+	}
+	return hasLetters
+}
+
+func (p LangPair) String() string {
+	return p.Source + "-" + p.Target
+}
@@ -0,0 +1,60 @@
+package domain
+
+import "testing"
+
+func TestParseLangPair(t *testing.T) {
+	testCases := []struct {
+		text      string
+		expected  LangPair
+		expectErr bool
+	}{
+		{text: "en-ru", expected: LangPair{Source: "en", Target: "ru"}},
+		{text: "DE-RU", expected: LangPair{Source: "de", Target: "ru"}},
+		{text: " en-uk ", expected: LangPair{Source: "en", Target: "uk"}},
+		{text: "ru-de", expectErr: true},
+		{text: "en", expectErr: true},
+		{text: "en-ru-uk", expectErr: true},
+		{text: "", expectErr: true},
+	}
+	for _, c := range testCases {
+		t.Run(c.text, func(t *testing.T) {
+			res, err := ParseLangPair(c.text)
+			if !c.expectErr && err != nil {
+				t.Errorf("Expected no error, but got %s", err)
+			}
+			if c.expectErr && err == nil {
+				t.Errorf("Expected error, but got nothing")
+			}
+			if !c.expectErr && res != c.expected {
+				t.Errorf("Expected language pair:%s;Actual:%s", c.expected, res)
+			}
+		})
+	}
+}
+
+func TestLangPair_LookupPair(t *testing.T) {
+	enRu := LangPair{Source: "en", Target: "ru"}
+	enUk := LangPair{Source: "en", Target: "uk"}
+	testCases := []struct {
+		name     string
+		pair     LangPair
+		text     string
+		expected LangPair
+	}{
+		{name: "Latin text", pair: enRu, text: "hedgehog", expected: enRu},
+		{name: "Cyrillic text", pair: enRu, text: "ёж", expected: LangPair{Source: "ru", Target: "en"}},
+		{name: "Cyrillic phrase", pair: enRu, text: "колючее заграждение!", expected: LangPair{Source: "ru", Target: "en"}},
+		{name: "Ukrainian text", pair: enUk, text: "їжак", expected: LangPair{Source: "uk", Target: "en"}},
+		{name: "Mixed scripts", pair: enRu, text: "ёж hedgehog", expected: enRu},
+		{name: "No letters", pair: enRu, text: "42", expected: enRu},
+		{name: "Unknown languages", pair: LangPair{Source: "xx", Target: "yy"}, text: "ёж", expected: LangPair{Source: "xx", Target: "yy"}},
+	}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			res := c.pair.LookupPair(c.text)
+			if res != c.expected {
+				t.Errorf("Expected language pair:%s;Actual:%s", c.expected, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package domain
+
+import "fmt"
+
+const (
+	// LearnedCorrectStreak is the number of consecutive correct answers after which the entry is considered learned.
+	LearnedCorrectStreak = 6
+	// FamiliarCorrectStreak is the number of consecutive correct answers after which the entry is familiar.
+	FamiliarCorrectStreak = 3
+	// LearningCorrectStreak is the number of consecutive correct answers after which the entry is being learned.
+	LearningCorrectStreak = 1
+)
+
+// EntryState is a state of the entry in the user's vocab.
+type EntryState int
+
+const (
+	// EntryStateActive entries are being learned and are reviewed regularly.
+	EntryStateActive EntryState = iota
+	// EntryStateLearned entries are archived and are reviewed only when they are due for refresh.
+	EntryStateLearned
+)
+
+func (s EntryState) String() string {
+	switch s {
+	case EntryStateActive:
+		return "active"
+	case EntryStateLearned:
+		return "learned"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(s))
+	}
+}
+
+// MasteryLevel is how well the user knows the entry.
+type MasteryLevel int
+
+const (
+	MasteryNew MasteryLevel = iota
+	MasteryLearning
+	MasteryFamiliar
+	MasteryLearned
+)
+
+// MasteryLevels are all mastery levels from the lowest to the highest.
+var MasteryLevels = []MasteryLevel{MasteryNew, MasteryLearning, MasteryFamiliar, MasteryLearned}
+
+// MasteryLevelOf returns the mastery level derived from the number of consecutive correct answers.
+func MasteryLevelOf(correctStreak int) MasteryLevel {
+	switch {
+	case correctStreak >= LearnedCorrectStreak:
+		return MasteryLearned
+	case correctStreak >= FamiliarCorrectStreak:
+		return MasteryFamiliar
+	case correctStreak >= LearningCorrectStreak:
+		return MasteryLearning
+	default:
+		return MasteryNew
+	}
+}
+
+func (l MasteryLevel) String() string {
+	switch l {
+	case MasteryNew:
+		return "new"
+	case MasteryLearning:
+		return "learning"
+	case MasteryFamiliar:
+		return "familiar"
+	case MasteryLearned:
+		return "learned"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(l))
+	}
+}
+
+// EntryProgress is the user's progress in learning the entry.
+// CorrectStreak is the number of consecutive answers not graded as "again" in any direction.
+type EntryProgress struct {
+	EntryID       int
+	CorrectStreak int
+	State         EntryState
+}
+
+func (p *EntryProgress) String() string {
+	return fmt.Sprintf("EntryID: %v; CorrectStreak: %v; State: %s", p.EntryID, p.CorrectStreak, p.State)
+}
+
+// Advance updates the progress according to the grade of the answer.
+// The entry becomes learned after LearnedCorrectStreak correct answers in a row
+// and gets back to active when it is forgotten.
+func (p *EntryProgress) Advance(grade Grade) {
+	if grade == GradeAgain {
+		p.CorrectStreak = 0
+		p.State = EntryStateActive
+		return
+	}
+	p.CorrectStreak++
+	if p.CorrectStreak >= LearnedCorrectStreak {
+		p.State = EntryStateLearned
+	}
+}
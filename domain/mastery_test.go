@@ -0,0 +1,58 @@
+package domain
+
+import "testing"
+
+func TestEntryProgress_Advance(t *testing.T) {
+	testCases := []struct {
+		name     string
+		progress EntryProgress
+		grade    Grade
+		expected EntryProgress
+	}{
+		{
+			name:     "Correct answer",
+			progress: EntryProgress{CorrectStreak: 2},
+			grade:    GradeHard,
+			expected: EntryProgress{CorrectStreak: 3},
+		},
+		{
+			name:     "Correct answer makes entry learned",
+			progress: EntryProgress{CorrectStreak: LearnedCorrectStreak - 1},
+			grade:    GradeGood,
+			expected: EntryProgress{CorrectStreak: LearnedCorrectStreak, State: EntryStateLearned},
+		},
+		{
+			name:     "Refresh of learned entry",
+			progress: EntryProgress{CorrectStreak: LearnedCorrectStreak + 2, State: EntryStateLearned},
+			grade:    GradeEasy,
+			expected: EntryProgress{CorrectStreak: LearnedCorrectStreak + 3, State: EntryStateLearned},
+		},
+		{
+			name:     "Learned entry forgotten",
+			progress: EntryProgress{CorrectStreak: LearnedCorrectStreak + 2, State: EntryStateLearned},
+			grade:    GradeAgain,
+			expected: EntryProgress{},
+		},
+	}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			c.progress.Advance(c.grade)
+			if c.progress != c.expected {
+				t.Errorf("Expected progress:%v;Actual:%v", &c.expected, &c.progress)
+			}
+		})
+	}
+}
+
+func TestMasteryLevelOf(t *testing.T) {
+	expected := []MasteryLevel{
+		MasteryNew, MasteryLearning, MasteryLearning, MasteryFamiliar, MasteryFamiliar, MasteryFamiliar,
+		MasteryLearned, MasteryLearned,
+	}
+	for streak, level := range expected {
+		res := MasteryLevelOf(streak)
+		if res != level {
+			t.Errorf("Expected level for streak %v:%s;Actual:%s", streak, level, res)
+		}
+	}
+}
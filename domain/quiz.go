@@ -0,0 +1,103 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ChoiceQuestion is a quiz question with several translations to choose from.
+// Options include the asked entry itself.
+type ChoiceQuestion struct {
+	Entry   *VocabEntry
+	Options []*VocabEntry
+}
+
+func (q *ChoiceQuestion) String() string {
+	builder := new(strings.Builder)
+	builder.WriteString(fmt.Sprintf("Entry: {%s}; Options: [", q.Entry))
+	for i, o := range q.Options {
+		if i != 0 {
+			builder.WriteString("; ")
+		}
+		builder.WriteString(fmt.Sprintf("%v – %s", o.ID, o.MainTranslation))
+	}
+	builder.WriteString("]")
+	return builder.String()
+}
+
+// QuizSession is a quiz of a fixed set of entries from the user's vocab.
+// Entries are asked in the stored order, each entry is asked once.
+type QuizSession struct {
+	ID         int
+	Direction  Direction
+	Entries    []*QuizSessionEntry
+	StartedAt  time.Time
+	FinishedAt *time.Time
+}
+
+// QuizSessionEntry is an entry asked in the quiz session. Grade is nil until the entry is answered.
+type QuizSessionEntry struct {
+	EntryID int
+	Grade   *Grade
+}
+
+func (s *QuizSession) String() string {
+	builder := new(strings.Builder)
+	builder.WriteString(fmt.Sprintf("ID: %v; Direction: %s; StartedAt: %s; Entries: [",
+		s.ID, s.Direction, s.StartedAt.Format(time.RFC3339)))
+	for i, e := range s.Entries {
+		if i != 0 {
+			builder.WriteString("; ")
+		}
+		if e.Grade == nil {
+			builder.WriteString(fmt.Sprintf("%v", e.EntryID))
+		} else {
+			builder.WriteString(fmt.Sprintf("%v – %s", e.EntryID, e.Grade))
+		}
+	}
+	builder.WriteString("]")
+	if s.FinishedAt != nil {
+		builder.WriteString(fmt.Sprintf("; FinishedAt: %s", s.FinishedAt.Format(time.RFC3339)))
+	}
+	return builder.String()
+}
+
+// NextEntryID returns ID of the first not answered entry of the session.
+// Returns false if all the entries are answered.
+func (s *QuizSession) NextEntryID() (int, bool) {
+	for _, e := range s.Entries {
+		if e.Grade == nil {
+			return e.EntryID, true
+		}
+	}
+	return 0, false
+}
+
+// AnsweredQnt returns the number of answered entries of the session.
+func (s *QuizSession) AnsweredQnt() int {
+	qnt := 0
+	for _, e := range s.Entries {
+		if e.Grade != nil {
+			qnt++
+		}
+	}
+	return qnt
+}
+
+// Entry returns the session entry with the given ID or nil if the entry is not in the session.
+func (s *QuizSession) Entry(entryID int) *QuizSessionEntry {
+	for _, e := range s.Entries {
+		if e.EntryID == entryID {
+			return e
+		}
+	}
+	return nil
+}
+
+// QuizSummary is a result of the quiz session.
+// Entries answered with any grade except "again" are considered known.
+type QuizSummary struct {
+	Known  []*VocabEntry
+	Missed []*VocabEntry
+}
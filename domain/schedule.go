@@ -0,0 +1,81 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Grade is a self-assessment of how well the user recalled a vocab entry.
+type Grade int
+
+const (
+	GradeAgain Grade = iota
+	GradeHard
+	GradeGood
+	GradeEasy
+)
+
+func (g Grade) String() string {
+	switch g {
+	case GradeAgain:
+		return "again"
+	case GradeHard:
+		return "hard"
+	case GradeGood:
+		return "good"
+	case GradeEasy:
+		return "easy"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(g))
+	}
+}
+
+// Direction is a direction of the quiz question.
+type Direction int
+
+const (
+	// DirectionForward asks to recall the translation of the english word.
+	DirectionForward Direction = iota
+	// DirectionReverse asks to recall the english word by its translation.
+	DirectionReverse
+)
+
+func (d Direction) String() string {
+	switch d {
+	case DirectionForward:
+		return "forward"
+	case DirectionReverse:
+		return "reverse"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(d))
+	}
+}
+
+// Schedule is a spaced repetition state of the entry in the user's vocab.
+// Each direction of the entry is scheduled separately. Interval is measured in days.
+type Schedule struct {
+	EntryID     int
+	Direction   Direction
+	EaseFactor  float64
+	Interval    int
+	Repetitions int
+	DueAt       time.Time
+}
+
+func (s *Schedule) String() string {
+	return fmt.Sprintf("EntryID: %v; Direction: %s; EaseFactor: %.2f; Interval: %v; Repetitions: %v; DueAt: %s",
+		s.EntryID, s.Direction, s.EaseFactor, s.Interval, s.Repetitions, s.DueAt.Format(time.RFC3339))
+}
+
+// Review is a single recall attempt of the entry from the user's vocab.
+type Review struct {
+	EntryID    int
+	Direction  Direction
+	Grade      Grade
+	ReviewedAt time.Time
+}
+
+func (r *Review) String() string {
+	return fmt.Sprintf("EntryID: %v; Direction: %s; Grade: %s; ReviewedAt: %s",
+		r.EntryID, r.Direction, r.Grade, r.ReviewedAt.Format(time.RFC3339))
+}
@@ -0,0 +1,91 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// TimeOfDay is a time of day measured in minutes since midnight.
+type TimeOfDay int
+
+// ParseTimeOfDay parses time of day in the 24-hour format, e.g. "9:00" or "21:30".
+func ParseTimeOfDay(text string) (TimeOfDay, error) {
+	var hours, minutes int
+	var rest string
+	n, _ := fmt.Sscanf(text, "%d:%d%s", &hours, &minutes, &rest)
+	if n != 2 || hours < 0 || hours > 23 || minutes < 0 || minutes > 59 {
+		return 0, fmt.Errorf("invalid time of day: %s", text)
+	}
+	return TimeOfDay(hours*60 + minutes), nil
+}
+
+// TimeOfDayOf returns time of day of the given time in its location.
+func TimeOfDayOf(t time.Time) TimeOfDay {
+	return TimeOfDay(t.Hour()*60 + t.Minute())
+}
+
+func (t TimeOfDay) String() string {
+	return fmt.Sprintf("%02d:%02d", int(t)/60, int(t)%60)
+}
+
+const (
+	defaultRemindAt = TimeOfDay(9 * 60)
+	defaultTimezone = "UTC"
+)
+
+// UserSettings are the user's preferences.
+// LastRemindedOn is a date in the user's timezone when the last reminder was sent.
+// Zero DailyGoal means the user has not set a goal.
+type UserSettings struct {
+	UserID           int
+	ChatID           int64
+	RemindAt         TimeOfDay
+	Timezone         string
+	RemindersEnabled bool
+	LastRemindedOn   *time.Time
+	DailyGoal        int
+	LangPair         LangPair
+}
+
+// DefaultUserSettings returns settings of the user who has never changed them.
+func DefaultUserSettings(userID int) *UserSettings {
+	return &UserSettings{
+		UserID:   userID,
+		RemindAt: defaultRemindAt,
+		Timezone: defaultTimezone,
+		LangPair: DefaultLangPair,
+	}
+}
+
+// Location returns the user's timezone. Falls back to UTC if the timezone is unknown.
+func (s *UserSettings) Location() *time.Location {
+	location, err := time.LoadLocation(s.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+func (s *UserSettings) String() string {
+	lastRemindedOn := "never"
+	if s.LastRemindedOn != nil {
+		lastRemindedOn = s.LastRemindedOn.Format("2006-01-02")
+	}
+	return fmt.Sprintf("UserID: %v; ChatID: %v; RemindAt: %s; Timezone: %s; RemindersEnabled: %v; "+
+		"LastRemindedOn: %s; DailyGoal: %v; LangPair: %s",
+		s.UserID, s.ChatID, s.RemindAt, s.Timezone, s.RemindersEnabled, lastRemindedOn, s.DailyGoal, s.LangPair)
+}
+
+// Reminder is a message to the user about entries waiting for review.
+// Date is the date of the reminder in the user's timezone, kept as midnight UTC.
+type Reminder struct {
+	UserID int
+	ChatID int64
+	DueQnt int
+	Date   time.Time
+}
+
+func (r *Reminder) String() string {
+	return fmt.Sprintf("UserID: %v; ChatID: %v; DueQnt: %v; Date: %s",
+		r.UserID, r.ChatID, r.DueQnt, r.Date.Format("2006-01-02"))
+}
@@ -0,0 +1,35 @@
+package domain
+
+import "testing"
+
+func TestParseTimeOfDay(t *testing.T) {
+	testCases := []struct {
+		text      string
+		expected  TimeOfDay
+		expectErr bool
+	}{
+		{text: "9:00", expected: 540},
+		{text: "09:05", expected: 545},
+		{text: "23:59", expected: 1439},
+		{text: "0:00", expected: 0},
+		{text: "24:00", expectErr: true},
+		{text: "12:60", expectErr: true},
+		{text: "12", expectErr: true},
+		{text: "12:30pm", expectErr: true},
+		{text: "noon", expectErr: true},
+	}
+	for _, c := range testCases {
+		t.Run(c.text, func(t *testing.T) {
+			res, err := ParseTimeOfDay(c.text)
+			if !c.expectErr && err != nil {
+				t.Errorf("Expected no error, but got %s", err)
+			}
+			if c.expectErr && err == nil {
+				t.Errorf("Expected error, but got nothing")
+			}
+			if !c.expectErr && res != c.expected {
+				t.Errorf("Expected time of day:%s;Actual:%s", c.expected, res)
+			}
+		})
+	}
+}
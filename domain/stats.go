@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Stats is a summary of the user's learning progress.
+// Accuracy is a share of reviews for the last 30 days which were not graded as "again", from 0 to 1.
+// Streak is a number of consecutive days with the daily goal reached ending today or yesterday.
+// If the user has no daily goal then a day with at least one review counts.
+// Days are counted in the user's timezone.
+type Stats struct {
+	TotalEntries     int
+	AddedThisWeek    int
+	ReviewsToday     int
+	ReviewsLastWeek  int
+	ReviewsLastMonth int
+	Accuracy         float64
+	Streak           int
+}
+
+func (s *Stats) String() string {
+	return fmt.Sprintf("TotalEntries: %v; AddedThisWeek: %v; ReviewsToday: %v; ReviewsLastWeek: %v; "+
+		"ReviewsLastMonth: %v; Accuracy: %.2f; Streak: %v",
+		s.TotalEntries, s.AddedThisWeek, s.ReviewsToday, s.ReviewsLastWeek, s.ReviewsLastMonth, s.Accuracy, s.Streak)
+}
+
+// DailyReviews is a number of the user's reviews on the date.
+type DailyReviews struct {
+	Date time.Time
+	Qnt  int
+}
+
+func (d *DailyReviews) String() string {
+	return fmt.Sprintf("Date: %s; Qnt: %v", d.Date.Format("2006-01-02"), d.Qnt)
+}
+
+// DailyProgress is the user's progress towards the daily goal. Zero Goal means the user has not set a goal.
+type DailyProgress struct {
+	Goal         int
+	ReviewsToday int
+	Streak       int
+}
+
+func (p *DailyProgress) String() string {
+	return fmt.Sprintf("Goal: %v; ReviewsToday: %v; Streak: %v", p.Goal, p.ReviewsToday, p.Streak)
+}
@@ -4,29 +4,62 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
+	"unicode/utf8"
 )
 
+// DefaultVocabName is the name of the vocab created for a new user.
+const DefaultVocabName = "Основной"
+
+// Vocab is a named deck of entries. The user can have several vocabs but works with one active vocab at a time.
 type Vocab struct {
 	ID     int
 	UserID int
+	Name   string
+	Active bool
 }
 
 func (v *Vocab) String() string {
-	return fmt.Sprintf("ID: %v; UserID: %v", v.ID, v.UserID)
+	return fmt.Sprintf("ID: %v; UserID: %v; Name: %s; Active: %v", v.ID, v.UserID, v.Name, v.Active)
 }
 
+// maxDescExamples is the number of examples of each translation shown in the full description of the entry.
+const maxDescExamples = 2
+
+// maxFullDescLen is the max number of characters in the full description of the entry.
+// It leaves room for the text the bot adds to the description within the 4096 characters of a Telegram message.
+const maxFullDescLen = 3500
+
+// descCutMark ends the full description which didn't fit maxFullDescLen.
+const descCutMark = "\n…"
+
+// CustomEntrySource is the source of the entries with the user's own translation.
+// Such entry has the only translation without class and no transcription.
+const CustomEntrySource = "user"
+
+// VocabEntry is a dictionary entry.
+// LangPair is the pair of the entry text language and the translations language.
+// Source is the name of the dictionary which produced the entry, it's filled only for the single entries.
+// UserID is the ID of the user owning the custom entry, it's zero for the dictionary entries
+// and it's filled only for the single entries.
+// Mastery is filled only for the entries got from the user's vocab.
 type VocabEntry struct {
 	ID              int
 	Text            string
+	LangPair        LangPair
+	Source          string
+	UserID          int
 	Transcription   string
 	MainTranslation string
 	Translations    []*Translation
+	Mastery         MasteryLevel
 }
 
 func (e *VocabEntry) String() string {
 	builder := new(strings.Builder)
-	builder.WriteString(fmt.Sprintf("ID: %v; Text: %s; Transcription: %s; MainTranslation: %s; Translations: [",
-		e.ID, e.Text, e.Transcription, e.MainTranslation))
+	builder.WriteString(fmt.Sprintf("ID: %v; Text: %s; LangPair: %s; Source: %s; UserID: %v; Transcription: %s; "+
+		"MainTranslation: %s; Translations: [", e.ID, e.Text, e.LangPair, e.Source, e.UserID, e.Transcription,
+		e.MainTranslation))
 	for i, t := range e.Translations {
 		if i != 0 {
 			builder.WriteString("; ")
@@ -37,6 +70,17 @@ func (e *VocabEntry) String() string {
 	return builder.String()
 }
 
+// MainClass returns the class of the main translation.
+// Returns empty string if the entry has no translations.
+func (e *VocabEntry) MainClass() string {
+	for _, t := range e.Translations {
+		if t.Position == 0 {
+			return t.Class
+		}
+	}
+	return ""
+}
+
 func (e *VocabEntry) ShortDesc() string {
 	if e.Transcription != "" {
 		return fmt.Sprintf("[%s]\n%s", e.Transcription, e.MainTranslation)
@@ -45,6 +89,9 @@ func (e *VocabEntry) ShortDesc() string {
 	}
 }
 
+// FullDesc returns the transcription and the translations grouped by class followed by the details of each translation.
+// The synonyms already shown as the translations of the entry are omitted. The details which don't fit
+// maxFullDescLen characters are cut off.
 func (e *VocabEntry) FullDesc(printEntryText bool) string {
 	builder := new(strings.Builder)
 	if printEntryText {
@@ -53,28 +100,207 @@ func (e *VocabEntry) FullDesc(printEntryText bool) string {
 	if e.Transcription != "" {
 		builder.WriteString(fmt.Sprintf("[%s]", e.Transcription))
 	}
+	if len(e.Translations) != 0 {
+		builder.WriteString("\n\n" + e.TranslationsDesc())
+	}
+	limit := maxFullDescLen - utf8.RuneCountInString(descCutMark)
+	length := utf8.RuneCountInString(builder.String())
+	if length > maxFullDescLen {
+		return string([]rune(builder.String())[:limit]) + descCutMark
+	}
+	shown := make(map[string]struct{}, len(e.Translations))
+	for _, t := range e.Translations {
+		shown[strings.ToLower(t.Text)] = struct{}{}
+	}
+	for _, t := range e.Translations {
+		synonyms := t.synonymsExcept(shown)
+		if !t.hasDetails(synonyms) {
+			continue
+		}
+		desc := "\n\n" + t.desc(synonyms)
+		descLength := utf8.RuneCountInString(desc)
+		if length+descLength > limit {
+			if length <= limit {
+				builder.WriteString(descCutMark)
+			}
+			break
+		}
+		builder.WriteString(desc)
+		length += descLength
+	}
+	return builder.String()
+}
+
+// TranslationsDesc returns all translations of the entry grouped by class without the entry text and transcription.
+func (e *VocabEntry) TranslationsDesc() string {
+	builder := new(strings.Builder)
+	for i, g := range e.TranslationGroups() {
+		if i != 0 {
+			builder.WriteString("\n\n")
+		}
+		builder.WriteString(g.String())
+	}
+	return builder.String()
+}
+
+// TranslationGroups returns the translations of the entry ordered by position
+// with the neighbouring translations of the same class put into one group.
+func (e *VocabEntry) TranslationGroups() []*TranslationGroup {
 	sort.Slice(e.Translations, func(i, j int) bool {
 		return e.Translations[i].Position < e.Translations[j].Position
 	})
-	var lastClass string
-	for _, t := range e.Translations {
-		if t.Class != lastClass {
-			builder.WriteString("\n\n" + t.Class + ": " + t.Text)
-			lastClass = t.Class
-		} else {
-			builder.WriteString(", " + t.Text)
+	var groups []*TranslationGroup
+	for i, t := range e.Translations {
+		if i == 0 || t.Class != e.Translations[i-1].Class {
+			groups = append(groups, &TranslationGroup{Class: t.Class})
 		}
+		last := groups[len(groups)-1]
+		last.Texts = append(last.Texts, t.Text)
 	}
-	return builder.String()
+	return groups
+}
+
+// TranslationGroup is the translations of the entry having the same class.
+type TranslationGroup struct {
+	Class string
+	Texts []string
 }
 
+// String returns the class followed by the translations, the class is omitted if it's unknown.
+func (g *TranslationGroup) String() string {
+	if g.Class == "" {
+		return strings.Join(g.Texts, ", ")
+	}
+	return g.Class + ": " + strings.Join(g.Texts, ", ")
+}
+
+// Translation is a translation of the entry text.
+// Gender and Aspect are the grammatical gender of the noun and the aspect of the verb, e.g. "ж" or "несов".
+// Synonyms are the other translations with the same meaning, Meanings are the meanings of the entry text
+// in its language the translation corresponds to.
 type Translation struct {
 	ID       int
 	Text     string
 	Class    string
 	Position int
+	Gender   string
+	Aspect   string
+	Synonyms []string
+	Meanings []string
+	Examples []*Example
+}
+
+// Example is a usage example of the entry text with its translation.
+type Example struct {
+	Text        string
+	Translation string
+}
+
+func (e *Example) String() string {
+	if e.Translation == "" {
+		return e.Text
+	}
+	return e.Text + " — " + e.Translation
+}
+
+// HasDetails tells if the translation has anything besides the text and the class.
+func (t *Translation) HasDetails() bool {
+	return t.hasDetails(t.Synonyms)
+}
+
+// hasDetails is HasDetails with the given synonyms instead of the translation ones.
+func (t *Translation) hasDetails(synonyms []string) bool {
+	return t.Gender != "" || t.Aspect != "" || len(synonyms) != 0 || len(t.Meanings) != 0 || len(t.Examples) != 0
+}
+
+// synonymsExcept returns the synonyms of the translation which are not in the given set of lowercased texts.
+func (t *Translation) synonymsExcept(texts map[string]struct{}) []string {
+	var synonyms []string
+	for _, s := range t.Synonyms {
+		if _, ok := texts[strings.ToLower(s)]; !ok {
+			synonyms = append(synonyms, s)
+		}
+	}
+	return synonyms
+}
+
+// Desc returns the translation with the gender or aspect and the meanings on the first line,
+// followed by the synonyms and the first maxDescExamples examples, e.g. "время (ср) — period, once".
+func (t *Translation) Desc() string {
+	return t.desc(t.Synonyms)
+}
+
+// desc is Desc with the given synonyms instead of the translation ones.
+func (t *Translation) desc(synonyms []string) string {
+	builder := new(strings.Builder)
+	builder.WriteString(t.Text)
+	var grammar []string
+	for _, g := range []string{t.Gender, t.Aspect} {
+		if g != "" {
+			grammar = append(grammar, g)
+		}
+	}
+	if len(grammar) != 0 {
+		builder.WriteString(" (" + strings.Join(grammar, ", ") + ")")
+	}
+	if len(t.Meanings) != 0 {
+		builder.WriteString(" — " + strings.Join(t.Meanings, ", "))
+	}
+	if len(synonyms) != 0 {
+		builder.WriteString("\nсинонимы: " + strings.Join(synonyms, ", "))
+	}
+	for i, e := range t.Examples {
+		if i == maxDescExamples {
+			break
+		}
+		builder.WriteString("\n" + e.String())
+	}
+	return builder.String()
 }
 
 func (t *Translation) String() string {
 	return fmt.Sprintf("ID: %v, Text: %s, Class: %s, Position: %v", t.ID, t.Text, t.Class, t.Position)
 }
+
+// EntriesPage is a part of the entries from the user's vocab. Number of the first page is 0.
+// Total is the number of entries on all pages, e.g. all entries of the vocab or all found entries.
+type EntriesPage struct {
+	Entries []*VocabEntry
+	Number  int
+	Size    int
+	Total   int
+}
+
+// PagesQnt returns the number of pages the vocab entries fit into. Empty vocab has one empty page.
+func (p *EntriesPage) PagesQnt() int {
+	if p.Total == 0 || p.Size <= 0 {
+		return 1
+	}
+	return (p.Total + p.Size - 1) / p.Size
+}
+
+// HasPrev checks if there is a page before this one.
+func (p *EntriesPage) HasPrev() bool {
+	return p.Number > 0
+}
+
+// HasNext checks if there is a page after this one.
+func (p *EntriesPage) HasNext() bool {
+	return p.Number < p.PagesQnt()-1
+}
+
+func (p *EntriesPage) String() string {
+	return fmt.Sprintf("Number: %v; Size: %v; Total: %v; Entries: %v", p.Number, p.Size, p.Total, len(p.Entries))
+}
+
+// ExportEntry is the entry from the user's vocab with all its translations and the learning progress.
+// AddedAt is zero for the entries added before the date of adding was tracked.
+type ExportEntry struct {
+	Entry    *VocabEntry
+	AddedAt  time.Time
+	Progress EntryProgress
+}
+
+func (e *ExportEntry) String() string {
+	return fmt.Sprintf("Entry: %s; AddedAt: %s; Progress: %s", e.Entry, e.AddedAt, &e.Progress)
+}
@@ -0,0 +1,148 @@
+package domain
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestEntriesPage_PagesQnt(t *testing.T) {
+	testCases := []struct {
+		name        string
+		page        EntriesPage
+		expectedQnt int
+		hasPrev     bool
+		hasNext     bool
+	}{
+		{name: "Empty vocab", page: EntriesPage{Size: 10}, expectedQnt: 1},
+		{name: "One full page", page: EntriesPage{Size: 10, Total: 10}, expectedQnt: 1},
+		{name: "First of several pages", page: EntriesPage{Size: 10, Total: 11}, expectedQnt: 2, hasNext: true},
+		{name: "Middle page", page: EntriesPage{Number: 1, Size: 10, Total: 25}, expectedQnt: 3, hasPrev: true, hasNext: true},
+		{name: "Last page", page: EntriesPage{Number: 2, Size: 10, Total: 25}, expectedQnt: 3, hasPrev: true},
+	}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			if qnt := c.page.PagesQnt(); qnt != c.expectedQnt {
+				t.Errorf("Expected pages qnt:%v;Actual:%v", c.expectedQnt, qnt)
+			}
+			if c.page.HasPrev() != c.hasPrev {
+				t.Errorf("Expected has prev:%v;Actual:%v", c.hasPrev, c.page.HasPrev())
+			}
+			if c.page.HasNext() != c.hasNext {
+				t.Errorf("Expected has next:%v;Actual:%v", c.hasNext, c.page.HasNext())
+			}
+		})
+	}
+}
+
+func TestVocabEntry_TranslationGroups(t *testing.T) {
+	entry := &VocabEntry{
+		Translations: []*Translation{
+			{Text: "курс", Class: "noun", Position: 1},
+			{Text: "направляться", Class: "verb", Position: 2},
+			{Text: "голова", Class: "noun", Position: 0},
+			{Text: "главный", Class: "adjective", Position: 3},
+			{Text: "руководить", Class: "verb", Position: 4},
+		},
+	}
+	expected := []*TranslationGroup{
+		{Class: "noun", Texts: []string{"голова", "курс"}},
+		{Class: "verb", Texts: []string{"направляться"}},
+		{Class: "adjective", Texts: []string{"главный"}},
+		{Class: "verb", Texts: []string{"руководить"}},
+	}
+	if groups := entry.TranslationGroups(); !reflect.DeepEqual(expected, groups) {
+		t.Errorf("Expected groups:%v;Actual:%v", expected, groups)
+	}
+	expectedDesc := "noun: голова, курс\n\nverb: направляться\n\nadjective: главный\n\nverb: руководить"
+	if desc := entry.TranslationsDesc(); desc != expectedDesc {
+		t.Errorf("Expected desc:%q;Actual:%q", expectedDesc, desc)
+	}
+	if groups := new(VocabEntry).TranslationGroups(); len(groups) != 0 {
+		t.Errorf("Expected no groups;Actual:%v", groups)
+	}
+	unknownClass := &TranslationGroup{Texts: []string{"ёж", "ёжик"}}
+	if desc := unknownClass.String(); desc != "ёж, ёжик" {
+		t.Errorf("Expected desc without class;Actual:%q", desc)
+	}
+}
+
+func TestVocabEntry_FullDesc(t *testing.T) {
+	entry := &VocabEntry{
+		Text:          "time",
+		Transcription: "taɪm",
+		Translations: []*Translation{
+			{
+				Text:     "время",
+				Class:    "noun",
+				Position: 0,
+				Gender:   "ср",
+				Synonyms: []string{"пора", "Срок", "раз"},
+				Meanings: []string{"period", "hour"},
+				Examples: []*Example{
+					{Text: "prime time", Translation: "прайм-тайм"},
+					{Text: "free time", Translation: "свободное время"},
+					{Text: "local time", Translation: "местное время"},
+				},
+			},
+			{Text: "срок", Class: "noun", Position: 1},
+			{Text: "засекать", Class: "verb", Position: 2, Aspect: "несов"},
+		},
+	}
+	expected := "time\n[taɪm]\n\nnoun: время, срок\n\nverb: засекать" +
+		"\n\nвремя (ср) — period, hour\nсинонимы: пора, раз\nprime time — прайм-тайм\nfree time — свободное время" +
+		"\n\nзасекать (несов)"
+	if desc := entry.FullDesc(true); desc != expected {
+		t.Errorf("Expected desc:%q;Actual:%q", expected, desc)
+	}
+	entry.Translations = entry.Translations[1:2]
+	expected = "[taɪm]\n\nnoun: срок"
+	if desc := entry.FullDesc(false); desc != expected {
+		t.Errorf("Expected desc:%q;Actual:%q", expected, desc)
+	}
+	entry.Translations = []*Translation{{Text: "срок", Class: "noun", Synonyms: []string{"время"}}}
+	expected = "[taɪm]\n\nnoun: срок\n\nсрок\nсинонимы: время"
+	if desc := entry.FullDesc(false); desc != expected {
+		t.Errorf("Expected desc:%q;Actual:%q", expected, desc)
+	}
+}
+
+func TestVocabEntry_FullDescTooLong(t *testing.T) {
+	entry := &VocabEntry{Text: "set", Transcription: "set"}
+	for i := 0; i < 100; i++ {
+		entry.Translations = append(entry.Translations, &Translation{
+			Text:     fmt.Sprintf("набор %v", i),
+			Class:    "noun",
+			Position: i,
+			Meanings: []string{"collection", "group of things"},
+			Examples: []*Example{{Text: "a set of tools", Translation: "набор инструментов"}},
+		})
+	}
+	desc := entry.FullDesc(true)
+	if length := utf8.RuneCountInString(desc); length > maxFullDescLen {
+		t.Errorf("Expected desc length <= %v;Actual:%v", maxFullDescLen, length)
+	}
+	if !strings.HasPrefix(desc, "set\n[set]\n\n"+entry.TranslationsDesc()+"\n\nнабор 0 — collection") {
+		t.Errorf("Expected desc to start with the translations and the details;Actual:%q", desc)
+	}
+	if !strings.HasSuffix(desc, descCutMark) {
+		t.Errorf("Expected desc to end with %q;Actual:%q", descCutMark, desc)
+	}
+
+	for i := 100; i < 1000; i++ {
+		entry.Translations = append(entry.Translations, &Translation{
+			Text:     fmt.Sprintf("набор %v", i),
+			Class:    "noun",
+			Position: i,
+		})
+	}
+	desc = entry.FullDesc(true)
+	if length := utf8.RuneCountInString(desc); length != maxFullDescLen {
+		t.Errorf("Expected desc length:%v;Actual:%v", maxFullDescLen, length)
+	}
+	if !strings.HasSuffix(desc, descCutMark) || strings.Contains(desc, "набор 0 —") {
+		t.Errorf("Expected translations cut without details;Actual:%q", desc)
+	}
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SplitWordList splits the text containing a list of words separated by commas, semicolons or new lines.
+// Words are lowercased and trimmed, empty and repeated words are skipped.
+// Returns the only element for the text without separators.
+func SplitWordList(text string) []string {
+	parts := strings.FieldsFunc(text, func(r rune) bool {
+		return r == ',' || r == ';' || r == '\n'
+	})
+	words := make([]string, 0, len(parts))
+	added := make(map[string]struct{}, len(parts))
+	for _, p := range parts {
+		word := strings.ToLower(strings.Join(strings.Fields(p), " "))
+		if word == "" {
+			continue
+		}
+		if _, ok := added[word]; ok {
+			continue
+		}
+		added[word] = struct{}{}
+		words = append(words, word)
+	}
+	return words
+}
+
+// WordCandidate is a word of the text which may be worth adding to the user's vocab.
+// Rank is the position of the word in the frequency list, the greater the rank the rarer the word.
+// Count is the number of occurrences of the word in the text.
+type WordCandidate struct {
+	Text  string
+	Rank  int
+	Count int
+}
+
+func (c *WordCandidate) String() string {
+	return fmt.Sprintf("Text: %s; Rank: %v; Count: %v", c.Text, c.Rank, c.Count)
+}
+
+// ImportRow is a row of the document the user imports words from.
+// Translation is the user's own translation of the word, it's empty if the row contains the word only.
+type ImportRow struct {
+	Text        string
+	Translation string
+}
+
+func (r *ImportRow) String() string {
+	return fmt.Sprintf("Text: %s; Translation: %s", r.Text, r.Translation)
+}
+
+// ParseImportRows parses the document containing a word per line or "word;translation" rows.
+// Commas and tabs are accepted as the separator as well, only the first separator of the row counts.
+// Words are lowercased and trimmed, empty rows and repeated words are skipped.
+func ParseImportRows(document string) []*ImportRow {
+	document = strings.TrimPrefix(document, "\uFEFF")
+	lines := strings.Split(strings.ReplaceAll(document, "\r\n", "\n"), "\n")
+	rows := make([]*ImportRow, 0, len(lines))
+	added := make(map[string]struct{}, len(lines))
+	for _, l := range lines {
+		text, translation := l, ""
+		if i := strings.IndexAny(l, ";,\t"); i != -1 {
+			text, translation = l[:i], l[i+1:]
+		}
+		text = strings.ToLower(normalizeImportField(text))
+		if text == "" {
+			continue
+		}
+		if _, ok := added[text]; ok {
+			continue
+		}
+		added[text] = struct{}{}
+		rows = append(rows, &ImportRow{Text: text, Translation: normalizeImportField(translation)})
+	}
+	return rows
+}
+
+// normalizeImportField trims the field, removes the quotes spreadsheet apps put around it and collapses spaces.
+func normalizeImportField(field string) string {
+	field = strings.TrimSpace(field)
+	if len(field) >= 2 && strings.HasPrefix(field, `"`) && strings.HasSuffix(field, `"`) {
+		field = strings.ReplaceAll(field[1:len(field)-1], `""`, `"`)
+	}
+	return strings.Join(strings.Fields(field), " ")
+}
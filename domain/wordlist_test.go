@@ -0,0 +1,77 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitWordList(t *testing.T) {
+	testCases := []struct {
+		name     string
+		text     string
+		expected []string
+	}{
+		{name: "Single word", text: "Hedgehog", expected: []string{"hedgehog"}},
+		{name: "Phrase", text: "by the  way", expected: []string{"by the way"}},
+		{name: "Commas", text: "cat, dog,fox", expected: []string{"cat", "dog", "fox"}},
+		{name: "New lines and semicolons", text: "cat\ndog;\n fox \n", expected: []string{"cat", "dog", "fox"}},
+		{name: "Repeated words", text: "cat, Cat, dog", expected: []string{"cat", "dog"}},
+		{name: "Empty items", text: ", ,cat,,", expected: []string{"cat"}},
+		{name: "No words", text: " , ", expected: []string{}},
+	}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			res := SplitWordList(c.text)
+			if !reflect.DeepEqual(c.expected, res) {
+				t.Errorf("Expected words:%q;Actual:%q", c.expected, res)
+			}
+		})
+	}
+}
+
+func TestParseImportRows(t *testing.T) {
+	testCases := []struct {
+		name     string
+		document string
+		expected []*ImportRow
+	}{
+		{
+			name:     "Word per line",
+			document: "Cat\r\ndog\n\n fox \n",
+			expected: []*ImportRow{{Text: "cat"}, {Text: "dog"}, {Text: "fox"}},
+		},
+		{
+			name:     "Words with translations",
+			document: "cat;кошка, кот\ndog,собака\nfox\tлиса\nhedgehog",
+			expected: []*ImportRow{
+				{Text: "cat", Translation: "кошка, кот"},
+				{Text: "dog", Translation: "собака"},
+				{Text: "fox", Translation: "лиса"},
+				{Text: "hedgehog"},
+			},
+		},
+		{
+			name:     "Quoted fields and BOM",
+			document: "\uFEFF\"by the  way\";\"кстати\"\n",
+			expected: []*ImportRow{{Text: "by the way", Translation: "кстати"}},
+		},
+		{
+			name:     "Repeated words and empty rows",
+			document: "cat\nCat;кот\n;кошка\n",
+			expected: []*ImportRow{{Text: "cat"}},
+		},
+		{
+			name:     "Empty document",
+			document: "",
+			expected: []*ImportRow{},
+		},
+	}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			res := ParseImportRows(c.document)
+			if !reflect.DeepEqual(c.expected, res) {
+				t.Errorf("Expected rows:%v;Actual:%v", c.expected, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package export
+
+import (
+	"fmt"
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"html"
+	"io"
+	"strings"
+)
+
+// ankiHeader tells Anki how to import the file: fields are separated with tabs, contain HTML
+// and the third column holds the tags of the note.
+const ankiHeader = "#separator:tab\n#html:true\n#tags column:3\n"
+
+// ankiTag is the tag of all exported notes, the notes are also tagged with the mastery level.
+const ankiTag = "pimpmyvocab"
+
+// ankiFieldEscaper replaces the characters which would break TSV structure.
+var ankiFieldEscaper = strings.NewReplacer("\t", " ", "\r", "", "\n", "<br>")
+
+// WriteAnki writes the entries as tab separated notes which Anki can import as a plain text file.
+// The front of the note is the entry text, the back is the transcription and all translations.
+func WriteAnki(w io.Writer, entries []*domain.ExportEntry) error {
+	_, err := io.WriteString(w, ankiHeader)
+	if err != nil {
+		return fmt.Errorf("writing Anki header: %s", err)
+	}
+	for _, e := range entries {
+		_, err := fmt.Fprintf(w, "%s\t%s\t%s\n", ankiField(e.Entry.Text), ankiBack(e.Entry), ankiTags(e.Entry))
+		if err != nil {
+			return fmt.Errorf("writing Anki note: %s", err)
+		}
+	}
+	return nil
+}
+
+func ankiBack(entry *domain.VocabEntry) string {
+	if entry.Transcription == "" {
+		return ankiTranslations(entry)
+	}
+	return ankiField("["+entry.Transcription+"]") + "<br>" + ankiTranslations(entry)
+}
+
+// ankiTranslations returns the translation groups of the entry, one group per line.
+func ankiTranslations(entry *domain.VocabEntry) string {
+	groups := entry.TranslationGroups()
+	lines := make([]string, 0, len(groups))
+	for _, g := range groups {
+		lines = append(lines, ankiField(g.String()))
+	}
+	return strings.Join(lines, "<br>")
+}
+
+func ankiTags(entry *domain.VocabEntry) string {
+	return ankiTag + " " + ankiTag + "::" + entry.Mastery.String()
+}
+
+func ankiField(text string) string {
+	return ankiFieldEscaper.Replace(html.EscapeString(text))
+}
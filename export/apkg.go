@@ -0,0 +1,277 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"crypto/sha1"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// The .apkg package is a zip archive with the Anki collection of schema version 11 stored as SQLite database
+// and the media map. The collection contains one deck and one note type with a card for each direction.
+
+const (
+	apkgDeckName  = "PimpMyVocab"
+	apkgModelName = "PimpMyVocab"
+	// apkgDefaultDeckID is the ID of the deck every Anki collection has.
+	apkgDefaultDeckID = 1
+	// apkgFieldSeparator separates the fields of the note.
+	apkgFieldSeparator = "\x1f"
+)
+
+const (
+	apkgColTable = "CREATE TABLE col (id integer primary key, crt integer not null, mod integer not null, " +
+		"scm integer not null, ver integer not null, dty integer not null, usn integer not null, ls integer not null, " +
+		"conf text not null, models text not null, decks text not null, dconf text not null, tags text not null)"
+	apkgNotesTable = "CREATE TABLE notes (id integer primary key, guid text not null, mid integer not null, " +
+		"mod integer not null, usn integer not null, tags text not null, flds text not null, sfld integer not null, " +
+		"csum integer not null, flags integer not null, data text not null)"
+	apkgCardsTable = "CREATE TABLE cards (id integer primary key, nid integer not null, did integer not null, " +
+		"ord integer not null, mod integer not null, usn integer not null, type integer not null, " +
+		"queue integer not null, due integer not null, ivl integer not null, factor integer not null, " +
+		"reps integer not null, lapses integer not null, left integer not null, odue integer not null, " +
+		"odid integer not null, flags integer not null, data text not null)"
+	apkgRevlogTable = "CREATE TABLE revlog (id integer primary key, cid integer not null, usn integer not null, " +
+		"ease integer not null, ivl integer not null, lastIvl integer not null, factor integer not null, " +
+		"time integer not null, type integer not null)"
+	apkgGravesTable = "CREATE TABLE graves (usn integer not null, oid integer not null, type integer not null)"
+)
+
+const (
+	apkgTranscription = "{{#Transcription}}<div class=transcription>[{{Transcription}}]</div>{{/Transcription}}"
+	apkgForwardFront  = "<div class=word>{{Front}}</div>" + apkgTranscription
+	apkgForwardBack   = "{{FrontSide}}<hr id=answer>{{Back}}"
+	apkgReverseFront  = "{{Back}}"
+	apkgReverseBack   = "{{FrontSide}}<hr id=answer><div class=word>{{Front}}</div>" + apkgTranscription
+	apkgCSS           = ".card {font-family: arial; font-size: 20px; text-align: center; color: black; " +
+		"background-color: white;}\n.word {font-size: 28px;}\n.transcription {color: gray;}"
+)
+
+// WriteApkg writes the entries as an Anki package. Each entry becomes a note with the entry text,
+// the transcription and the translations. The forward card shows the text with the transcription
+// and the reverse card shows the translations.
+// The note GUID is derived from the entry text and language pair so importing the package again
+// updates the notes instead of duplicating them.
+func WriteApkg(w io.Writer, entries []*domain.ExportEntry) error {
+	return writeApkg(w, entries, time.Now())
+}
+
+func writeApkg(w io.Writer, entries []*domain.ExportEntry, now time.Time) error {
+	tables, err := apkgTables(entries, now)
+	if err != nil {
+		return fmt.Errorf("building Anki collection: %s", err)
+	}
+	collection := new(bytes.Buffer)
+	err = writeSQLite(collection, tables)
+	if err != nil {
+		return fmt.Errorf("writing Anki collection: %s", err)
+	}
+	archive := zip.NewWriter(w)
+	files := []struct {
+		name    string
+		content []byte
+	}{
+		{name: "collection.anki2", content: collection.Bytes()},
+		{name: "media", content: []byte("{}")},
+	}
+	for _, f := range files {
+		fw, err := archive.CreateHeader(&zip.FileHeader{Name: f.name, Method: zip.Deflate, Modified: now})
+		if err != nil {
+			return fmt.Errorf("adding %s to Anki package: %s", f.name, err)
+		}
+		_, err = fw.Write(f.content)
+		if err != nil {
+			return fmt.Errorf("writing %s to Anki package: %s", f.name, err)
+		}
+	}
+	err = archive.Close()
+	if err != nil {
+		return fmt.Errorf("closing Anki package: %s", err)
+	}
+	return nil
+}
+
+// apkgTables returns the tables of the collection. Note type and deck IDs are creation times in milliseconds
+// like Anki does, note and card IDs follow them.
+func apkgTables(entries []*domain.ExportEntry, now time.Time) ([]*sqliteTable, error) {
+	nowMs := now.UnixNano() / int64(time.Millisecond)
+	modelID := nowMs
+	deckID := nowMs + 1
+	firstNoteID := nowMs + 2
+	firstCardID := firstNoteID + int64(len(entries))
+
+	notes := make([]*sqliteRow, 0, len(entries))
+	cards := make([]*sqliteRow, 0, 2*len(entries))
+	for i, e := range entries {
+		noteID := firstNoteID + int64(i)
+		fields := []string{ankiField(e.Entry.Text), ankiField(e.Entry.Transcription), ankiTranslations(e.Entry)}
+		notes = append(notes, &sqliteRow{
+			rowID: noteID,
+			values: []interface{}{
+				nil, apkgGUID(e.Entry), modelID, now.Unix(), -1, " " + ankiTags(e.Entry) + " ",
+				strings.Join(fields, apkgFieldSeparator), e.Entry.Text, apkgChecksum(e.Entry.Text), 0, "",
+			},
+		})
+		for ord := 0; ord < 2; ord++ {
+			cards = append(cards, &sqliteRow{
+				rowID: firstCardID + int64(2*i+ord),
+				values: []interface{}{
+					nil, noteID, deckID, ord, now.Unix(), -1,
+					0, 0, i + 1, 0, 0, 0, 0, 0, 0, 0, 0, "",
+				},
+			})
+		}
+	}
+	col := &sqliteRow{
+		rowID:  1,
+		values: []interface{}{nil, apkgDayStart(now), nowMs, nowMs, 11, 0, 0, 0},
+	}
+	// conf, models, decks and dconf columns are JSON objects
+	for _, v := range []interface{}{
+		apkgConf(modelID, deckID, len(entries)),
+		map[string]interface{}{strconv.FormatInt(modelID, 10): apkgModel(modelID, deckID, now)},
+		map[string]interface{}{
+			strconv.Itoa(apkgDefaultDeckID): apkgDeck(apkgDefaultDeckID, "Default", now),
+			strconv.FormatInt(deckID, 10):   apkgDeck(deckID, apkgDeckName, now),
+		},
+		map[string]interface{}{"1": apkgDeckConf()},
+	} {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, fmt.Errorf("marshalling collection JSON: %s", err)
+		}
+		col.values = append(col.values, string(b))
+	}
+	col.values = append(col.values, "{}")
+	return []*sqliteTable{
+		{name: "col", sql: apkgColTable, rows: []*sqliteRow{col}},
+		{name: "notes", sql: apkgNotesTable, rows: notes},
+		{name: "cards", sql: apkgCardsTable, rows: cards},
+		{name: "revlog", sql: apkgRevlogTable},
+		{name: "graves", sql: apkgGravesTable},
+	}, nil
+}
+
+func apkgConf(modelID, deckID int64, notesQnt int) map[string]interface{} {
+	return map[string]interface{}{
+		"nextPos":       notesQnt + 1,
+		"estTimes":      true,
+		"activeDecks":   []int64{deckID},
+		"sortType":      "noteFld",
+		"timeLim":       0,
+		"sortBackwards": false,
+		"addToCur":      true,
+		"curDeck":       deckID,
+		"newBury":       true,
+		"newSpread":     0,
+		"dueCounts":     true,
+		"curModel":      modelID,
+		"collapseTime":  1200,
+	}
+}
+
+func apkgModel(modelID, deckID int64, now time.Time) map[string]interface{} {
+	field := func(name string, ord int) map[string]interface{} {
+		return map[string]interface{}{
+			"name": name, "ord": ord, "sticky": false, "rtl": false, "font": "Arial", "size": 20, "media": []string{},
+		}
+	}
+	template := func(name string, ord int, front, back string) map[string]interface{} {
+		return map[string]interface{}{
+			"name": name, "ord": ord, "qfmt": front, "afmt": back, "did": nil, "bqfmt": "", "bafmt": "",
+		}
+	}
+	return map[string]interface{}{
+		"id":    modelID,
+		"name":  apkgModelName,
+		"type":  0,
+		"mod":   now.Unix(),
+		"usn":   -1,
+		"sortf": 0,
+		"did":   deckID,
+		"flds":  []interface{}{field("Front", 0), field("Transcription", 1), field("Back", 2)},
+		"tmpls": []interface{}{
+			template("Forward", 0, apkgForwardFront, apkgForwardBack),
+			template("Reverse", 1, apkgReverseFront, apkgReverseBack),
+		},
+		"css": apkgCSS,
+		"latexPre": "\\documentclass[12pt]{article}\n\\special{papersize=3in,5in}\n\\usepackage{amssymb,amsmath}\n" +
+			"\\pagestyle{empty}\n\\setlength{\\parindent}{0in}\n\\begin{document}\n",
+		"latexPost": "\\end{document}",
+		"latexsvg":  false,
+		// req tells which fields must be filled to generate the card of each template.
+		"req":  []interface{}{[]interface{}{0, "any", []int{0}}, []interface{}{1, "any", []int{2}}},
+		"tags": []string{},
+		"vers": []string{},
+	}
+}
+
+func apkgDeck(id int64, name string, now time.Time) map[string]interface{} {
+	return map[string]interface{}{
+		"id":               id,
+		"name":             name,
+		"mod":              now.Unix(),
+		"usn":              -1,
+		"lrnToday":         []int{0, 0},
+		"revToday":         []int{0, 0},
+		"newToday":         []int{0, 0},
+		"timeToday":        []int{0, 0},
+		"collapsed":        false,
+		"browserCollapsed": false,
+		"desc":             "",
+		"dyn":              0,
+		"conf":             1,
+		"extendNew":        10,
+		"extendRev":        50,
+	}
+}
+
+func apkgDeckConf() map[string]interface{} {
+	return map[string]interface{}{
+		"id":       1,
+		"name":     "Default",
+		"mod":      0,
+		"usn":      0,
+		"maxTaken": 60,
+		"autoplay": true,
+		"timer":    0,
+		"replayq":  true,
+		"dyn":      false,
+		"new": map[string]interface{}{
+			"bury": true, "delays": []int{1, 10}, "initialFactor": 2500, "ints": []int{1, 4, 7},
+			"order": 1, "perDay": 20, "separate": true,
+		},
+		"lapse": map[string]interface{}{
+			"delays": []int{10}, "leechAction": 0, "leechFails": 8, "minInt": 1, "mult": 0,
+		},
+		"rev": map[string]interface{}{
+			"bury": true, "ease4": 1.3, "fuzz": 0.05, "ivlFct": 1, "maxIvl": 36500, "minSpace": 1, "perDay": 100,
+		},
+	}
+}
+
+// apkgGUID returns the stable note GUID of the entry.
+func apkgGUID(entry *domain.VocabEntry) string {
+	sum := sha1.Sum([]byte(entry.LangPair.String() + ":" + entry.Text))
+	return hex.EncodeToString(sum[:8])
+}
+
+// apkgChecksum returns the checksum Anki uses to find duplicates: the first 8 hex digits of SHA1 of the sort field.
+func apkgChecksum(sortField string) int64 {
+	sum := sha1.Sum([]byte(sortField))
+	return int64(binary.BigEndian.Uint32(sum[:4]))
+}
+
+// apkgDayStart returns the collection creation time: the start of the day in seconds.
+func apkgDayStart(now time.Time) int64 {
+	year, month, day := now.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, now.Location()).Unix()
+}
@@ -0,0 +1,285 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"io/ioutil"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWriteApkg(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := writeApkg(buf, testEntries(), time.Date(2020, 5, 17, 10, 0, 0, 0, time.UTC))
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Error reading package: %s", err)
+	}
+	files := make(map[string][]byte)
+	for _, f := range archive.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatalf("Error opening %s: %s", f.Name, err)
+		}
+		files[f.Name], err = ioutil.ReadAll(r)
+		if err != nil {
+			t.Fatalf("Error reading %s: %s", f.Name, err)
+		}
+		_ = r.Close()
+	}
+	if string(files["media"]) != "{}" {
+		t.Errorf("Expected empty media map, but got %q", files["media"])
+	}
+	collection, ok := files["collection.anki2"]
+	if !ok {
+		t.Fatalf("Package has no collection")
+	}
+	if !bytes.HasPrefix(collection, []byte("SQLite format 3\x00")) {
+		t.Errorf("Collection has no SQLite header")
+	}
+	pagesQnt := int(binary.BigEndian.Uint32(collection[28:]))
+	if len(collection) != pagesQnt*sqlitePageSize {
+		t.Fatalf("Expected collection size %v, but got %v", pagesQnt*sqlitePageSize, len(collection))
+	}
+	tables := readSQLiteTables(t, collection)
+	for name, expected := range map[string]int{"col": 1, "notes": 2, "cards": 4, "revlog": 0, "graves": 0} {
+		rows, ok := tables[name]
+		if !ok {
+			t.Errorf("Collection has no %s table", name)
+			continue
+		}
+		if len(rows) != expected {
+			t.Errorf("Expected %s rows:%v;Actual:%v", name, expected, len(rows))
+		}
+	}
+	if notes := tables["notes"]; len(notes) == 2 {
+		fields := strings.Split(notes[0].values[6].(string), apkgFieldSeparator)
+		expected := []string{"head", "hed", "noun: голова, курс<br>verb: направляться"}
+		if !reflect.DeepEqual(expected, fields) {
+			t.Errorf("Expected note fields:%q;Actual:%q", expected, fields)
+		}
+	}
+	if cols := tables["col"]; len(cols) == 1 {
+		models := make(map[string]struct {
+			Name  string
+			Flds  []struct{ Name string }
+			Tmpls []struct{ Name string }
+		})
+		err = json.Unmarshal([]byte(cols[0].values[9].(string)), &models)
+		if err != nil {
+			t.Fatalf("Error unmarshalling models: %s", err)
+		}
+		if len(models) != 1 {
+			t.Fatalf("Expected models qnt:1;Actual:%v", len(models))
+		}
+		for _, m := range models {
+			if m.Name != apkgModelName || len(m.Flds) != 3 || len(m.Tmpls) != 2 {
+				t.Errorf("Expected model %s with 3 fields and 2 templates;Actual:%+v", apkgModelName, m)
+			}
+		}
+	}
+}
+
+func TestWriteSQLite(t *testing.T) {
+	rows := make([]*sqliteRow, 0, 3000)
+	for i := 1; i <= 3000; i++ {
+		rows = append(rows, &sqliteRow{rowID: int64(i), values: []interface{}{nil, int64(i), "word " + strconv.Itoa(i)}})
+	}
+	long := strings.Repeat("overflow ", 1500)
+	rows[10].values[2] = long
+	buf := new(bytes.Buffer)
+	err := writeSQLite(buf, []*sqliteTable{
+		{name: "words", sql: "CREATE TABLE words (id integer primary key, n integer, text text)", rows: rows},
+		{name: "empty", sql: "CREATE TABLE empty (n integer)"},
+	})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	tables := readSQLiteTables(t, buf.Bytes())
+	if len(tables["empty"]) != 0 {
+		t.Errorf("Expected no rows in empty table;Actual:%v", len(tables["empty"]))
+	}
+	words := tables["words"]
+	if len(words) != len(rows) {
+		t.Fatalf("Expected rows qnt:%v;Actual:%v", len(rows), len(words))
+	}
+	for i, r := range words {
+		if !reflect.DeepEqual(rows[i], r) {
+			t.Fatalf("Expected row:%v;Actual:%v", rows[i], r)
+		}
+	}
+}
+
+func TestAppendVarint(t *testing.T) {
+	testCases := []struct {
+		value    uint64
+		expected []byte
+	}{
+		{value: 0, expected: []byte{0x00}},
+		{value: 127, expected: []byte{0x7f}},
+		{value: 128, expected: []byte{0x81, 0x00}},
+		{value: 16383, expected: []byte{0xff, 0x7f}},
+		{value: 16384, expected: []byte{0x81, 0x80, 0x00}},
+		{value: 1<<64 - 1, expected: []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+	}
+	for _, c := range testCases {
+		if res := appendVarint(nil, c.value); !reflect.DeepEqual(c.expected, res) {
+			t.Errorf("Value %v: expected %x;Actual:%x", c.value, c.expected, res)
+		}
+	}
+}
+
+func TestSqliteRecord(t *testing.T) {
+	res, err := sqliteRecord([]interface{}{nil, 0, 1, 2, -300, int64(1) << 40, "ab"})
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	expected := []byte{
+		0x08, 0x00, 0x08, 0x09, 0x01, 0x02, 0x05, 0x11, // header: size and serial types
+		0x02, 0xfe, 0xd4, 0x01, 0x00, 0x00, 0x00, 0x00, 0x00, 'a', 'b',
+	}
+	if !reflect.DeepEqual(expected, res) {
+		t.Errorf("Expected:%x;Actual:%x", expected, res)
+	}
+	_, err = sqliteRecord([]interface{}{1.5})
+	if err == nil {
+		t.Errorf("Expected error for unsupported type, but got nothing")
+	}
+}
+
+func TestSqliteLocalPayloadSize(t *testing.T) {
+	testCases := []struct {
+		size     int
+		expected int
+	}{
+		{size: 100, expected: 100},
+		{size: sqlitePageSize - 35, expected: sqlitePageSize - 35},
+		{size: 5000, expected: 489 + (5000-489)%(sqlitePageSize-4)},
+		{size: 4500, expected: 489},
+	}
+	for _, c := range testCases {
+		if res := sqliteLocalPayloadSize(c.size); res != c.expected {
+			t.Errorf("Size %v: expected %v;Actual:%v", c.size, c.expected, res)
+		}
+	}
+}
+
+// readSQLiteTables decodes the database written by writeSQLite and returns the rows of its tables by the table name.
+func readSQLiteTables(t *testing.T, db []byte) map[string][]*sqliteRow {
+	t.Helper()
+	tables := make(map[string][]*sqliteRow)
+	for _, s := range readSQLiteTree(t, db, 1) {
+		if len(s.values) != 5 || s.values[0] != "table" {
+			t.Fatalf("Unexpected schema row:%v", s.values)
+		}
+		tables[s.values[1].(string)] = readSQLiteTree(t, db, int(s.values[3].(int64)))
+	}
+	return tables
+}
+
+// readSQLiteTree returns the rows of the table b-tree with the given root page checking they're ordered by rowid.
+func readSQLiteTree(t *testing.T, db []byte, root int) []*sqliteRow {
+	t.Helper()
+	page := db[(root-1)*sqlitePageSize : root*sqlitePageSize]
+	offset := 0
+	if root == 1 {
+		offset = sqliteHeaderSize
+	}
+	cellsQnt := int(binary.BigEndian.Uint16(page[offset+3:]))
+	var rows []*sqliteRow
+	switch page[offset] {
+	case sqliteLeafTablePage:
+		for i := 0; i < cellsQnt; i++ {
+			cell := page[binary.BigEndian.Uint16(page[offset+sqliteLeafHeaderSize+2*i:]):]
+			size, n := readVarint(cell)
+			rowID, m := readVarint(cell[n:])
+			cell = cell[n+m:]
+			local := sqliteLocalPayloadSize(int(size))
+			payload := append([]byte(nil), cell[:local]...)
+			for next := 0; len(payload) < int(size); {
+				if next == 0 {
+					next = int(binary.BigEndian.Uint32(cell[local:]))
+				}
+				overflow := db[(next-1)*sqlitePageSize : next*sqlitePageSize]
+				chunk := overflow[4:]
+				if rest := int(size) - len(payload); rest < len(chunk) {
+					chunk = chunk[:rest]
+				}
+				payload = append(payload, chunk...)
+				next = int(binary.BigEndian.Uint32(overflow))
+			}
+			rows = append(rows, &sqliteRow{rowID: int64(rowID), values: readSQLiteRecord(t, payload)})
+		}
+	case sqliteInteriorTablePage:
+		for i := 0; i < cellsQnt; i++ {
+			cell := page[binary.BigEndian.Uint16(page[offset+sqliteInteriorHeader+2*i:]):]
+			child := readSQLiteTree(t, db, int(binary.BigEndian.Uint32(cell)))
+			if maxRowID, _ := readVarint(cell[4:]); len(child) > 0 && child[len(child)-1].rowID != int64(maxRowID) {
+				t.Fatalf("Expected child max rowid:%v;Actual:%v", maxRowID, child[len(child)-1].rowID)
+			}
+			rows = append(rows, child...)
+		}
+		rows = append(rows, readSQLiteTree(t, db, int(binary.BigEndian.Uint32(page[offset+8:])))...)
+	default:
+		t.Fatalf("Unexpected type %x of page %v", page[offset], root)
+	}
+	for i := 1; i < len(rows); i++ {
+		if rows[i-1].rowID >= rows[i].rowID {
+			t.Fatalf("Rows of page %v aren't ordered by rowid", root)
+		}
+	}
+	return rows
+}
+
+// readSQLiteRecord decodes the record written by sqliteRecord, integers are returned as int64.
+func readSQLiteRecord(t *testing.T, record []byte) []interface{} {
+	t.Helper()
+	headerSize, n := readVarint(record)
+	header, body := record[n:headerSize], record[headerSize:]
+	var values []interface{}
+	for len(header) > 0 {
+		serialType, n := readVarint(header)
+		header = header[n:]
+		switch {
+		case serialType == 0:
+			values = append(values, nil)
+		case serialType == 8 || serialType == 9:
+			values = append(values, int64(serialType-8))
+		case serialType >= 1 && serialType <= 6:
+			size := []int{0, 1, 2, 3, 4, 6, 8}[serialType]
+			v := int64(int8(body[0]))
+			for _, b := range body[1:size] {
+				v = v<<8 | int64(b)
+			}
+			values = append(values, v)
+			body = body[size:]
+		case serialType >= 13 && serialType%2 == 1:
+			size := int(serialType-13) / 2
+			values = append(values, string(body[:size]))
+			body = body[size:]
+		default:
+			t.Fatalf("Unexpected serial type %v", serialType)
+		}
+	}
+	return values
+}
+
+// readVarint decodes the SQLite variable-length integer and returns it with its length.
+func readVarint(b []byte) (uint64, int) {
+	var v uint64
+	for i := 0; i < 8; i++ {
+		v = v<<7 | uint64(b[i]&0x7f)
+		if b[i]&0x80 == 0 {
+			return v, i + 1
+		}
+	}
+	return v<<8 | uint64(b[8]), 9
+}
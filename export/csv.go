@@ -0,0 +1,39 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"io"
+	"strconv"
+)
+
+var csvHeader = []string{"text", "transcription", "translations", "added_at", "mastery", "correct_streak", "state"}
+
+// WriteCSV writes the entries as CSV with a header row. Translations of the entry take one column.
+func WriteCSV(w io.Writer, entries []*domain.ExportEntry) error {
+	writer := csv.NewWriter(w)
+	err := writer.Write(csvHeader)
+	if err != nil {
+		return fmt.Errorf("writing CSV header: %s", err)
+	}
+	for _, e := range entries {
+		err := writer.Write([]string{
+			e.Entry.Text,
+			e.Entry.Transcription,
+			translationsDesc(e.Entry, "; "),
+			formatDate(e.AddedAt),
+			e.Entry.Mastery.String(),
+			strconv.Itoa(e.Progress.CorrectStreak),
+			e.Progress.State.String(),
+		})
+		if err != nil {
+			return fmt.Errorf("writing CSV row: %s", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("flushing CSV: %s", err)
+	}
+	return nil
+}
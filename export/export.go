@@ -0,0 +1,90 @@
+// Package export provides serializers of the user's vocab into files which can be used outside of the bot.
+package export
+
+import (
+	"fmt"
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"io"
+	"strings"
+	"time"
+)
+
+// Format is a format of the exported file.
+type Format int
+
+const (
+	FormatCSV Format = iota
+	FormatJSON
+	FormatAnki
+	FormatApkg
+)
+
+// Formats are all supported formats.
+var Formats = []Format{FormatCSV, FormatJSON, FormatAnki, FormatApkg}
+
+// dateLayout is a layout of the date the entry was added in the text formats.
+const dateLayout = "2006-01-02"
+
+func (f Format) String() string {
+	switch f {
+	case FormatCSV:
+		return "CSV"
+	case FormatJSON:
+		return "JSON"
+	case FormatAnki:
+		return "Anki TXT"
+	case FormatApkg:
+		return "Anki APKG"
+	default:
+		return fmt.Sprintf("unknown(%d)", int(f))
+	}
+}
+
+// FileExtension returns the extension of the file in the format including the leading dot.
+func (f Format) FileExtension() string {
+	switch f {
+	case FormatJSON:
+		return ".json"
+	case FormatAnki:
+		return ".txt"
+	case FormatApkg:
+		return ".apkg"
+	default:
+		return ".csv"
+	}
+}
+
+// Write serializes the entries in the given format to w.
+func Write(w io.Writer, format Format, entries []*domain.ExportEntry) error {
+	switch format {
+	case FormatCSV:
+		return WriteCSV(w, entries)
+	case FormatJSON:
+		return WriteJSON(w, entries)
+	case FormatAnki:
+		return WriteAnki(w, entries)
+	case FormatApkg:
+		return WriteApkg(w, entries)
+	default:
+		return fmt.Errorf("unsupported export format %s", format)
+	}
+}
+
+// translationsDesc returns all translations of the entry in one line, the groups of different classes
+// are separated with the given separator, e.g. "noun: голова, курс; verb: направляться".
+func translationsDesc(entry *domain.VocabEntry, sep string) string {
+	groups := entry.TranslationGroups()
+	descs := make([]string, 0, len(groups))
+	for _, g := range groups {
+		descs = append(descs, g.String())
+	}
+	return strings.Join(descs, sep)
+}
+
+// formatDate returns the date the entry was added or empty string if it's unknown.
+func formatDate(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.Format(dateLayout)
+}
@@ -0,0 +1,136 @@
+package export
+
+import (
+	"bytes"
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"testing"
+	"time"
+)
+
+func testEntries() []*domain.ExportEntry {
+	return []*domain.ExportEntry{
+		{
+			Entry: &domain.VocabEntry{
+				Text:          "head",
+				Transcription: "hed",
+				Mastery:       domain.MasteryFamiliar,
+				Translations: []*domain.Translation{
+					{Text: "направляться", Class: "verb", Position: 2},
+					{Text: "голова", Class: "noun", Position: 0},
+					{Text: "курс", Class: "noun", Position: 1},
+				},
+			},
+			AddedAt:  time.Date(2020, 5, 17, 10, 0, 0, 0, time.UTC),
+			Progress: domain.EntryProgress{CorrectStreak: 4},
+		},
+		{
+			Entry: &domain.VocabEntry{
+				Text:         "by the way",
+				Mastery:      domain.MasteryLearned,
+				Translations: []*domain.Translation{{Text: "кстати, между прочим", Class: "adverb"}},
+			},
+			Progress: domain.EntryProgress{CorrectStreak: 6, State: domain.EntryStateLearned},
+		},
+	}
+}
+
+func TestWrite(t *testing.T) {
+	testCases := []struct {
+		format   Format
+		expected string
+	}{
+		{
+			format: FormatCSV,
+			expected: "text,transcription,translations,added_at,mastery,correct_streak,state\n" +
+				"head,hed,\"noun: голова, курс; verb: направляться\",2020-05-17,familiar,4,active\n" +
+				"by the way,,\"adverb: кстати, между прочим\",,learned,6,learned\n",
+		},
+		{
+			format: FormatJSON,
+			expected: `[
+  {
+    "text": "head",
+    "transcription": "hed",
+    "translations": [
+      {
+        "class": "noun",
+        "texts": [
+          "голова",
+          "курс"
+        ]
+      },
+      {
+        "class": "verb",
+        "texts": [
+          "направляться"
+        ]
+      }
+    ],
+    "added_at": "2020-05-17",
+    "mastery": "familiar",
+    "correct_streak": 4,
+    "state": "active"
+  },
+  {
+    "text": "by the way",
+    "translations": [
+      {
+        "class": "adverb",
+        "texts": [
+          "кстати, между прочим"
+        ]
+      }
+    ],
+    "mastery": "learned",
+    "correct_streak": 6,
+    "state": "learned"
+  }
+]
+`,
+		},
+		{
+			format: FormatAnki,
+			expected: "#separator:tab\n#html:true\n#tags column:3\n" +
+				"head\t[hed]<br>noun: голова, курс<br>verb: направляться\tpimpmyvocab pimpmyvocab::familiar\n" +
+				"by the way\tadverb: кстати, между прочим\tpimpmyvocab pimpmyvocab::learned\n",
+		},
+	}
+	for _, c := range testCases {
+		t.Run(c.format.String(), func(t *testing.T) {
+			buf := new(bytes.Buffer)
+			err := Write(buf, c.format, testEntries())
+			if err != nil {
+				t.Fatalf("Expected no error, but got %s", err)
+			}
+			if buf.String() != c.expected {
+				t.Errorf("Expected:\n%s\nActual:\n%s", c.expected, buf.String())
+			}
+		})
+	}
+}
+
+func TestWrite_EmptyVocab(t *testing.T) {
+	buf := new(bytes.Buffer)
+	err := Write(buf, FormatJSON, nil)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if buf.String() != "[]\n" {
+		t.Errorf("Expected empty JSON array, but got %q", buf.String())
+	}
+}
+
+func TestWrite_UnsupportedFormat(t *testing.T) {
+	err := Write(new(bytes.Buffer), Format(-1), testEntries())
+	if err == nil {
+		t.Errorf("Expected error, but got nothing")
+	}
+}
+
+func TestAnkiField(t *testing.T) {
+	res := ankiField("<b>tab\there</b>\nnext")
+	expected := "&lt;b&gt;tab here&lt;/b&gt;<br>next"
+	if res != expected {
+		t.Errorf("Expected:%q;Actual:%q", expected, res)
+	}
+}
@@ -0,0 +1,50 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"io"
+)
+
+type jsonEntry struct {
+	Text          string             `json:"text"`
+	Transcription string             `json:"transcription,omitempty"`
+	Translations  []*jsonTranslation `json:"translations"`
+	AddedAt       string             `json:"added_at,omitempty"`
+	Mastery       string             `json:"mastery"`
+	CorrectStreak int                `json:"correct_streak"`
+	State         string             `json:"state"`
+}
+
+type jsonTranslation struct {
+	Class string   `json:"class"`
+	Texts []string `json:"texts"`
+}
+
+// WriteJSON writes the entries as an indented JSON array. Translations are grouped by class.
+func WriteJSON(w io.Writer, entries []*domain.ExportEntry) error {
+	out := make([]*jsonEntry, 0, len(entries))
+	for _, e := range entries {
+		entry := &jsonEntry{
+			Text:          e.Entry.Text,
+			Transcription: e.Entry.Transcription,
+			Translations:  []*jsonTranslation{},
+			AddedAt:       formatDate(e.AddedAt),
+			Mastery:       e.Entry.Mastery.String(),
+			CorrectStreak: e.Progress.CorrectStreak,
+			State:         e.Progress.State.String(),
+		}
+		for _, g := range e.Entry.TranslationGroups() {
+			entry.Translations = append(entry.Translations, &jsonTranslation{Class: g.Class, Texts: g.Texts})
+		}
+		out = append(out, entry)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	err := encoder.Encode(out)
+	if err != nil {
+		return fmt.Errorf("encoding JSON: %s", err)
+	}
+	return nil
+}
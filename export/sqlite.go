@@ -0,0 +1,331 @@
+package export
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+)
+
+// The functions below write a minimal SQLite database file, see https://www.sqlite.org/fileformat.html
+// Only what's needed to ship a freshly built database is supported: tables without indexes,
+// rows given in the rowid order and no free pages. Reading and updating the file is left to SQLite itself.
+
+const (
+	sqlitePageSize   = 4096
+	sqliteHeaderSize = 100
+	// sqliteVersion is the version of SQLite library written to the file header.
+	sqliteVersion = 3031001
+
+	sqliteInteriorTablePage = 0x05
+	sqliteLeafTablePage     = 0x0d
+	sqliteLeafHeaderSize    = 8
+	sqliteInteriorHeader    = 12
+)
+
+// sqliteTable is a table of the database. Rows must be ordered by rowid.
+type sqliteTable struct {
+	name string
+	sql  string
+	rows []*sqliteRow
+}
+
+// sqliteRow is a row of the table. Values can be nil, int, int64 or string.
+// The value of the "integer primary key" column must be nil since the column is an alias of rowid.
+type sqliteRow struct {
+	rowID  int64
+	values []interface{}
+}
+
+// sqliteWriter collects the pages of the database. Pages are numbered from 1, the first one is the schema table.
+type sqliteWriter struct {
+	pages [][]byte
+}
+
+// sqliteChild is a page of the table b-tree and the greatest rowid stored in its subtree.
+type sqliteChild struct {
+	page     int
+	maxRowID int64
+}
+
+// writeSQLite writes the database containing the given tables.
+func writeSQLite(w io.Writer, tables []*sqliteTable) error {
+	sw := &sqliteWriter{pages: [][]byte{nil}}
+	schema := make([]*sqliteRow, 0, len(tables))
+	for i, t := range tables {
+		root, err := sw.writeTable(t.rows)
+		if err != nil {
+			return fmt.Errorf("writing table %s: %s", t.name, err)
+		}
+		schema = append(schema, &sqliteRow{
+			rowID:  int64(i + 1),
+			values: []interface{}{"table", t.name, t.name, root, t.sql},
+		})
+	}
+	cells, err := sw.leafCells(schema)
+	if err != nil {
+		return fmt.Errorf("writing schema table: %s", err)
+	}
+	if !sqlitePageFits(cells, sqliteHeaderSize+sqliteLeafHeaderSize, 0) {
+		return fmt.Errorf("schema table doesn't fit the first page")
+	}
+	sw.pages[0] = sqlitePage(sqliteLeafTablePage, cells, sqliteHeaderSize, 0)
+	sw.writeHeader()
+	for _, p := range sw.pages {
+		_, err := w.Write(p)
+		if err != nil {
+			return fmt.Errorf("writing page: %s", err)
+		}
+	}
+	return nil
+}
+
+func (w *sqliteWriter) writeHeader() {
+	h := w.pages[0]
+	copy(h, "SQLite format 3\x00")
+	binary.BigEndian.PutUint16(h[16:], sqlitePageSize)
+	h[18], h[19] = 1, 1 // legacy journal mode for both writing and reading
+	h[21], h[22], h[23] = 64, 32, 32
+	binary.BigEndian.PutUint32(h[24:], 1) // file change counter
+	binary.BigEndian.PutUint32(h[28:], uint32(len(w.pages)))
+	binary.BigEndian.PutUint32(h[40:], 1) // schema cookie
+	binary.BigEndian.PutUint32(h[44:], 4) // schema format number
+	binary.BigEndian.PutUint32(h[56:], 1) // UTF-8 text encoding
+	binary.BigEndian.PutUint32(h[92:], 1) // change counter the version below is valid for
+	binary.BigEndian.PutUint32(h[96:], sqliteVersion)
+}
+
+func (w *sqliteWriter) addPage(page []byte) int {
+	w.pages = append(w.pages, page)
+	return len(w.pages)
+}
+
+// writeTable writes the table b-tree and returns the number of its root page.
+func (w *sqliteWriter) writeTable(rows []*sqliteRow) (int, error) {
+	cells, err := w.leafCells(rows)
+	if err != nil {
+		return 0, err
+	}
+	var level []sqliteChild
+	var pageCells [][]byte
+	var maxRowID int64
+	for i, c := range cells {
+		if !sqlitePageFits(append(pageCells, c), sqliteLeafHeaderSize, 0) {
+			level = append(level, sqliteChild{w.addPage(sqlitePage(sqliteLeafTablePage, pageCells, 0, 0)), maxRowID})
+			pageCells = nil
+		}
+		pageCells = append(pageCells, c)
+		maxRowID = rows[i].rowID
+	}
+	level = append(level, sqliteChild{w.addPage(sqlitePage(sqliteLeafTablePage, pageCells, 0, 0)), maxRowID})
+	for len(level) > 1 {
+		level = w.writeInteriorLevel(level)
+	}
+	return level[0].page, nil
+}
+
+// writeInteriorLevel writes the interior pages pointing to the given children and returns the written pages.
+// The last child of each page becomes its right-most pointer, the others are stored as cells.
+func (w *sqliteWriter) writeInteriorLevel(children []sqliteChild) []sqliteChild {
+	var level []sqliteChild
+	var pageCells [][]byte
+	last := children[0]
+	for _, c := range children[1:] {
+		cell := interiorCell(last)
+		if !sqlitePageFits(append(pageCells, cell), sqliteInteriorHeader, 0) {
+			level = append(level, sqliteChild{w.addPage(sqlitePage(sqliteInteriorTablePage, pageCells, 0, last.page)), last.maxRowID})
+			pageCells = nil
+		} else {
+			pageCells = append(pageCells, cell)
+		}
+		last = c
+	}
+	return append(level, sqliteChild{w.addPage(sqlitePage(sqliteInteriorTablePage, pageCells, 0, last.page)), last.maxRowID})
+}
+
+func interiorCell(child sqliteChild) []byte {
+	cell := make([]byte, 4, 13)
+	binary.BigEndian.PutUint32(cell, uint32(child.page))
+	return appendVarint(cell, uint64(child.maxRowID))
+}
+
+// leafCells returns the table leaf cells of the rows. The payload which doesn't fit the cell
+// is written to the overflow pages.
+func (w *sqliteWriter) leafCells(rows []*sqliteRow) ([][]byte, error) {
+	cells := make([][]byte, 0, len(rows))
+	for _, r := range rows {
+		payload, err := sqliteRecord(r.values)
+		if err != nil {
+			return nil, fmt.Errorf("encoding row %v: %s", r.rowID, err)
+		}
+		cell := appendVarint(nil, uint64(len(payload)))
+		cell = appendVarint(cell, uint64(r.rowID))
+		local := sqliteLocalPayloadSize(len(payload))
+		cell = append(cell, payload[:local]...)
+		if local < len(payload) {
+			cell = appendUint32(cell, uint32(w.writeOverflow(payload[local:])))
+		}
+		cells = append(cells, cell)
+	}
+	return cells, nil
+}
+
+// writeOverflow writes the chain of overflow pages and returns the number of the first one.
+func (w *sqliteWriter) writeOverflow(payload []byte) int {
+	const chunkSize = sqlitePageSize - 4
+	first := len(w.pages) + 1
+	for len(payload) > 0 {
+		page := make([]byte, sqlitePageSize)
+		n := copy(page[4:], payload)
+		payload = payload[n:]
+		if len(payload) > 0 {
+			binary.BigEndian.PutUint32(page, uint32(len(w.pages)+2))
+		}
+		w.addPage(page)
+	}
+	return first
+}
+
+// sqliteLocalPayloadSize returns the number of payload bytes stored in the table leaf cell itself.
+func sqliteLocalPayloadSize(size int) int {
+	const (
+		maxLocal = sqlitePageSize - 35
+		minLocal = (sqlitePageSize-12)*32/255 - 23
+	)
+	if size <= maxLocal {
+		return size
+	}
+	local := minLocal + (size-minLocal)%(sqlitePageSize-4)
+	if local <= maxLocal {
+		return local
+	}
+	return minLocal
+}
+
+func sqlitePageFits(cells [][]byte, headerSize, offset int) bool {
+	size := offset + headerSize
+	for _, c := range cells {
+		size += len(c) + 2
+	}
+	return size <= sqlitePageSize
+}
+
+// sqlitePage returns the b-tree page with the given cells. Offset is the size of the file header for the first page.
+// Right pointer is used by interior pages only.
+func sqlitePage(pageType byte, cells [][]byte, offset, rightPointer int) []byte {
+	page := make([]byte, sqlitePageSize)
+	page[offset] = pageType
+	headerSize := sqliteLeafHeaderSize
+	if pageType == sqliteInteriorTablePage {
+		headerSize = sqliteInteriorHeader
+		binary.BigEndian.PutUint32(page[offset+8:], uint32(rightPointer))
+	}
+	binary.BigEndian.PutUint16(page[offset+3:], uint16(len(cells)))
+	content := sqlitePageSize
+	for i, c := range cells {
+		content -= len(c)
+		copy(page[content:], c)
+		binary.BigEndian.PutUint16(page[offset+headerSize+2*i:], uint16(content))
+	}
+	binary.BigEndian.PutUint16(page[offset+5:], uint16(content))
+	return page
+}
+
+// sqliteRecord encodes the values in the SQLite record format.
+func sqliteRecord(values []interface{}) ([]byte, error) {
+	var header, body []byte
+	for _, v := range values {
+		switch v := v.(type) {
+		case nil:
+			header = appendVarint(header, 0)
+		case int:
+			header, body = appendInt(header, body, int64(v))
+		case int64:
+			header, body = appendInt(header, body, v)
+		case string:
+			header = appendVarint(header, uint64(13+2*len(v)))
+			body = append(body, v...)
+		default:
+			return nil, fmt.Errorf("unsupported value type %T", v)
+		}
+	}
+	headerSize := len(header) + 1
+	for varintLen(uint64(headerSize))+len(header) != headerSize {
+		headerSize = varintLen(uint64(headerSize)) + len(header)
+	}
+	record := appendVarint(make([]byte, 0, headerSize+len(body)), uint64(headerSize))
+	record = append(record, header...)
+	return append(record, body...), nil
+}
+
+// appendInt appends the serial type of the integer to the record header and its value to the record body.
+// Integers are stored big-endian in the smallest of the sizes SQLite supports.
+func appendInt(header, body []byte, v int64) ([]byte, []byte) {
+	var serialType uint64
+	var size int
+	switch {
+	case v == 0:
+		return appendVarint(header, 8), body
+	case v == 1:
+		return appendVarint(header, 9), body
+	case v >= -1<<7 && v < 1<<7:
+		serialType, size = 1, 1
+	case v >= -1<<15 && v < 1<<15:
+		serialType, size = 2, 2
+	case v >= -1<<23 && v < 1<<23:
+		serialType, size = 3, 3
+	case v >= -1<<31 && v < 1<<31:
+		serialType, size = 4, 4
+	case v >= -1<<47 && v < 1<<47:
+		serialType, size = 5, 6
+	default:
+		serialType, size = 6, 8
+	}
+	for i := size - 1; i >= 0; i-- {
+		body = append(body, byte(v>>(8*i)))
+	}
+	return appendVarint(header, serialType), body
+}
+
+// appendVarint appends the value in the SQLite variable-length integer format:
+// big-endian groups of 7 bits with the high bit set on all bytes but the last one,
+// the ninth byte if any holds all its 8 bits.
+func appendVarint(b []byte, v uint64) []byte {
+	if v > 1<<56-1 {
+		var buf [9]byte
+		buf[8] = byte(v)
+		v >>= 8
+		for i := 7; i >= 0; i-- {
+			buf[i] = byte(v&0x7f) | 0x80
+			v >>= 7
+		}
+		return append(b, buf[:]...)
+	}
+	var buf [8]byte
+	n := 0
+	for {
+		buf[n] = byte(v & 0x7f)
+		n++
+		v >>= 7
+		if v == 0 {
+			break
+		}
+	}
+	for i := n - 1; i >= 0; i-- {
+		c := buf[i]
+		if i != 0 {
+			c |= 0x80
+		}
+		b = append(b, c)
+	}
+	return b
+}
+
+func varintLen(v uint64) int {
+	return len(appendVarint(nil, v))
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+	binary.BigEndian.PutUint32(buf[:], v)
+	return append(b, buf[:]...)
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/dmalyar/pimpmyvocab/domain"
 	"github.com/dmalyar/pimpmyvocab/log"
 	"sync"
+	"time"
 )
 
 // VocabRepo is a mock struct implementing repo.Vocab interface.
@@ -11,35 +12,68 @@ type VocabRepo struct {
 	AddVocabFn      func(vocab *domain.Vocab) (*domain.Vocab, error)
 	AddVocabInvoked bool
 
-	GetVocabByUserIDFn      func(userID int) (*domain.Vocab, error)
-	GetVocabByUserIDInvoked bool
+	GetVocabsByUserIDFn      func(userID int) ([]*domain.Vocab, error)
+	GetVocabsByUserIDInvoked bool
 
-	ClearVocabByUserIDFn      func(userID int) error
-	ClearVocabByUserIDInvoked bool
+	GetActiveVocabByUserIDFn      func(userID int) (*domain.Vocab, error)
+	GetActiveVocabByUserIDInvoked bool
 
-	AddVocabEntryFn      func(vocab *domain.VocabEntry) (*domain.VocabEntry, error)
+	GetVocabByNameFn      func(userID int, name string) (*domain.Vocab, error)
+	GetVocabByNameInvoked bool
+
+	SetActiveVocabFn      func(vocab *domain.Vocab) error
+	SetActiveVocabInvoked bool
+
+	ClearVocabFn      func(vocabID int) error
+	ClearVocabInvoked bool
+
+	AddVocabEntryFn      func(entry *domain.VocabEntry) (*domain.VocabEntry, error)
 	AddVocabEntryInvoked bool
 
-	GetVocabEntryByTextFn      func(text string) (*domain.VocabEntry, error)
+	GetVocabEntryByTextFn      func(text string, langPair domain.LangPair) (*domain.VocabEntry, error)
 	GetVocabEntryByTextInvoked bool
 
+	GetCustomVocabEntryByTextFn      func(text string, langPair domain.LangPair, userID int) (*domain.VocabEntry, error)
+	GetCustomVocabEntryByTextInvoked bool
+
 	GetVocabEntryByIDFn      func(id int) (*domain.VocabEntry, error)
 	GetVocabEntryByIDInvoked bool
 
-	AddEntryToUserVocabFn      func(entryID, userID int) error
-	AddEntryToUserVocabInvoked bool
+	AddVocabEntryAliasFn      func(alias string, entryID int) error
+	AddVocabEntryAliasInvoked bool
 
-	CheckEntryInUserVocabFn      func(entryID, userID int) (bool, error)
-	CheckEntryInUserVocabInvoked bool
+	GetVocabEntryByAliasFn      func(alias string, langPair domain.LangPair) (*domain.VocabEntry, error)
+	GetVocabEntryByAliasInvoked bool
 
-	GetEntryIDsByUserIDFn      func(userID int) ([]int, error)
-	GetEntryIDsByUserIDInvoked bool
+	AddEntryToVocabFn      func(entryID, vocabID int) error
+	AddEntryToVocabInvoked bool
 
-	GetEntriesByUserIDFn      func(userID int) ([]*domain.VocabEntry, error)
-	GetEntriesByUserIDInvoked bool
+	CheckEntryInVocabFn      func(entryID, vocabID int) (bool, error)
+	CheckEntryInVocabInvoked bool
 
-	RemoveEntryFromUserVocabFn      func(entryID, userID int) error
-	RemoveEntryFromUserVocabInvoked bool
+	GetEntryIDsByVocabIDFn      func(vocabID int) ([]int, error)
+	GetEntryIDsByVocabIDInvoked bool
+
+	GetEntriesByVocabIDFn      func(vocabID int) ([]*domain.VocabEntry, error)
+	GetEntriesByVocabIDInvoked bool
+
+	GetExportEntriesByVocabIDFn      func(vocabID int) ([]*domain.ExportEntry, error)
+	GetExportEntriesByVocabIDInvoked bool
+
+	GetEntriesPageByVocabIDFn      func(vocabID, limit, offset int) ([]*domain.VocabEntry, error)
+	GetEntriesPageByVocabIDInvoked bool
+
+	CountEntriesByVocabIDFn      func(vocabID int) (int, error)
+	CountEntriesByVocabIDInvoked bool
+
+	FindEntriesByVocabIDFn      func(vocabID int, query string, limit, offset int) ([]*domain.VocabEntry, error)
+	FindEntriesByVocabIDInvoked bool
+
+	CountFoundEntriesByVocabIDFn      func(vocabID int, query string) (int, error)
+	CountFoundEntriesByVocabIDInvoked bool
+
+	RemoveEntryFromVocabFn      func(entryID, vocabID int) error
+	RemoveEntryFromVocabInvoked bool
 }
 
 // AddVocab registers invocation of AddVocab func and calls it.
@@ -48,16 +82,34 @@ func (r *VocabRepo) AddVocab(vocab *domain.Vocab) (*domain.Vocab, error) {
 	return r.AddVocabFn(vocab)
 }
 
-// GetVocabByUserID registers invocation of GetVocabByUserID func and calls it.
-func (r *VocabRepo) GetVocabByUserID(userID int) (*domain.Vocab, error) {
-	r.GetVocabByUserIDInvoked = true
-	return r.GetVocabByUserIDFn(userID)
+// GetVocabsByUserID registers invocation of GetVocabsByUserID func and calls it.
+func (r *VocabRepo) GetVocabsByUserID(userID int) ([]*domain.Vocab, error) {
+	r.GetVocabsByUserIDInvoked = true
+	return r.GetVocabsByUserIDFn(userID)
 }
 
-// ClearVocabByUserID registers invocation of ClearVocabByUserID func and calls it.
-func (r *VocabRepo) ClearVocabByUserID(userID int) error {
-	r.ClearVocabByUserIDInvoked = true
-	return r.ClearVocabByUserIDFn(userID)
+// GetActiveVocabByUserID registers invocation of GetActiveVocabByUserID func and calls it.
+func (r *VocabRepo) GetActiveVocabByUserID(userID int) (*domain.Vocab, error) {
+	r.GetActiveVocabByUserIDInvoked = true
+	return r.GetActiveVocabByUserIDFn(userID)
+}
+
+// GetVocabByName registers invocation of GetVocabByName func and calls it.
+func (r *VocabRepo) GetVocabByName(userID int, name string) (*domain.Vocab, error) {
+	r.GetVocabByNameInvoked = true
+	return r.GetVocabByNameFn(userID, name)
+}
+
+// SetActiveVocab registers invocation of SetActiveVocab func and calls it.
+func (r *VocabRepo) SetActiveVocab(vocab *domain.Vocab) error {
+	r.SetActiveVocabInvoked = true
+	return r.SetActiveVocabFn(vocab)
+}
+
+// ClearVocab registers invocation of ClearVocab func and calls it.
+func (r *VocabRepo) ClearVocab(vocabID int) error {
+	r.ClearVocabInvoked = true
+	return r.ClearVocabFn(vocabID)
 }
 
 // AddVocabEntry registers invocation of AddVocabEntry func and calls it.
@@ -67,9 +119,9 @@ func (r *VocabRepo) AddVocabEntry(entry *domain.VocabEntry) (*domain.VocabEntry,
 }
 
 // GetVocabEntryByText registers invocation of GetVocabEntryByText func and calls it.
-func (r *VocabRepo) GetVocabEntryByText(text string) (*domain.VocabEntry, error) {
+func (r *VocabRepo) GetVocabEntryByText(text string, langPair domain.LangPair) (*domain.VocabEntry, error) {
 	r.GetVocabEntryByTextInvoked = true
-	return r.GetVocabEntryByTextFn(text)
+	return r.GetVocabEntryByTextFn(text, langPair)
 }
 
 // GetVocabEntryByID registers invocation of GetVocabEntryByID func and calls it.
@@ -78,61 +130,378 @@ func (r *VocabRepo) GetVocabEntryByID(id int) (*domain.VocabEntry, error) {
 	return r.GetVocabEntryByIDFn(id)
 }
 
-// AddEntryToUserVocab registers invocation of AddEntryToUserVocab func and calls it.
-func (r *VocabRepo) AddEntryToUserVocab(entryID, userID int) error {
-	r.AddEntryToUserVocabInvoked = true
-	return r.AddEntryToUserVocabFn(entryID, userID)
+// GetCustomVocabEntryByText registers invocation of GetCustomVocabEntryByText func and calls it.
+func (r *VocabRepo) GetCustomVocabEntryByText(
+	text string,
+	langPair domain.LangPair,
+	userID int,
+) (*domain.VocabEntry, error) {
+	r.GetCustomVocabEntryByTextInvoked = true
+	return r.GetCustomVocabEntryByTextFn(text, langPair, userID)
 }
 
-// CheckEntryInUserVocab registers invocation of CheckEntryInUserVocab func and calls it.
-func (r *VocabRepo) CheckEntryInUserVocab(entryID, userID int) (bool, error) {
-	r.CheckEntryInUserVocabInvoked = true
-	return r.CheckEntryInUserVocabFn(entryID, userID)
+// AddVocabEntryAlias registers invocation of AddVocabEntryAlias func and calls it.
+func (r *VocabRepo) AddVocabEntryAlias(alias string, entryID int) error {
+	r.AddVocabEntryAliasInvoked = true
+	return r.AddVocabEntryAliasFn(alias, entryID)
 }
 
-// GetEntryIDsByUserID registers invocation of GetEntryIDsByUserID func and calls it.
-func (r *VocabRepo) GetEntryIDsByUserID(userID int) ([]int, error) {
-	r.GetEntryIDsByUserIDInvoked = true
-	return r.GetEntryIDsByUserIDFn(userID)
+// GetVocabEntryByAlias registers invocation of GetVocabEntryByAlias func and calls it.
+func (r *VocabRepo) GetVocabEntryByAlias(alias string, langPair domain.LangPair) (*domain.VocabEntry, error) {
+	r.GetVocabEntryByAliasInvoked = true
+	return r.GetVocabEntryByAliasFn(alias, langPair)
 }
 
-// GetEntriesByUserID registers invocation of GetEntriesByUserID func and calls it.
-func (r *VocabRepo) GetEntriesByUserID(userID int) ([]*domain.VocabEntry, error) {
-	r.GetEntriesByUserIDInvoked = true
-	return r.GetEntriesByUserIDFn(userID)
+// AddEntryToVocab registers invocation of AddEntryToVocab func and calls it.
+func (r *VocabRepo) AddEntryToVocab(entryID, vocabID int) error {
+	r.AddEntryToVocabInvoked = true
+	return r.AddEntryToVocabFn(entryID, vocabID)
 }
 
-// RemoveEntryFromUserVocab registers invocation of RemoveEntryFromUserVocab func and calls it.
-func (r *VocabRepo) RemoveEntryFromUserVocab(entryID, userID int) error {
-	r.RemoveEntryFromUserVocabInvoked = true
-	return r.RemoveEntryFromUserVocabFn(entryID, userID)
+// CheckEntryInVocab registers invocation of CheckEntryInVocab func and calls it.
+func (r *VocabRepo) CheckEntryInVocab(entryID, vocabID int) (bool, error) {
+	r.CheckEntryInVocabInvoked = true
+	return r.CheckEntryInVocabFn(entryID, vocabID)
+}
+
+// GetEntryIDsByVocabID registers invocation of GetEntryIDsByVocabID func and calls it.
+func (r *VocabRepo) GetEntryIDsByVocabID(vocabID int) ([]int, error) {
+	r.GetEntryIDsByVocabIDInvoked = true
+	return r.GetEntryIDsByVocabIDFn(vocabID)
+}
+
+// GetEntriesByVocabID registers invocation of GetEntriesByVocabID func and calls it.
+func (r *VocabRepo) GetEntriesByVocabID(vocabID int) ([]*domain.VocabEntry, error) {
+	r.GetEntriesByVocabIDInvoked = true
+	return r.GetEntriesByVocabIDFn(vocabID)
+}
+
+// GetExportEntriesByVocabID registers invocation of GetExportEntriesByVocabID func and calls it.
+func (r *VocabRepo) GetExportEntriesByVocabID(vocabID int) ([]*domain.ExportEntry, error) {
+	r.GetExportEntriesByVocabIDInvoked = true
+	return r.GetExportEntriesByVocabIDFn(vocabID)
+}
+
+// GetEntriesPageByVocabID registers invocation of GetEntriesPageByVocabID func and calls it.
+func (r *VocabRepo) GetEntriesPageByVocabID(vocabID, limit, offset int) ([]*domain.VocabEntry, error) {
+	r.GetEntriesPageByVocabIDInvoked = true
+	return r.GetEntriesPageByVocabIDFn(vocabID, limit, offset)
+}
+
+// CountEntriesByVocabID registers invocation of CountEntriesByVocabID func and calls it.
+func (r *VocabRepo) CountEntriesByVocabID(vocabID int) (int, error) {
+	r.CountEntriesByVocabIDInvoked = true
+	return r.CountEntriesByVocabIDFn(vocabID)
+}
+
+// FindEntriesByVocabID registers invocation of FindEntriesByVocabID func and calls it.
+func (r *VocabRepo) FindEntriesByVocabID(vocabID int, query string, limit, offset int) ([]*domain.VocabEntry, error) {
+	r.FindEntriesByVocabIDInvoked = true
+	return r.FindEntriesByVocabIDFn(vocabID, query, limit, offset)
+}
+
+// CountFoundEntriesByVocabID registers invocation of CountFoundEntriesByVocabID func and calls it.
+func (r *VocabRepo) CountFoundEntriesByVocabID(vocabID int, query string) (int, error) {
+	r.CountFoundEntriesByVocabIDInvoked = true
+	return r.CountFoundEntriesByVocabIDFn(vocabID, query)
+}
+
+// RemoveEntryFromVocab registers invocation of RemoveEntryFromVocab func and calls it.
+func (r *VocabRepo) RemoveEntryFromVocab(entryID, vocabID int) error {
+	r.RemoveEntryFromVocabInvoked = true
+	return r.RemoveEntryFromVocabFn(entryID, vocabID)
 }
 
 // Reset resets functions invocation.
 func (r *VocabRepo) Reset() {
 	r.AddVocabInvoked = false
-	r.GetVocabByUserIDInvoked = false
-	r.ClearVocabByUserIDInvoked = false
+	r.GetVocabsByUserIDInvoked = false
+	r.GetActiveVocabByUserIDInvoked = false
+	r.GetVocabByNameInvoked = false
+	r.SetActiveVocabInvoked = false
+	r.ClearVocabInvoked = false
 	r.AddVocabEntryInvoked = false
 	r.GetVocabEntryByTextInvoked = false
+	r.GetCustomVocabEntryByTextInvoked = false
 	r.GetVocabEntryByIDInvoked = false
-	r.AddEntryToUserVocabInvoked = false
-	r.CheckEntryInUserVocabInvoked = false
-	r.GetEntriesByUserIDInvoked = false
-	r.RemoveEntryFromUserVocabInvoked = false
+	r.AddVocabEntryAliasInvoked = false
+	r.GetVocabEntryByAliasInvoked = false
+	r.AddEntryToVocabInvoked = false
+	r.CheckEntryInVocabInvoked = false
+	r.GetEntryIDsByVocabIDInvoked = false
+	r.GetEntriesByVocabIDInvoked = false
+	r.GetExportEntriesByVocabIDInvoked = false
+	r.GetEntriesPageByVocabIDInvoked = false
+	r.CountEntriesByVocabIDInvoked = false
+	r.FindEntriesByVocabIDInvoked = false
+	r.CountFoundEntriesByVocabIDInvoked = false
+	r.RemoveEntryFromVocabInvoked = false
+}
+
+// DictionaryRepo is a mock struct implementing repo.Dictionary interface.
+type DictionaryRepo struct {
+	AddVocabEntriesFn      func(entries []*domain.VocabEntry) (int, error)
+	AddVocabEntriesInvoked bool
+}
+
+// AddVocabEntries registers invocation of AddVocabEntries func and calls it.
+func (r *DictionaryRepo) AddVocabEntries(entries []*domain.VocabEntry) (int, error) {
+	r.AddVocabEntriesInvoked = true
+	return r.AddVocabEntriesFn(entries)
+}
+
+// Reset resets functions invocation.
+func (r *DictionaryRepo) Reset() {
+	r.AddVocabEntriesInvoked = false
+}
+
+// ScheduleRepo is a mock struct implementing repo.Schedule interface.
+type ScheduleRepo struct {
+	GetEntryIDsToReviewFn      func(userID int, direction domain.Direction) ([]int, error)
+	GetEntryIDsToReviewInvoked bool
+
+	CountEntriesToReviewFn      func(userID int, direction domain.Direction, at time.Time) (int, error)
+	CountEntriesToReviewInvoked bool
+
+	GetScheduleFn      func(entryID, userID int, direction domain.Direction) (*domain.Schedule, error)
+	GetScheduleInvoked bool
+
+	SaveReviewFn      func(review *domain.Review, schedule *domain.Schedule, userID int) (*domain.EntryProgress, error)
+	SaveReviewInvoked bool
+}
+
+// GetEntryIDsToReview registers invocation of GetEntryIDsToReview func and calls it.
+func (r *ScheduleRepo) GetEntryIDsToReview(userID int, direction domain.Direction) ([]int, error) {
+	r.GetEntryIDsToReviewInvoked = true
+	return r.GetEntryIDsToReviewFn(userID, direction)
+}
+
+// CountEntriesToReview registers invocation of CountEntriesToReview func and calls it.
+func (r *ScheduleRepo) CountEntriesToReview(userID int, direction domain.Direction, at time.Time) (int, error) {
+	r.CountEntriesToReviewInvoked = true
+	return r.CountEntriesToReviewFn(userID, direction, at)
+}
+
+// GetSchedule registers invocation of GetSchedule func and calls it.
+func (r *ScheduleRepo) GetSchedule(entryID, userID int, direction domain.Direction) (*domain.Schedule, error) {
+	r.GetScheduleInvoked = true
+	return r.GetScheduleFn(entryID, userID, direction)
+}
+
+// SaveReview registers invocation of SaveReview func and calls it.
+func (r *ScheduleRepo) SaveReview(
+	review *domain.Review,
+	schedule *domain.Schedule,
+	userID int,
+) (*domain.EntryProgress, error) {
+	r.SaveReviewInvoked = true
+	return r.SaveReviewFn(review, schedule, userID)
+}
+
+// Reset resets functions invocation.
+func (r *ScheduleRepo) Reset() {
+	r.GetEntryIDsToReviewInvoked = false
+	r.CountEntriesToReviewInvoked = false
+	r.GetScheduleInvoked = false
+	r.SaveReviewInvoked = false
+}
+
+// QuizSessionRepo is a mock struct implementing repo.QuizSession interface.
+type QuizSessionRepo struct {
+	AddQuizSessionFn      func(session *domain.QuizSession, userID int) (*domain.QuizSession, error)
+	AddQuizSessionInvoked bool
+
+	GetActiveQuizSessionFn      func(userID int) (*domain.QuizSession, error)
+	GetActiveQuizSessionInvoked bool
+
+	GradeQuizSessionEntryFn      func(sessionID, entryID int, grade domain.Grade) (bool, error)
+	GradeQuizSessionEntryInvoked bool
+
+	FinishQuizSessionFn      func(sessionID int, finishedAt time.Time) error
+	FinishQuizSessionInvoked bool
+}
+
+// AddQuizSession registers invocation of AddQuizSession func and calls it.
+func (r *QuizSessionRepo) AddQuizSession(session *domain.QuizSession, userID int) (*domain.QuizSession, error) {
+	r.AddQuizSessionInvoked = true
+	return r.AddQuizSessionFn(session, userID)
+}
+
+// GetActiveQuizSession registers invocation of GetActiveQuizSession func and calls it.
+func (r *QuizSessionRepo) GetActiveQuizSession(userID int) (*domain.QuizSession, error) {
+	r.GetActiveQuizSessionInvoked = true
+	return r.GetActiveQuizSessionFn(userID)
+}
+
+// GradeQuizSessionEntry registers invocation of GradeQuizSessionEntry func and calls it.
+func (r *QuizSessionRepo) GradeQuizSessionEntry(sessionID, entryID int, grade domain.Grade) (bool, error) {
+	r.GradeQuizSessionEntryInvoked = true
+	return r.GradeQuizSessionEntryFn(sessionID, entryID, grade)
+}
+
+// FinishQuizSession registers invocation of FinishQuizSession func and calls it.
+func (r *QuizSessionRepo) FinishQuizSession(sessionID int, finishedAt time.Time) error {
+	r.FinishQuizSessionInvoked = true
+	return r.FinishQuizSessionFn(sessionID, finishedAt)
+}
+
+// Reset resets functions invocation.
+func (r *QuizSessionRepo) Reset() {
+	r.AddQuizSessionInvoked = false
+	r.GetActiveQuizSessionInvoked = false
+	r.GradeQuizSessionEntryInvoked = false
+	r.FinishQuizSessionInvoked = false
+}
+
+// StatsRepo is a mock struct implementing repo.Stats interface.
+type StatsRepo struct {
+	CountEntriesFn      func(userID int) (int, error)
+	CountEntriesInvoked bool
+
+	CountEntriesAddedSinceFn      func(userID int, since time.Time) (int, error)
+	CountEntriesAddedSinceInvoked bool
+
+	CountReviewsSinceFn      func(userID int, since time.Time) (int, int, error)
+	CountReviewsSinceInvoked bool
+
+	GetDailyReviewCountsFn      func(userID int, timezone string, since time.Time) ([]*domain.DailyReviews, error)
+	GetDailyReviewCountsInvoked bool
+}
+
+// CountEntries registers invocation of CountEntries func and calls it.
+func (r *StatsRepo) CountEntries(userID int) (int, error) {
+	r.CountEntriesInvoked = true
+	return r.CountEntriesFn(userID)
+}
+
+// CountEntriesAddedSince registers invocation of CountEntriesAddedSince func and calls it.
+func (r *StatsRepo) CountEntriesAddedSince(userID int, since time.Time) (int, error) {
+	r.CountEntriesAddedSinceInvoked = true
+	return r.CountEntriesAddedSinceFn(userID, since)
+}
+
+// CountReviewsSince registers invocation of CountReviewsSince func and calls it.
+func (r *StatsRepo) CountReviewsSince(userID int, since time.Time) (int, int, error) {
+	r.CountReviewsSinceInvoked = true
+	return r.CountReviewsSinceFn(userID, since)
+}
+
+// GetDailyReviewCounts registers invocation of GetDailyReviewCounts func and calls it.
+func (r *StatsRepo) GetDailyReviewCounts(
+	userID int,
+	timezone string,
+	since time.Time,
+) ([]*domain.DailyReviews, error) {
+	r.GetDailyReviewCountsInvoked = true
+	return r.GetDailyReviewCountsFn(userID, timezone, since)
+}
+
+// Reset resets functions invocation.
+func (r *StatsRepo) Reset() {
+	r.CountEntriesInvoked = false
+	r.CountEntriesAddedSinceInvoked = false
+	r.CountReviewsSinceInvoked = false
+	r.GetDailyReviewCountsInvoked = false
+}
+
+// UserSettingsRepo is a mock struct implementing repo.UserSettings interface.
+type UserSettingsRepo struct {
+	GetUserSettingsFn      func(userID int) (*domain.UserSettings, error)
+	GetUserSettingsInvoked bool
+
+	SaveUserSettingsFn      func(settings *domain.UserSettings) error
+	SaveUserSettingsInvoked bool
+
+	GetUserSettingsWithRemindersEnabledFn      func() ([]*domain.UserSettings, error)
+	GetUserSettingsWithRemindersEnabledInvoked bool
+
+	MarkRemindedFn      func(userID int, date time.Time) (bool, error)
+	MarkRemindedInvoked bool
+
+	MarkGoalReachedFn      func(userID int, date time.Time) (bool, error)
+	MarkGoalReachedInvoked bool
+}
+
+// GetUserSettings registers invocation of GetUserSettings func and calls it.
+func (r *UserSettingsRepo) GetUserSettings(userID int) (*domain.UserSettings, error) {
+	r.GetUserSettingsInvoked = true
+	return r.GetUserSettingsFn(userID)
+}
+
+// SaveUserSettings registers invocation of SaveUserSettings func and calls it.
+func (r *UserSettingsRepo) SaveUserSettings(settings *domain.UserSettings) error {
+	r.SaveUserSettingsInvoked = true
+	return r.SaveUserSettingsFn(settings)
+}
+
+// GetUserSettingsWithRemindersEnabled registers invocation of GetUserSettingsWithRemindersEnabled func and calls it.
+func (r *UserSettingsRepo) GetUserSettingsWithRemindersEnabled() ([]*domain.UserSettings, error) {
+	r.GetUserSettingsWithRemindersEnabledInvoked = true
+	return r.GetUserSettingsWithRemindersEnabledFn()
+}
+
+// MarkReminded registers invocation of MarkReminded func and calls it.
+func (r *UserSettingsRepo) MarkReminded(userID int, date time.Time) (bool, error) {
+	r.MarkRemindedInvoked = true
+	return r.MarkRemindedFn(userID, date)
+}
+
+// MarkGoalReached registers invocation of MarkGoalReached func and calls it.
+func (r *UserSettingsRepo) MarkGoalReached(userID int, date time.Time) (bool, error) {
+	r.MarkGoalReachedInvoked = true
+	return r.MarkGoalReachedFn(userID, date)
+}
+
+// Reset resets functions invocation.
+func (r *UserSettingsRepo) Reset() {
+	r.GetUserSettingsInvoked = false
+	r.SaveUserSettingsInvoked = false
+	r.GetUserSettingsWithRemindersEnabledInvoked = false
+	r.MarkRemindedInvoked = false
+	r.MarkGoalReachedInvoked = false
+}
+
+// SchedulerService is a mock struct implementing service.Scheduler interface.
+type SchedulerService struct {
+	GetNextEntryToReviewFn      func(userID, previousEntryID int, direction domain.Direction) (*domain.VocabEntry, error)
+	GetNextEntryToReviewInvoked bool
+
+	ReviewEntryFn      func(entryID, userID int, direction domain.Direction, grade domain.Grade) error
+	ReviewEntryInvoked bool
+}
+
+// GetNextEntryToReview registers invocation of GetNextEntryToReview func and calls it.
+func (s *SchedulerService) GetNextEntryToReview(
+	userID, previousEntryID int,
+	direction domain.Direction,
+) (*domain.VocabEntry, error) {
+	s.GetNextEntryToReviewInvoked = true
+	return s.GetNextEntryToReviewFn(userID, previousEntryID, direction)
+}
+
+// ReviewEntry registers invocation of ReviewEntry func and calls it.
+func (s *SchedulerService) ReviewEntry(entryID, userID int, direction domain.Direction, grade domain.Grade) error {
+	s.ReviewEntryInvoked = true
+	return s.ReviewEntryFn(entryID, userID, direction, grade)
+}
+
+// Reset resets functions invocation.
+func (s *SchedulerService) Reset() {
+	s.GetNextEntryToReviewInvoked = false
+	s.ReviewEntryInvoked = false
 }
 
 type VocabEntryService struct {
-	GetVocabEntryByTextFn      func(text string) (*domain.VocabEntry, error)
+	GetVocabEntryByTextFn      func(text string, langPair domain.LangPair) (*domain.VocabEntry, error)
 	GetVocabEntryByTextInvoked bool
 
 	GetVocabEntryByIDFn      func(id int) (*domain.VocabEntry, error)
 	GetVocabEntryByIDInvoked bool
 }
 
-func (v *VocabEntryService) GetVocabEntryByText(text string) (*domain.VocabEntry, error) {
+func (v *VocabEntryService) GetVocabEntryByText(text string, langPair domain.LangPair) (*domain.VocabEntry, error) {
 	v.GetVocabEntryByTextInvoked = true
-	return v.GetVocabEntryByTextFn(text)
+	return v.GetVocabEntryByTextFn(text, langPair)
 }
 
 func (v *VocabEntryService) GetVocabEntryByID(id int) (*domain.VocabEntry, error) {
@@ -151,6 +520,15 @@ type VocabServiceConcurrencyCheck struct {
 	CreateVocabFn      func(userID int) (*domain.Vocab, error)
 	CreateVocabInvoked bool
 
+	AddUserVocabFn      func(userID int, name string) (*domain.Vocab, error)
+	AddUserVocabInvoked bool
+
+	GetUserVocabsFn      func(userID int) ([]*domain.Vocab, error)
+	GetUserVocabsInvoked bool
+
+	UseUserVocabFn      func(userID int, name string) (*domain.Vocab, error)
+	UseUserVocabInvoked bool
+
 	ClearUserVocabFn      func(userID int) error
 	ClearUserVocabInvoked bool
 
@@ -160,21 +538,30 @@ type VocabServiceConcurrencyCheck struct {
 	CheckEntryInUserVocabFn      func(entryID, userID int) (bool, error)
 	CheckEntryInUserVocabInvoked bool
 
-	GetRandomEntryFromUserVocabFn      func(userId, previousEntryID int) (*domain.VocabEntry, error)
-	GetRandomEntryFromUserVocabInvoked bool
-
 	GetEntriesFromUserVocabFn      func(userID int) ([]*domain.VocabEntry, error)
 	GetEntriesFromUserVocabInvoked bool
 
+	GetExportEntriesFromUserVocabFn      func(userID int) ([]*domain.ExportEntry, error)
+	GetExportEntriesFromUserVocabInvoked bool
+
+	GetEntriesPageFromUserVocabFn      func(userID, number, size int) (*domain.EntriesPage, error)
+	GetEntriesPageFromUserVocabInvoked bool
+
+	FindEntriesInUserVocabFn      func(userID int, query string, number, size int) (*domain.EntriesPage, error)
+	FindEntriesInUserVocabInvoked bool
+
 	RemoveEntryFromUserVocabFn      func(entryID, userID int) error
 	RemoveEntryFromUserVocabInvoked bool
 
-	GetVocabEntryByTextFn      func(text string) (*domain.VocabEntry, error)
+	GetVocabEntryByTextFn      func(text string, langPair domain.LangPair) (*domain.VocabEntry, error)
 	GetVocabEntryByTextInvoked bool
 
 	GetVocabEntryByIDFn      func(ID int) (*domain.VocabEntry, error)
 	GetVocabEntryByIDInvoked bool
 
+	AddCustomVocabEntryFn      func(text, translation string, langPair domain.LangPair, userID int) (*domain.VocabEntry, error)
+	AddCustomVocabEntryInvoked bool
+
 	textConcurrencyCheckMu  sync.Mutex
 	textConcurrencyCheck    map[string]struct{}
 	TextConcurrentlyInvoked bool
@@ -200,6 +587,30 @@ func (s *VocabServiceConcurrencyCheck) CreateVocab(userID int) (*domain.Vocab, e
 	return s.CreateVocabFn(userID)
 }
 
+// AddUserVocab registers invocation of AddUserVocab func and calls it.
+// Also registers if it was called concurrently by the same user.
+func (s *VocabServiceConcurrencyCheck) AddUserVocab(userID int, name string) (*domain.Vocab, error) {
+	s.startWorkSyncedByUserID(userID, &s.AddUserVocabInvoked)
+	defer s.endWorkSyncedByUserID(userID)
+	return s.AddUserVocabFn(userID, name)
+}
+
+// GetUserVocabs registers invocation of GetUserVocabs func and calls it.
+// Also registers if it was called concurrently by the same user.
+func (s *VocabServiceConcurrencyCheck) GetUserVocabs(userID int) ([]*domain.Vocab, error) {
+	s.startWorkSyncedByUserID(userID, &s.GetUserVocabsInvoked)
+	defer s.endWorkSyncedByUserID(userID)
+	return s.GetUserVocabsFn(userID)
+}
+
+// UseUserVocab registers invocation of UseUserVocab func and calls it.
+// Also registers if it was called concurrently by the same user.
+func (s *VocabServiceConcurrencyCheck) UseUserVocab(userID int, name string) (*domain.Vocab, error) {
+	s.startWorkSyncedByUserID(userID, &s.UseUserVocabInvoked)
+	defer s.endWorkSyncedByUserID(userID)
+	return s.UseUserVocabFn(userID, name)
+}
+
 // ClearUserVocab registers invocation of ClearUserVocab func and calls it.
 // Also registers if it was called concurrently by the same user.
 func (s *VocabServiceConcurrencyCheck) ClearUserVocab(userID int) error {
@@ -224,14 +635,6 @@ func (s *VocabServiceConcurrencyCheck) CheckEntryInUserVocab(entryID, userID int
 	return s.CheckEntryInUserVocabFn(entryID, userID)
 }
 
-// GetRandomEntryFromUserVocab registers invocation of GetRandomEntryFromUserVocab func and calls it.
-// Also registers if it was called concurrently by the same user.
-func (s *VocabServiceConcurrencyCheck) GetRandomEntryFromUserVocab(userID, previousEntryID int) (*domain.VocabEntry, error) {
-	s.startWorkSyncedByUserID(userID, &s.GetRandomEntryFromUserVocabInvoked)
-	defer s.endWorkSyncedByUserID(userID)
-	return s.GetRandomEntryFromUserVocabFn(userID, previousEntryID)
-}
-
 // GetEntriesByUserID registers invocation of GetEntriesByUserID func and calls it.
 // Also registers if it was called concurrently by the same user.
 func (s *VocabServiceConcurrencyCheck) GetEntriesFromUserVocab(userID int) ([]*domain.VocabEntry, error) {
@@ -240,6 +643,30 @@ func (s *VocabServiceConcurrencyCheck) GetEntriesFromUserVocab(userID int) ([]*d
 	return s.GetEntriesFromUserVocabFn(userID)
 }
 
+// GetExportEntriesFromUserVocab registers invocation of GetExportEntriesFromUserVocab func and calls it.
+// Also registers if it was called concurrently by the same user.
+func (s *VocabServiceConcurrencyCheck) GetExportEntriesFromUserVocab(userID int) ([]*domain.ExportEntry, error) {
+	s.startWorkSyncedByUserID(userID, &s.GetExportEntriesFromUserVocabInvoked)
+	defer s.endWorkSyncedByUserID(userID)
+	return s.GetExportEntriesFromUserVocabFn(userID)
+}
+
+// GetEntriesPageFromUserVocab registers invocation of GetEntriesPageFromUserVocab func and calls it.
+// Also registers if it was called concurrently by the same user.
+func (s *VocabServiceConcurrencyCheck) GetEntriesPageFromUserVocab(userID, number, size int) (*domain.EntriesPage, error) {
+	s.startWorkSyncedByUserID(userID, &s.GetEntriesPageFromUserVocabInvoked)
+	defer s.endWorkSyncedByUserID(userID)
+	return s.GetEntriesPageFromUserVocabFn(userID, number, size)
+}
+
+// FindEntriesInUserVocab registers invocation of FindEntriesInUserVocab func and calls it.
+// Also registers if it was called concurrently by the same user.
+func (s *VocabServiceConcurrencyCheck) FindEntriesInUserVocab(userID int, query string, number, size int) (*domain.EntriesPage, error) {
+	s.startWorkSyncedByUserID(userID, &s.FindEntriesInUserVocabInvoked)
+	defer s.endWorkSyncedByUserID(userID)
+	return s.FindEntriesInUserVocabFn(userID, query, number, size)
+}
+
 // RemoveEntryFromUserVocab registers invocation of RemoveEntryFromUserVocab func and calls it.
 // Also registers if it was called concurrently by the same user.
 func (s *VocabServiceConcurrencyCheck) RemoveEntryFromUserVocab(entryID, userID int) error {
@@ -249,11 +676,23 @@ func (s *VocabServiceConcurrencyCheck) RemoveEntryFromUserVocab(entryID, userID
 }
 
 // GetVocabEntryByText registers invocation of GetVocabEntryByText func and calls it.
-// Also registers if it was called concurrently for the same text.
-func (s *VocabServiceConcurrencyCheck) GetVocabEntryByText(text string) (*domain.VocabEntry, error) {
-	s.startWorkSyncedByText(text, &s.GetVocabEntryByTextInvoked)
-	defer s.endWorkSyncedByText(text)
-	return s.GetVocabEntryByTextFn(text)
+// Also registers if it was called concurrently for the same text and language pair.
+func (s *VocabServiceConcurrencyCheck) GetVocabEntryByText(text string, langPair domain.LangPair) (*domain.VocabEntry, error) {
+	key := langPair.String() + ":" + text
+	s.startWorkSyncedByText(key, &s.GetVocabEntryByTextInvoked)
+	defer s.endWorkSyncedByText(key)
+	return s.GetVocabEntryByTextFn(text, langPair)
+}
+
+// AddCustomVocabEntry registers invocation of AddCustomVocabEntry func and calls it.
+// Also registers if it was called concurrently for the same text and language pair.
+func (s *VocabServiceConcurrencyCheck) AddCustomVocabEntry(
+	text, translation string, langPair domain.LangPair, userID int,
+) (*domain.VocabEntry, error) {
+	key := langPair.String() + ":" + text
+	s.startWorkSyncedByText(key, &s.AddCustomVocabEntryInvoked)
+	defer s.endWorkSyncedByText(key)
+	return s.AddCustomVocabEntryFn(text, translation, langPair, userID)
 }
 
 // GetVocabEntryByID registers invocation of GetVocabEntryByID func and calls it.
@@ -298,6 +737,68 @@ func (s *VocabServiceConcurrencyCheck) endWorkSyncedByText(text string) {
 	s.textConcurrencyCheckMu.Unlock()
 }
 
+// SchedulerServiceConcurrencyCheck is a mock struct implementing service.Scheduler interface.
+// You can test concurrent execution of methods with this struct.
+type SchedulerServiceConcurrencyCheck struct {
+	GetNextEntryToReviewFn      func(userID, previousEntryID int, direction domain.Direction) (*domain.VocabEntry, error)
+	GetNextEntryToReviewInvoked bool
+
+	ReviewEntryFn      func(entryID, userID int, direction domain.Direction, grade domain.Grade) error
+	ReviewEntryInvoked bool
+
+	userIDConcurrencyCheckMu  sync.Mutex
+	userIDConcurrencyCheck    map[int]struct{}
+	UserIDConcurrentlyInvoked bool
+}
+
+// NewSchedulerServiceConcurrencyCheck returns ready to use SchedulerServiceConcurrencyCheck.
+func NewSchedulerServiceConcurrencyCheck() *SchedulerServiceConcurrencyCheck {
+	return &SchedulerServiceConcurrencyCheck{
+		userIDConcurrencyCheck: make(map[int]struct{}),
+	}
+}
+
+// GetNextEntryToReview registers invocation of GetNextEntryToReview func and calls it.
+// Also registers if it was called concurrently by the same user.
+func (s *SchedulerServiceConcurrencyCheck) GetNextEntryToReview(
+	userID, previousEntryID int,
+	direction domain.Direction,
+) (*domain.VocabEntry, error) {
+	s.startWorkSyncedByUserID(userID, &s.GetNextEntryToReviewInvoked)
+	defer s.endWorkSyncedByUserID(userID)
+	return s.GetNextEntryToReviewFn(userID, previousEntryID, direction)
+}
+
+// ReviewEntry registers invocation of ReviewEntry func and calls it.
+// Also registers if it was called concurrently by the same user.
+func (s *SchedulerServiceConcurrencyCheck) ReviewEntry(
+	entryID, userID int,
+	direction domain.Direction,
+	grade domain.Grade,
+) error {
+	s.startWorkSyncedByUserID(userID, &s.ReviewEntryInvoked)
+	defer s.endWorkSyncedByUserID(userID)
+	return s.ReviewEntryFn(entryID, userID, direction, grade)
+}
+
+func (s *SchedulerServiceConcurrencyCheck) startWorkSyncedByUserID(userID int, invocation *bool) {
+	s.userIDConcurrencyCheckMu.Lock()
+	*invocation = true
+	_, ok := s.userIDConcurrencyCheck[userID]
+	if ok {
+		s.UserIDConcurrentlyInvoked = true
+	} else {
+		s.userIDConcurrencyCheck[userID] = struct{}{}
+	}
+	s.userIDConcurrencyCheckMu.Unlock()
+}
+
+func (s *SchedulerServiceConcurrencyCheck) endWorkSyncedByUserID(userID int) {
+	s.userIDConcurrencyCheckMu.Lock()
+	delete(s.userIDConcurrencyCheck, userID)
+	s.userIDConcurrencyCheckMu.Unlock()
+}
+
 type Logger struct {
 }
 
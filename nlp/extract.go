@@ -0,0 +1,107 @@
+// Package nlp provides simple natural language processing used to pick vocabulary from texts.
+package nlp
+
+import (
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const minCandidateLen = 2
+
+type token struct {
+	text          string
+	sentenceStart bool
+}
+
+// tokenize splits the text into words made of letters.
+// Words with apostrophes are contractions or possessives, possessive "'s" is removed and contractions are skipped.
+func tokenize(text string) []token {
+	var tokens []token
+	var word []rune
+	sentenceStart := true
+	wordSentenceStart := true
+	flush := func() {
+		if len(word) == 0 {
+			return
+		}
+		w := string(word)
+		word = word[:0]
+		if i := strings.IndexAny(w, "'’"); i != -1 {
+			if w[i:] != "'s" && w[i:] != "’s" {
+				return
+			}
+			w = w[:i]
+		}
+		tokens = append(tokens, token{text: w, sentenceStart: wordSentenceStart})
+	}
+	for _, r := range text {
+		switch {
+		case unicode.IsLetter(r):
+			if len(word) == 0 {
+				wordSentenceStart = sentenceStart
+				sentenceStart = false
+			}
+			word = append(word, r)
+		case (r == '\'' || r == '’') && len(word) != 0:
+			word = append(word, r)
+		default:
+			flush()
+			if r == '.' || r == '!' || r == '?' || r == '\n' {
+				sentenceStart = true
+			}
+		}
+	}
+	flush()
+	return tokens
+}
+
+// ExtractCandidates returns the distinct words of the text which may be worth learning ordered from the rarest one.
+// Words of the same rarity keep the order of their first occurrence.
+// English words are normalized to lemmas, stop-words are dropped and rarity is taken from the frequency list.
+// Words of other languages are only lowercased and keep the order of their first occurrence.
+// Capitalized English words never used in lowercase or at the start of a sentence are considered names and skipped.
+func ExtractCandidates(text, lang string) []*domain.WordCandidate {
+	var candidates []*domain.WordCandidate
+	byText := make(map[string]*domain.WordCandidate)
+	names := make(map[string]bool)
+	for _, t := range tokenize(text) {
+		lower := strings.ToLower(t.text)
+		if len([]rune(lower)) < minCandidateLen {
+			continue
+		}
+		capitalized := lower != t.text
+		word, rank := lower, 0
+		if lang == "en" {
+			if IsStopWord(lower) {
+				continue
+			}
+			word = Lemma(lower)
+			if IsStopWord(word) {
+				continue
+			}
+			rank = FrequencyRank(word)
+		}
+		isName := lang == "en" && capitalized && !t.sentenceStart
+		if c, ok := byText[word]; ok {
+			c.Count++
+			names[word] = names[word] && isName
+			continue
+		}
+		c := &domain.WordCandidate{Text: word, Rank: rank, Count: 1}
+		byText[word] = c
+		names[word] = isName
+		candidates = append(candidates, c)
+	}
+	res := make([]*domain.WordCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		if !names[c.Text] {
+			res = append(res, c)
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Rank > res[j].Rank
+	})
+	return res
+}
@@ -0,0 +1,72 @@
+package nlp
+
+import (
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"reflect"
+	"testing"
+)
+
+func TestExtractCandidates(t *testing.T) {
+	testCases := []struct {
+		name     string
+		text     string
+		lang     string
+		expected []*domain.WordCandidate
+	}{
+		{
+			name: "English text",
+			text: "The hedgehogs were running through the garden. A hedgehog doesn't run fast.",
+			lang: "en",
+			expected: []*domain.WordCandidate{
+				{Text: "hedgehog", Rank: FrequencyRank("hedgehog"), Count: 2},
+				{Text: "fast", Rank: FrequencyRank("fast"), Count: 1},
+				{Text: "garden", Rank: FrequencyRank("garden"), Count: 1},
+				{Text: "run", Rank: FrequencyRank("run"), Count: 2},
+			},
+		},
+		{
+			name: "Names are skipped",
+			text: "Yesterday Sonic met Tails. Meet again, sonic!",
+			lang: "en",
+			expected: []*domain.WordCandidate{
+				{Text: "sonic", Rank: FrequencyRank("sonic"), Count: 2},
+				{Text: "yesterday", Rank: FrequencyRank("yesterday"), Count: 1},
+				{Text: "meet", Rank: FrequencyRank("meet"), Count: 2},
+			},
+		},
+		{
+			name: "Possessives",
+			text: "the hedgehog's nose",
+			lang: "en",
+			expected: []*domain.WordCandidate{
+				{Text: "hedgehog", Rank: FrequencyRank("hedgehog"), Count: 1},
+				{Text: "nose", Rank: FrequencyRank("nose"), Count: 1},
+			},
+		},
+		{
+			name: "Other language",
+			text: "Der Igel läuft. Der Igel schläft.",
+			lang: "de",
+			expected: []*domain.WordCandidate{
+				{Text: "der", Count: 2},
+				{Text: "igel", Count: 2},
+				{Text: "läuft", Count: 1},
+				{Text: "schläft", Count: 1},
+			},
+		},
+		{
+			name:     "No words",
+			text:     "42, 1 + 1 = 2",
+			lang:     "en",
+			expected: []*domain.WordCandidate{},
+		},
+	}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			res := ExtractCandidates(c.text, c.lang)
+			if !reflect.DeepEqual(c.expected, res) {
+				t.Errorf("Expected candidates:%v;Actual:%v", c.expected, res)
+			}
+		})
+	}
+}
@@ -0,0 +1,184 @@
+package nlp
+
+import "strings"
+
+// irregularForms maps irregular English word forms to their lemmas.
+var irregularForms = map[string]string{
+	"am": "be", "is": "be", "are": "be", "was": "be", "were": "be", "been": "be", "being": "be",
+	"has": "have", "had": "have", "having": "have",
+	"does": "do", "did": "do", "done": "do", "doing": "do",
+	"went": "go", "gone": "go", "going": "go", "used": "use", "using": "use",
+	"said": "say", "made": "make", "got": "get", "gotten": "get", "knew": "know", "known": "know",
+	"thought": "think", "took": "take", "taken": "take", "saw": "see", "seen": "see",
+	"came": "come", "gave": "give", "given": "give", "told": "tell", "found": "find",
+	"felt": "feel", "became": "become", "put": "put", "meant": "mean",
+	"kept": "keep", "let": "let", "began": "begin", "begun": "begin", "held": "hold",
+	"brought": "bring", "wrote": "write", "written": "write", "sat": "sit", "stood": "stand",
+	"lost": "lose", "paid": "pay", "met": "meet", "ran": "run", "led": "lead", "understood": "understand",
+	"spoke": "speak", "spoken": "speak", "read": "read", "spent": "spend", "grew": "grow", "grown": "grow",
+	"won": "win", "taught": "teach", "bought": "buy", "sent": "send", "built": "build", "fell": "fall",
+	"fallen": "fall", "cut": "cut", "sold": "sell", "risen": "rise", "drove": "drive",
+	"driven": "drive", "broke": "break", "broken": "break", "wore": "wear", "worn": "wear", "ate": "eat",
+	"eaten": "eat", "drew": "draw", "drawn": "draw", "chose": "choose", "chosen": "choose", "caught": "catch",
+	"fought": "fight", "threw": "throw", "thrown": "throw", "shot": "shoot", "lain": "lie",
+	"laid": "lay", "hung": "hang", "forgot": "forget", "forgotten": "forget", "shook": "shake", "shaken": "shake",
+	"flew": "fly", "flown": "fly", "sang": "sing", "sung": "sing", "hid": "hide", "hidden": "hide",
+	"rode": "ride", "ridden": "ride", "fed": "feed", "swam": "swim", "swum": "swim", "struck": "strike",
+	"stuck": "stick", "slept": "sleep", "dealt": "deal", "drank": "drink", "drunk": "drink", "blew": "blow",
+	"blown": "blow", "froze": "freeze", "frozen": "freeze", "woke": "wake", "woken": "wake",
+	"bitten": "bite", "shut": "shut", "hit": "hit", "set": "set", "cost": "cost", "hurt": "hurt",
+	"born": "bear", "borne": "bear", "beat": "beat", "beaten": "beat", "sought": "seek",
+	"swore": "swear", "sworn": "swear", "tore": "tear", "torn": "tear", "wept": "weep",
+	"dug": "dig", "fled": "flee", "forgave": "forgive", "forgiven": "forgive", "shone": "shine",
+	"slid": "slide", "spun": "spin", "stole": "steal", "stolen": "steal", "stung": "sting", "strove": "strive",
+	"swung": "swing", "thrust": "thrust", "bent": "bend", "bled": "bleed", "bred": "breed", "crept": "creep",
+	"dying": "die", "lying": "lie", "tying": "tie",
+	"men": "man", "women": "woman", "children": "child", "feet": "foot", "teeth": "tooth", "mice": "mouse",
+	"geese": "goose", "people": "people", "lives": "life", "wives": "wife", "knives": "knife", "leaves": "leaf",
+	"halves": "half", "shelves": "shelf", "wolves": "wolf", "thieves": "thief",
+	"better": "good", "best": "good", "worse": "bad", "worst": "bad",
+}
+
+// invariantForms are the English words which look like inflected forms but are lemmas themselves.
+var invariantForms = toSet([]string{
+	"always", "perhaps", "news", "series", "species", "yes", "gas", "christmas", "politics", "physics",
+	"economics", "mathematics", "means", "whereas", "towards", "afterwards", "sometimes", "clothes", "evening",
+	"during", "nothing", "something", "anything", "everything", "thing", "morning", "ceiling", "wedding",
+	"indeed", "hundred", "kindness", "analysis", "basis", "crisis", "this", "thus", "bus", "canvas",
+	"need", "seed", "feed", "weed", "speed", "breed", "bleed", "deed", "greed", "proceed", "succeed", "exceed",
+	"united", "lens", "chaos", "cosmos", "ethos", "pathos",
+})
+
+// Lemma returns the dictionary form of the English word, e.g. "went" → "go", "studies" → "study".
+// The word is expected to be lowercase. Irregular forms are looked up in the table,
+// regular inflections are removed preferring the forms found in the frequency list.
+// The word is kept as it is if none of them is known and the ending can't be removed reliably.
+func Lemma(word string) string {
+	lemma, _ := KnownLemma(word)
+	return lemma
+}
+
+// KnownLemma returns the lemma of the English word as Lemma does, and whether it's certain.
+// The lemma isn't certain if it's derived by removing the ending without being found in the frequency list,
+// e.g. "hedgehogs" → "hedgehog".
+func KnownLemma(word string) (string, bool) {
+	if lemma, ok := irregularForms[word]; ok {
+		return lemma, true
+	}
+	if _, ok := invariantForms[word]; ok {
+		return word, true
+	}
+	candidates, fallback := regularLemmas(word)
+	for _, c := range candidates {
+		if isKnownLemma(c) {
+			return c, true
+		}
+	}
+	if isKnownLemma(word) || !plausibleLemma(fallback) {
+		return word, true
+	}
+	return fallback, false
+}
+
+// plausibleLemma checks if the word derived by removing the ending is long enough and has a vowel,
+// so that words like "string" or "seed" are not mistaken for inflected forms.
+func plausibleLemma(word string) bool {
+	return len([]rune(word)) >= 3 && strings.ContainsAny(word, "aeiouy")
+}
+
+// regularLemmas returns possible lemmas of the word derived by removing regular inflection endings
+// in the order they should be checked, and the lemma to use if none of them is known.
+// Returns no candidates if the word doesn't look inflected and "" as the fallback if only a known lemma may be taken.
+func regularLemmas(word string) (candidates []string, fallback string) {
+	switch {
+	case hasStem(word, "ies", 2):
+		stem := strings.TrimSuffix(word, "ies")
+		return []string{stem + "y", stem + "ie"}, stem + "y"
+	case hasStem(word, "ied", 2):
+		stem := strings.TrimSuffix(word, "ied")
+		return []string{stem + "y"}, stem + "y"
+	case hasStem(word, "ves", 2):
+		stem := strings.TrimSuffix(word, "ves")
+		return []string{stem + "f", stem + "fe", stem + "ve"}, stem + "ve"
+	case hasStem(word, "es", 2):
+		stem := strings.TrimSuffix(word, "es")
+		if takesBareEs(stem) {
+			return []string{stem + "e", stem}, stem
+		}
+		return []string{stem + "e", stem}, stem + "e"
+	case hasStem(word, "s", 3) && !hasAnySuffix(word, "ss", "us", "is", "ous"):
+		stem := strings.TrimSuffix(word, "s")
+		if strings.HasSuffix(word, "as") {
+			// most of the words ending with -as are singular, e.g. "bias", so only the known lemma is taken
+			return []string{stem}, ""
+		}
+		return []string{stem}, stem
+	case hasStem(word, "ing", 3):
+		stem := strings.TrimSuffix(word, "ing")
+		return []string{stem + "e", stem, undouble(stem)}, verbFallback(stem)
+	case hasStem(word, "ed", 3):
+		stem := strings.TrimSuffix(word, "ed")
+		return []string{stem + "e", stem, undouble(stem)}, verbFallback(stem)
+	default:
+		return nil, ""
+	}
+}
+
+// verbFallback returns the lemma to use for the unknown word ending with -ing or -ed.
+// The ending is only removed if the stem has a doubled final consonant or several syllables,
+// e.g. "jogged" → "jog" or "embroidered" → "embroider", as the short stems usually belong
+// to the words which aren't inflected, e.g. "naked" or "wicked". Returns "" to keep the word.
+func verbFallback(stem string) string {
+	if undoubled := undouble(stem); undoubled != stem {
+		return undoubled
+	}
+	if syllables(stem) > 1 {
+		return stem
+	}
+	return ""
+}
+
+// syllables approximates the number of syllables of the word by counting the groups of vowels in it.
+func syllables(word string) int {
+	n := 0
+	inVowels := false
+	for _, r := range word {
+		isVowel := strings.ContainsRune("aeiouy", r)
+		if isVowel && !inVowels {
+			n++
+		}
+		inVowels = isVowel
+	}
+	return n
+}
+
+// hasStem checks if the word ends with the suffix and has at least minStemLen letters before it.
+func hasStem(word, suffix string, minStemLen int) bool {
+	return strings.HasSuffix(word, suffix) && len([]rune(word))-len([]rune(suffix)) >= minStemLen
+}
+
+func hasAnySuffix(word string, suffixes ...string) bool {
+	for _, s := range suffixes {
+		if strings.HasSuffix(word, s) {
+			return true
+		}
+	}
+	return false
+}
+
+// takesBareEs checks if the word takes -es without the final e being a part of the lemma, e.g. "box" → "boxes".
+// The stems ending with a single s or z usually lost the final e of the lemma, e.g. "nurses" or "prizes".
+func takesBareEs(stem string) bool {
+	return hasAnySuffix(stem, "ss", "zz", "x", "ch", "sh")
+}
+
+// undouble removes the doubled final consonant added before -ing and -ed, e.g. "runn" → "run".
+// Final "ll", "ss" and "zz" are kept as they are usually a part of the lemma.
+func undouble(stem string) string {
+	runes := []rune(stem)
+	n := len(runes)
+	if n < 3 || runes[n-1] != runes[n-2] || strings.ContainsRune("aeiouylsz", runes[n-1]) {
+		return stem
+	}
+	return string(runes[:n-1])
+}
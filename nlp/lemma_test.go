@@ -0,0 +1,81 @@
+package nlp
+
+import "testing"
+
+func TestLemma(t *testing.T) {
+	testCases := []struct {
+		word     string
+		expected string
+	}{
+		{word: "went", expected: "go"},
+		{word: "children", expected: "child"},
+		{word: "mice", expected: "mouse"},
+		{word: "studies", expected: "study"},
+		{word: "studied", expected: "study"},
+		{word: "boxes", expected: "box"},
+		{word: "houses", expected: "house"},
+		{word: "nurses", expected: "nurse"},
+		{word: "prizes", expected: "prize"},
+		{word: "buzzes", expected: "buzz"},
+		{word: "classes", expected: "class"},
+		{word: "ideas", expected: "idea"},
+		{word: "bias", expected: "bias"},
+		{word: "goes", expected: "go"},
+		{word: "hedgehogs", expected: "hedgehog"},
+		{word: "making", expected: "make"},
+		{word: "running", expected: "run"},
+		{word: "reading", expected: "read"},
+		{word: "used", expected: "use"},
+		{word: "wanted", expected: "want"},
+		{word: "stopped", expected: "stop"},
+		{word: "embroidered", expected: "embroider"},
+		{word: "news", expected: "news"},
+		{word: "business", expected: "business"},
+		{word: "string", expected: "string"},
+		{word: "seed", expected: "seed"},
+		{word: "hedgehog", expected: "hedgehog"},
+		{word: "naked", expected: "naked"},
+		{word: "sacred", expected: "sacred"},
+		{word: "bored", expected: "bored"},
+		{word: "united", expected: "united"},
+		{word: "wicked", expected: "wicked"},
+		{word: "lens", expected: "lens"},
+		{word: "chaos", expected: "chaos"},
+		{word: "shed", expected: "shed"},
+		{word: "jogged", expected: "jog"},
+		{word: "going", expected: "go"},
+	}
+	for _, c := range testCases {
+		t.Run(c.word, func(t *testing.T) {
+			res := Lemma(c.word)
+			if res != c.expected {
+				t.Errorf("Expected lemma:%s;Actual:%s", c.expected, res)
+			}
+		})
+	}
+}
+
+func TestKnownLemma(t *testing.T) {
+	testCases := []struct {
+		word          string
+		expected      string
+		expectedKnown bool
+	}{
+		{word: "went", expected: "go", expectedKnown: true},
+		{word: "wanted", expected: "want", expectedKnown: true},
+		{word: "hedgehog", expected: "hedgehog", expectedKnown: true},
+		{word: "hedgehogs", expected: "hedgehog"},
+		{word: "embroidered", expected: "embroider"},
+	}
+	for _, c := range testCases {
+		t.Run(c.word, func(t *testing.T) {
+			res, known := KnownLemma(c.word)
+			if res != c.expected {
+				t.Errorf("Expected lemma:%s;Actual:%s", c.expected, res)
+			}
+			if known != c.expectedKnown {
+				t.Errorf("Expected known:%v;Actual:%v", c.expectedKnown, known)
+			}
+		})
+	}
+}
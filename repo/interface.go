@@ -1,20 +1,74 @@
 package repo
 
-import "github.com/dmalyar/pimpmyvocab/domain"
+import (
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"time"
+)
 
 // Vocab provides methods for interacting with vocabs on repository level.
 type Vocab interface {
 	AddVocab(vocab *domain.Vocab) (*domain.Vocab, error)
-	GetVocabByUserID(userID int) (*domain.Vocab, error)
-	ClearVocabByUserID(userID int) error
+	GetVocabsByUserID(userID int) ([]*domain.Vocab, error)
+	GetActiveVocabByUserID(userID int) (*domain.Vocab, error)
+	GetVocabByName(userID int, name string) (*domain.Vocab, error)
+	SetActiveVocab(vocab *domain.Vocab) error
+	ClearVocab(vocabID int) error
 
 	AddVocabEntry(entry *domain.VocabEntry) (*domain.VocabEntry, error)
-	GetVocabEntryByText(text string) (*domain.VocabEntry, error)
+	GetVocabEntryByText(text string, langPair domain.LangPair) (*domain.VocabEntry, error)
+	GetCustomVocabEntryByText(text string, langPair domain.LangPair, userID int) (*domain.VocabEntry, error)
 	GetVocabEntryByID(id int) (*domain.VocabEntry, error)
+	AddVocabEntryAlias(alias string, entryID int) error
+	GetVocabEntryByAlias(alias string, langPair domain.LangPair) (*domain.VocabEntry, error)
 
-	AddEntryToUserVocab(entryID, userID int) error
-	CheckEntryInUserVocab(entryID, userID int) (bool, error)
-	GetEntryIDsByUserID(userID int) ([]int, error)
-	GetEntriesByUserID(userID int) ([]*domain.VocabEntry, error)
-	RemoveEntryFromUserVocab(entryID, userID int) error
+	AddEntryToVocab(entryID, vocabID int) error
+	CheckEntryInVocab(entryID, vocabID int) (bool, error)
+	GetEntryIDsByVocabID(vocabID int) ([]int, error)
+	GetEntriesByVocabID(vocabID int) ([]*domain.VocabEntry, error)
+	GetExportEntriesByVocabID(vocabID int) ([]*domain.ExportEntry, error)
+	GetEntriesPageByVocabID(vocabID, limit, offset int) ([]*domain.VocabEntry, error)
+	CountEntriesByVocabID(vocabID int) (int, error)
+	FindEntriesByVocabID(vocabID int, query string, limit, offset int) ([]*domain.VocabEntry, error)
+	CountFoundEntriesByVocabID(vocabID int, query string) (int, error)
+	RemoveEntryFromVocab(entryID, vocabID int) error
+}
+
+// Dictionary provides methods for bulk loading of the dictionary entries on repository level.
+type Dictionary interface {
+	AddVocabEntries(entries []*domain.VocabEntry) (int, error)
+}
+
+// Schedule provides methods for interacting with review schedules of the entries from the user's active vocab
+// on repository level.
+type Schedule interface {
+	GetEntryIDsToReview(userID int, direction domain.Direction) ([]int, error)
+	CountEntriesToReview(userID int, direction domain.Direction, at time.Time) (int, error)
+	GetSchedule(entryID, userID int, direction domain.Direction) (*domain.Schedule, error)
+	SaveReview(review *domain.Review, schedule *domain.Schedule, userID int) (*domain.EntryProgress, error)
+}
+
+// QuizSession provides methods for interacting with the quiz sessions of the user's active vocab on repository level.
+type QuizSession interface {
+	AddQuizSession(session *domain.QuizSession, userID int) (*domain.QuizSession, error)
+	GetActiveQuizSession(userID int) (*domain.QuizSession, error)
+	GradeQuizSessionEntry(sessionID, entryID int, grade domain.Grade) (bool, error)
+	FinishQuizSession(sessionID int, finishedAt time.Time) error
+}
+
+// Stats provides methods for collecting the user's learning statistics across all the user's vocabs
+// on repository level.
+type Stats interface {
+	CountEntries(userID int) (int, error)
+	CountEntriesAddedSince(userID int, since time.Time) (int, error)
+	CountReviewsSince(userID int, since time.Time) (total, recalled int, err error)
+	GetDailyReviewCounts(userID int, timezone string, since time.Time) ([]*domain.DailyReviews, error)
+}
+
+// UserSettings provides methods for interacting with the user's settings on repository level.
+type UserSettings interface {
+	GetUserSettings(userID int) (*domain.UserSettings, error)
+	SaveUserSettings(settings *domain.UserSettings) error
+	GetUserSettingsWithRemindersEnabled() ([]*domain.UserSettings, error)
+	MarkReminded(userID int, date time.Time) (bool, error)
+	MarkGoalReached(userID int, date time.Time) (bool, error)
 }
@@ -0,0 +1,8 @@
+begin;
+drop index if exists vocab_to_entry_link_due_at_index;
+alter table vocab_to_entry_link
+    drop column if exists ease_factor,
+    drop column if exists interval_days,
+    drop column if exists repetitions,
+    drop column if exists due_at;
+commit;
\ No newline at end of file
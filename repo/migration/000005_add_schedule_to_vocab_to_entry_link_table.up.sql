@@ -0,0 +1,9 @@
+begin;
+alter table vocab_to_entry_link
+    add column if not exists ease_factor   double precision not null default 2.5,
+    add column if not exists interval_days integer not null default 0,
+    add column if not exists repetitions   integer not null default 0,
+    add column if not exists due_at        timestamptz not null default now();
+create index if not exists vocab_to_entry_link_due_at_index
+    on vocab_to_entry_link (vocab_id, due_at);
+commit;
@@ -0,0 +1,4 @@
+begin;
+drop index if exists review_vocab_id_reviewed_at_index;
+drop table if exists review;
+commit;
\ No newline at end of file
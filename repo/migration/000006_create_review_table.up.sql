@@ -0,0 +1,18 @@
+begin;
+create table if not exists review
+(
+    id          serial      not null
+        constraint review_pkey
+            primary key,
+    vocab_id    integer     not null
+        constraint review_vocab_id_fkey
+            references vocab,
+    entry_id    integer     not null
+        constraint review_entry_id_fkey
+            references vocab_entry,
+    grade       integer     not null,
+    reviewed_at timestamptz not null default now()
+);
+create index if not exists review_vocab_id_reviewed_at_index
+    on review (vocab_id, reviewed_at);
+commit;
@@ -0,0 +1,22 @@
+begin;
+alter table review
+    drop column if exists direction;
+alter table vocab_to_entry_link
+    add column if not exists ease_factor   double precision not null default 2.5,
+    add column if not exists interval_days integer not null default 0,
+    add column if not exists repetitions   integer not null default 0,
+    add column if not exists due_at        timestamptz not null default now();
+create index if not exists vocab_to_entry_link_due_at_index
+    on vocab_to_entry_link (vocab_id, due_at);
+update vocab_to_entry_link l
+set ease_factor   = s.ease_factor,
+    interval_days = s.interval_days,
+    repetitions   = s.repetitions,
+    due_at        = s.due_at
+from schedule s
+where s.vocab_id = l.vocab_id
+  and s.entry_id = l.entry_id
+  and s.direction = 0;
+drop index if exists schedule_due_at_index;
+drop table if exists schedule;
+commit;
\ No newline at end of file
@@ -0,0 +1,30 @@
+begin;
+create table if not exists schedule
+(
+    vocab_id      integer          not null,
+    entry_id      integer          not null,
+    direction     integer          not null,
+    ease_factor   double precision not null,
+    interval_days integer          not null,
+    repetitions   integer          not null,
+    due_at        timestamptz      not null,
+    constraint schedule_pkey
+        primary key (vocab_id, entry_id, direction),
+    constraint schedule_link_fkey
+        foreign key (vocab_id, entry_id) references vocab_to_entry_link (vocab_id, entry_id)
+            on delete cascade
+);
+create index if not exists schedule_due_at_index
+    on schedule (vocab_id, direction, due_at);
+insert into schedule(vocab_id, entry_id, direction, ease_factor, interval_days, repetitions, due_at)
+select vocab_id, entry_id, 0, ease_factor, interval_days, repetitions, due_at
+from vocab_to_entry_link;
+drop index if exists vocab_to_entry_link_due_at_index;
+alter table vocab_to_entry_link
+    drop column if exists ease_factor,
+    drop column if exists interval_days,
+    drop column if exists repetitions,
+    drop column if exists due_at;
+alter table review
+    add column if not exists direction integer not null default 0;
+commit;
@@ -0,0 +1,5 @@
+begin;
+drop table if exists quiz_session_entry;
+drop index if exists quiz_session_active_uindex;
+drop table if exists quiz_session;
+commit;
@@ -0,0 +1,31 @@
+begin;
+create table if not exists quiz_session
+(
+    id          serial      not null
+        constraint quiz_session_pkey
+            primary key,
+    vocab_id    integer     not null
+        constraint quiz_session_vocab_id_fkey
+            references vocab,
+    direction   integer     not null,
+    started_at  timestamptz not null default now(),
+    finished_at timestamptz
+);
+create unique index if not exists quiz_session_active_uindex
+    on quiz_session (vocab_id)
+    where finished_at is null;
+create table if not exists quiz_session_entry
+(
+    session_id integer not null
+        constraint quiz_session_entry_session_id_fkey
+            references quiz_session
+            on delete cascade,
+    entry_id   integer not null
+        constraint quiz_session_entry_entry_id_fkey
+            references vocab_entry,
+    position   integer not null,
+    grade      integer,
+    constraint quiz_session_entry_pkey
+        primary key (session_id, entry_id)
+);
+commit;
@@ -0,0 +1,4 @@
+begin;
+alter table vocab_to_entry_link
+    drop column if exists added_at;
+commit;
@@ -0,0 +1,6 @@
+begin;
+alter table vocab_to_entry_link
+    add column if not exists added_at timestamptz;
+alter table vocab_to_entry_link
+    alter column added_at set default now();
+commit;
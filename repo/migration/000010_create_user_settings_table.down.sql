@@ -0,0 +1,4 @@
+begin;
+drop index if exists user_settings_reminders_enabled_index;
+drop table if exists user_settings;
+commit;
@@ -0,0 +1,15 @@
+begin;
+create table if not exists user_settings
+(
+    user_id           integer not null
+        constraint user_settings_pkey
+            primary key,
+    chat_id           bigint  not null,
+    remind_at         integer not null default 540,
+    timezone          text    not null default 'UTC',
+    reminders_enabled boolean not null default false,
+    last_reminded_on  date
+);
+create index if not exists user_settings_reminders_enabled_index
+    on user_settings (reminders_enabled);
+commit;
@@ -0,0 +1,4 @@
+begin;
+alter table user_settings
+    drop column if exists daily_goal;
+commit;
@@ -0,0 +1,4 @@
+begin;
+alter table user_settings
+    add column if not exists daily_goal integer not null default 0;
+commit;
@@ -0,0 +1,5 @@
+begin;
+alter table vocab_to_entry_link
+    drop column if exists correct_streak,
+    drop column if exists state;
+commit;
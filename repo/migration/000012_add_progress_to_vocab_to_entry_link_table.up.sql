@@ -0,0 +1,14 @@
+begin;
+alter table vocab_to_entry_link
+    add column if not exists correct_streak integer not null default 0,
+    add column if not exists state          integer not null default 0;
+update vocab_to_entry_link l
+set correct_streak = s.repetitions
+from schedule s
+where s.vocab_id = l.vocab_id
+  and s.entry_id = l.entry_id
+  and s.direction = 0;
+update vocab_to_entry_link
+set state = 1
+where correct_streak >= 6;
+commit;
@@ -0,0 +1,34 @@
+begin;
+alter table user_settings
+    drop column if exists source_lang,
+    drop column if exists target_lang;
+
+create temporary table non_default_lang_entry on commit drop as
+select id
+from vocab_entry
+where source_lang <> 'en'
+   or target_lang <> 'ru';
+delete
+from quiz_session_entry
+where entry_id in (select id from non_default_lang_entry);
+delete
+from review
+where entry_id in (select id from non_default_lang_entry);
+delete
+from vocab_to_entry_link
+where entry_id in (select id from non_default_lang_entry);
+delete
+from translation
+where vocab_entry_id in (select id from non_default_lang_entry);
+delete
+from vocab_entry
+where id in (select id from non_default_lang_entry);
+
+alter table vocab_entry
+    drop constraint if exists vocab_entry_text_lang_pair_key;
+alter table vocab_entry
+    add constraint vocab_entry_text_key unique (text);
+alter table vocab_entry
+    drop column if exists source_lang,
+    drop column if exists target_lang;
+commit;
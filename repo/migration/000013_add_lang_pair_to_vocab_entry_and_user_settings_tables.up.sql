@@ -0,0 +1,13 @@
+begin;
+alter table vocab_entry
+    add column if not exists source_lang text not null default 'en',
+    add column if not exists target_lang text not null default 'ru';
+alter table vocab_entry
+    drop constraint if exists vocab_entry_text_key;
+alter table vocab_entry
+    add constraint vocab_entry_text_lang_pair_key unique (text, source_lang, target_lang);
+
+alter table user_settings
+    add column if not exists source_lang text not null default 'en',
+    add column if not exists target_lang text not null default 'ru';
+commit;
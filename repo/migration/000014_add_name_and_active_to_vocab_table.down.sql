@@ -0,0 +1,26 @@
+begin;
+create temporary table inactive_vocab on commit drop as
+select id
+from vocab
+where not active;
+delete
+from quiz_session
+where vocab_id in (select id from inactive_vocab);
+delete
+from review
+where vocab_id in (select id from inactive_vocab);
+delete
+from vocab_to_entry_link
+where vocab_id in (select id from inactive_vocab);
+delete
+from vocab
+where id in (select id from inactive_vocab);
+
+drop index if exists vocab_user_id_active_index;
+drop index if exists vocab_user_id_name_index;
+alter table vocab
+    add constraint vocab_user_id_key unique (user_id);
+alter table vocab
+    drop column if exists name,
+    drop column if exists active;
+commit;
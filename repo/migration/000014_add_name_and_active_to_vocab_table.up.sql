@@ -0,0 +1,14 @@
+begin;
+alter table vocab
+    add column if not exists name   text    not null default 'Основной',
+    add column if not exists active boolean not null default true;
+alter table vocab
+    alter column name drop default;
+alter table vocab
+    drop constraint if exists vocab_user_id_key;
+create unique index if not exists vocab_user_id_name_index
+    on vocab (user_id, lower(name));
+create unique index if not exists vocab_user_id_active_index
+    on vocab (user_id)
+    where active;
+commit;
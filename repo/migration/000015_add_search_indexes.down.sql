@@ -0,0 +1,5 @@
+begin;
+drop index if exists translation_class_index;
+drop index if exists translation_text_trgm_index;
+drop index if exists vocab_entry_text_trgm_index;
+commit;
@@ -0,0 +1,9 @@
+begin;
+create extension if not exists pg_trgm;
+create index if not exists vocab_entry_text_trgm_index
+    on vocab_entry using gin (text gin_trgm_ops);
+create index if not exists translation_text_trgm_index
+    on translation using gin (text gin_trgm_ops);
+create index if not exists translation_class_index
+    on translation (class);
+commit;
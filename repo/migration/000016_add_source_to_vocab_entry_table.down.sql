@@ -0,0 +1,4 @@
+begin;
+alter table vocab_entry
+    drop column if exists source;
+commit;
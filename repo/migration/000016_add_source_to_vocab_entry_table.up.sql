@@ -0,0 +1,20 @@
+begin;
+alter table vocab_entry
+    add column if not exists source text;
+
+-- entries added with the user's own translation have the only translation without class and no transcription
+update vocab_entry e
+set source = 'user'
+where source is null
+  and coalesce(e.transcription, '') = ''
+  and (select count(*) from translation t where t.vocab_entry_id = e.id) = 1
+  and exists(select 1 from translation t where t.vocab_entry_id = e.id and coalesce(t.class, '') = '');
+-- all other entries were found in the yandex dictionary
+update vocab_entry
+set source = 'yandex'
+where source is null;
+
+alter table vocab_entry
+    alter column source set not null,
+    alter column source set default '';
+commit;
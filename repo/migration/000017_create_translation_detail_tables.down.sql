@@ -0,0 +1,8 @@
+begin;
+drop table if exists translation_example;
+drop table if exists translation_meaning;
+drop table if exists translation_synonym;
+alter table translation
+    drop column if exists gender,
+    drop column if exists aspect;
+commit;
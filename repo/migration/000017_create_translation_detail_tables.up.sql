@@ -0,0 +1,48 @@
+begin;
+alter table translation
+    add column if not exists gender text not null default '',
+    add column if not exists aspect text not null default '';
+
+create table if not exists translation_synonym
+(
+    id             serial  not null
+        constraint translation_synonym_pkey
+            primary key,
+    translation_id integer not null
+        constraint translation_synonym_translation_id_fkey
+            references translation,
+    text           text    not null,
+    position       integer not null
+);
+create index if not exists translation_synonym_translation_id_index
+    on translation_synonym (translation_id);
+
+create table if not exists translation_meaning
+(
+    id             serial  not null
+        constraint translation_meaning_pkey
+            primary key,
+    translation_id integer not null
+        constraint translation_meaning_translation_id_fkey
+            references translation,
+    text           text    not null,
+    position       integer not null
+);
+create index if not exists translation_meaning_translation_id_index
+    on translation_meaning (translation_id);
+
+create table if not exists translation_example
+(
+    id             serial  not null
+        constraint translation_example_pkey
+            primary key,
+    translation_id integer not null
+        constraint translation_example_translation_id_fkey
+            references translation,
+    text           text    not null,
+    translation    text    not null,
+    position       integer not null
+);
+create index if not exists translation_example_translation_id_index
+    on translation_example (translation_id);
+commit;
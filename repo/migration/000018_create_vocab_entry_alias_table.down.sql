@@ -0,0 +1,3 @@
+begin;
+drop table if exists vocab_entry_alias;
+commit;
@@ -0,0 +1,14 @@
+begin;
+create table if not exists vocab_entry_alias
+(
+    id       serial  not null
+        constraint vocab_entry_alias_pkey
+            primary key,
+    text     text    not null,
+    entry_id integer not null
+        constraint vocab_entry_alias_entry_id_fkey
+            references vocab_entry,
+    constraint vocab_entry_alias_text_entry_id_key
+        unique (text, entry_id)
+);
+commit;
@@ -0,0 +1,4 @@
+begin;
+alter table user_settings
+    drop column if exists goal_reached_on;
+commit;
@@ -0,0 +1,4 @@
+begin;
+alter table user_settings
+    add column if not exists goal_reached_on date;
+commit;
@@ -0,0 +1,8 @@
+begin;
+drop index if exists vocab_entry_user_id_text_lang_pair_uindex;
+drop index if exists vocab_entry_text_lang_pair_uindex;
+alter table vocab_entry
+    drop column if exists user_id;
+alter table vocab_entry
+    add constraint vocab_entry_text_lang_pair_key unique (text, source_lang, target_lang);
+commit;
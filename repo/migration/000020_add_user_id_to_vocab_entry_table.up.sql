@@ -0,0 +1,57 @@
+begin;
+alter table vocab_entry
+    add column if not exists user_id integer;
+
+-- the custom entry belongs to the first user who has it in the vocab,
+-- or who has reviewed it or got it in a quiz if it was removed from the vocab
+update vocab_entry e
+set user_id = coalesce(
+        (select min(v.user_id)
+         from vocab_to_entry_link l
+                  join vocab v on l.vocab_id = v.id
+         where l.entry_id = e.id),
+        (select min(v.user_id)
+         from review r
+                  join vocab v on r.vocab_id = v.id
+         where r.entry_id = e.id),
+        (select min(v.user_id)
+         from quiz_session_entry qe
+                  join quiz_session q on qe.session_id = q.id
+                  join vocab v on q.vocab_id = v.id
+         where qe.entry_id = e.id))
+where e.source = 'user';
+
+-- the custom entries nobody has ever used are removed, so that they don't become global ones
+create temp table orphan_entry on commit drop as
+select id
+from vocab_entry
+where source = 'user'
+  and user_id is null;
+delete
+from translation_synonym
+where translation_id in (select id from translation where vocab_entry_id in (select id from orphan_entry));
+delete
+from translation_meaning
+where translation_id in (select id from translation where vocab_entry_id in (select id from orphan_entry));
+delete
+from translation_example
+where translation_id in (select id from translation where vocab_entry_id in (select id from orphan_entry));
+delete
+from translation
+where vocab_entry_id in (select id from orphan_entry);
+delete
+from vocab_entry_alias
+where entry_id in (select id from orphan_entry);
+delete
+from vocab_entry
+where id in (select id from orphan_entry);
+
+alter table vocab_entry
+    drop constraint if exists vocab_entry_text_lang_pair_key;
+create unique index if not exists vocab_entry_text_lang_pair_uindex
+    on vocab_entry (text, source_lang, target_lang)
+    where user_id is null;
+create unique index if not exists vocab_entry_user_id_text_lang_pair_uindex
+    on vocab_entry (user_id, text, source_lang, target_lang)
+    where user_id is not null;
+commit;
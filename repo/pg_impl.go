@@ -7,9 +7,11 @@ import (
 	"github.com/dmalyar/pimpmyvocab/log"
 	"github.com/jackc/pgx/v4"
 	"github.com/jackc/pgx/v4/pgxpool"
+	"strings"
+	"time"
 )
 
-// Postgres implements repo.Vocab interface for working with PostgreSQL DB.
+// Postgres implements repo.Vocab, repo.Dictionary, repo.Schedule, repo.QuizSession, repo.Stats and repo.UserSettings interfaces for working with PostgreSQL DB.
 type Postgres struct {
 	logger log.Logger
 	pool   *pgxpool.Pool
@@ -20,66 +22,285 @@ func NewPostgresRepo(logger log.Logger, pool *pgxpool.Pool) *Postgres {
 }
 
 const (
-	addVocab           = "INSERT INTO vocab(user_id) VALUES ($1) RETURNING id"
-	getVocabByUserID   = "SELECT id, user_id FROM vocab WHERE user_id = $1"
-	clearVocabByUserID = "DELETE FROM vocab_to_entry_link " +
-		"WHERE vocab_id = (SELECT ID from vocab WHERE user_id = $1)"
-
-	addVocabEntry = "INSERT INTO vocab_entry(text, transcription) " +
-		"VALUES ($1, $2) RETURNING id"
-	getVocabEntryByText = "SELECT id, text, transcription " +
-		"FROM vocab_entry WHERE text = $1"
-	getVocabEntryByID = "SELECT id, text, transcription " +
+	addVocab               = "INSERT INTO vocab(user_id, name, active) VALUES ($1, $2, $3) RETURNING id"
+	deactivateVocabs       = "UPDATE vocab SET active = false WHERE user_id = $1 AND active"
+	activateVocab          = "UPDATE vocab SET active = true WHERE id = $1 AND user_id = $2"
+	getVocabsByUserID      = "SELECT id, user_id, name, active FROM vocab WHERE user_id = $1 ORDER BY id"
+	getActiveVocabByUserID = "SELECT id, user_id, name, active FROM vocab WHERE user_id = $1 AND active"
+	getVocabByName         = "SELECT id, user_id, name, active FROM vocab WHERE user_id = $1 AND lower(name) = lower($2)"
+	clearVocab             = "DELETE FROM vocab_to_entry_link WHERE vocab_id = $1"
+
+	addVocabEntry = "INSERT INTO vocab_entry(text, source_lang, target_lang, transcription, source, user_id) " +
+		"VALUES ($1, $2, $3, $4, $5, NULLIF($6, 0)) RETURNING id"
+	createVocabEntryStaging = "CREATE TEMP TABLE vocab_entry_staging " +
+		"(idx integer, text text, source_lang text, target_lang text, transcription text, source text) " +
+		"ON COMMIT DROP"
+	addVocabEntriesFromStaging = "INSERT INTO vocab_entry(text, source_lang, target_lang, transcription, source) " +
+		"SELECT text, source_lang, target_lang, transcription, source FROM vocab_entry_staging ORDER BY idx " +
+		"ON CONFLICT (text, source_lang, target_lang) WHERE user_id IS NULL DO NOTHING " +
+		"RETURNING id, text, source_lang, target_lang"
+	getVocabEntryByText = "SELECT id, text, source_lang, target_lang, transcription, source, " +
+		"COALESCE(user_id, 0) " +
+		"FROM vocab_entry WHERE text = $1 AND source_lang = $2 AND target_lang = $3 AND user_id IS NULL"
+	getCustomVocabEntryByText = "SELECT id, text, source_lang, target_lang, transcription, source, " +
+		"COALESCE(user_id, 0) " +
+		"FROM vocab_entry WHERE text = $1 AND source_lang = $2 AND target_lang = $3 AND user_id = $4"
+	getVocabEntryByID = "SELECT id, text, source_lang, target_lang, transcription, source, COALESCE(user_id, 0) " +
 		"FROM vocab_entry WHERE id = $1"
+	addVocabEntryAlias = "INSERT INTO vocab_entry_alias(text, entry_id) " +
+		"VALUES ($1, $2) ON CONFLICT (text, entry_id) DO NOTHING"
+	getVocabEntryByAlias = "SELECT e.id, e.text, e.source_lang, e.target_lang, e.transcription, e.source, " +
+		"COALESCE(e.user_id, 0) " +
+		"FROM vocab_entry_alias a " +
+		"JOIN vocab_entry e on a.entry_id = e.id " +
+		"WHERE a.text = $1 AND e.source_lang = $2 AND e.target_lang = $3 AND e.user_id IS NULL " +
+		"ORDER BY a.id LIMIT 1"
+
+	addEntryToVocab   = "INSERT INTO vocab_to_entry_link(entry_id, vocab_id) VALUES ($1, $2)"
+	checkEntryInVocab = "SELECT entry_id " +
+		"FROM vocab_to_entry_link " +
+		"WHERE entry_id = $1 AND vocab_id = $2"
+	getEntryIDsByVocabID = "SELECT entry_id " +
+		"FROM vocab_to_entry_link " +
+		"WHERE vocab_id = $1"
+	getEntriesByVocabID = "SELECT e.id, e.text, e.source_lang, e.target_lang, e.transcription, " +
+		"t.id, t.text, t.class, l.correct_streak " +
+		"FROM vocab_to_entry_link l " +
+		"JOIN vocab_entry e on l.entry_id = e.id " +
+		"JOIN translation t on e.id = t.vocab_entry_id " +
+		"WHERE l.vocab_id = $1 AND t.position = 0"
+	getExportEntriesByVocabID = "SELECT e.id, e.text, e.source_lang, e.target_lang, e.transcription, " +
+		"l.added_at, l.correct_streak, l.state " +
+		"FROM vocab_to_entry_link l " +
+		"JOIN vocab_entry e on l.entry_id = e.id " +
+		"WHERE l.vocab_id = $1 " +
+		"ORDER BY l.added_at NULLS FIRST, e.text, e.id"
+	getTranslationsByVocabID = "SELECT t.vocab_entry_id, t.id, t.text, t.class, t.position " +
+		"FROM vocab_to_entry_link l " +
+		"JOIN translation t on l.entry_id = t.vocab_entry_id " +
+		"WHERE l.vocab_id = $1 " +
+		"ORDER BY t.vocab_entry_id, t.position"
+	// getEntriesPageByVocabID orders entries by mastery level using the thresholds of domain.MasteryLevelOf
+	// passed as $4, $5 and $6, so that the entries of the same level go one after another through the pages.
+	getEntriesPageByVocabID = "SELECT e.id, e.text, e.source_lang, e.target_lang, e.transcription, " +
+		"t.id, t.text, t.class, l.correct_streak " +
+		"FROM vocab_to_entry_link l " +
+		"JOIN vocab_entry e on l.entry_id = e.id " +
+		"JOIN translation t on e.id = t.vocab_entry_id " +
+		"WHERE l.vocab_id = $1 AND t.position = 0 " +
+		"ORDER BY CASE WHEN l.correct_streak >= $4 THEN 3 WHEN l.correct_streak >= $5 THEN 2 " +
+		"WHEN l.correct_streak >= $6 THEN 1 ELSE 0 END, e.text, e.id " +
+		"LIMIT $2 OFFSET $3"
+	countEntriesByVocabID = "SELECT count(*) FROM vocab_to_entry_link WHERE vocab_id = $1"
+	// foundEntryCondition matches entries by text prefix ($2), translation substring ($3) or translation class ($4).
+	foundEntryCondition = "(e.text ILIKE $2 OR EXISTS (" +
+		"SELECT 1 FROM translation f WHERE f.vocab_entry_id = e.id AND (f.text ILIKE $3 OR f.class = $4)))"
+	findEntriesByVocabID = "SELECT e.id, e.text, e.source_lang, e.target_lang, e.transcription, " +
+		"t.id, t.text, t.class, l.correct_streak " +
+		"FROM vocab_to_entry_link l " +
+		"JOIN vocab_entry e on l.entry_id = e.id " +
+		"JOIN translation t on e.id = t.vocab_entry_id " +
+		"WHERE l.vocab_id = $1 AND t.position = 0 AND " + foundEntryCondition + " " +
+		"ORDER BY e.text, e.id " +
+		"LIMIT $5 OFFSET $6"
+	countFoundEntriesByVocabID = "SELECT count(*) " +
+		"FROM vocab_to_entry_link l " +
+		"JOIN vocab_entry e on l.entry_id = e.id " +
+		"WHERE l.vocab_id = $1 AND " + foundEntryCondition
+	removeEntryFromVocab = "DELETE FROM vocab_to_entry_link " +
+		"WHERE entry_id = $1 AND vocab_id = $2"
 
-	addEntryToUserVocab = "INSERT INTO vocab_to_entry_link(vocab_id, entry_id)" +
-		"SELECT id, $1 FROM vocab WHERE user_id = $2"
-	checkEntryInUserVocab = "SELECT l.entry_id " +
+	addTranslation = "INSERT INTO translation(vocab_entry_id, text, class, position, gender, aspect) " +
+		"VALUES ($1, $2, $3, $4, $5, $6) RETURNING id"
+	getTranslationIDsByEntryIDs = "SELECT id, vocab_entry_id, position FROM translation " +
+		"WHERE vocab_entry_id = ANY($1)"
+	getTranslationsByEntryID = "SELECT id, text, class, position, gender, aspect " +
+		"FROM translation WHERE vocab_entry_id = $1 " +
+		"ORDER BY position"
+	addTranslationSynonym = "INSERT INTO translation_synonym(translation_id, text, position) " +
+		"VALUES ($1, $2, $3)"
+	addTranslationMeaning = "INSERT INTO translation_meaning(translation_id, text, position) " +
+		"VALUES ($1, $2, $3)"
+	addTranslationExample = "INSERT INTO translation_example(translation_id, text, translation, position) " +
+		"VALUES ($1, $2, $3, $4)"
+	getSynonymsByEntryID = "SELECT s.translation_id, s.text " +
+		"FROM translation_synonym s " +
+		"JOIN translation t on s.translation_id = t.id " +
+		"WHERE t.vocab_entry_id = $1 " +
+		"ORDER BY s.translation_id, s.position"
+	getMeaningsByEntryID = "SELECT m.translation_id, m.text " +
+		"FROM translation_meaning m " +
+		"JOIN translation t on m.translation_id = t.id " +
+		"WHERE t.vocab_entry_id = $1 " +
+		"ORDER BY m.translation_id, m.position"
+	getExamplesByEntryID = "SELECT e.translation_id, e.text, e.translation " +
+		"FROM translation_example e " +
+		"JOIN translation t on e.translation_id = t.id " +
+		"WHERE t.vocab_entry_id = $1 " +
+		"ORDER BY e.translation_id, e.position"
+
+	getEntryIDsToReview = "SELECT l.entry_id " +
 		"FROM vocab v " +
 		"JOIN vocab_to_entry_link l on v.id = l.vocab_id " +
-		"WHERE l.entry_id = $1 and v.user_id = $2"
-	getEntryIDsByUserID = "SELECT l.entry_id " +
+		"LEFT JOIN schedule s on l.vocab_id = s.vocab_id AND l.entry_id = s.entry_id AND s.direction = $2 " +
+		"WHERE v.user_id = $1 AND v.active AND (l.state = $3 OR coalesce(s.due_at, now()) <= now()) " +
+		"ORDER BY coalesce(s.due_at, now()), l.entry_id"
+	countEntriesToReview = "SELECT count(*) " +
 		"FROM vocab v " +
 		"JOIN vocab_to_entry_link l on v.id = l.vocab_id " +
-		"WHERE v.user_id = $1"
-	getEntriesByUserID = "SELECT e.id, e.text, e.transcription, t.text " +
+		"LEFT JOIN schedule s on l.vocab_id = s.vocab_id AND l.entry_id = s.entry_id AND s.direction = $2 " +
+		"WHERE v.user_id = $1 AND v.active AND (s.due_at IS NULL OR s.due_at <= $3)"
+	getSchedule = "SELECT l.entry_id, coalesce(s.ease_factor, 2.5), coalesce(s.interval_days, 0), " +
+		"coalesce(s.repetitions, 0), coalesce(s.due_at, now()) " +
 		"FROM vocab v " +
 		"JOIN vocab_to_entry_link l on v.id = l.vocab_id " +
-		"JOIN vocab_entry e on l.entry_id = e.id " +
-		"JOIN translation t on e.id = t.vocab_entry_id " +
-		"WHERE v.user_id = $1 AND t.position = 0"
-	removeEntryFromUserVocab = "DELETE FROM vocab_to_entry_link " +
+		"LEFT JOIN schedule s on l.vocab_id = s.vocab_id AND l.entry_id = s.entry_id AND s.direction = $3 " +
+		"WHERE l.entry_id = $1 AND v.user_id = $2 AND v.active"
+	updateSchedule = "INSERT INTO schedule(vocab_id, entry_id, direction, ease_factor, interval_days, repetitions, due_at) " +
+		"SELECT id, $1, $2, $3, $4, $5, $6 FROM vocab WHERE user_id = $7 AND active " +
+		"ON CONFLICT (vocab_id, entry_id, direction) DO UPDATE " +
+		"SET ease_factor = excluded.ease_factor, interval_days = excluded.interval_days, " +
+		"repetitions = excluded.repetitions, due_at = excluded.due_at"
+	addReview = "INSERT INTO review(vocab_id, entry_id, direction, grade, reviewed_at) " +
+		"SELECT id, $1, $2, $3, $4 FROM vocab WHERE user_id = $5 AND active"
+
+	getEntryProgress = "SELECT l.entry_id, l.correct_streak, l.state " +
+		"FROM vocab v " +
+		"JOIN vocab_to_entry_link l on v.id = l.vocab_id " +
+		"WHERE l.entry_id = $1 AND v.user_id = $2 AND v.active " +
+		"FOR UPDATE OF l"
+	updateEntryProgress = "UPDATE vocab_to_entry_link SET correct_streak = $2, state = $3 " +
 		"WHERE entry_id = $1 " +
-		"AND vocab_id = (SELECT ID from vocab WHERE user_id = $2)"
+		"AND vocab_id = (SELECT id FROM vocab WHERE user_id = $4 AND active)"
 
-	addTranslation = "INSERT INTO translation(vocab_entry_id, text, class, position) " +
-		"VALUES ($1, $2, $3, $4) RETURNING id"
-	getTranslationsByEntryID = "SELECT id, text, class, position " +
-		"FROM translation WHERE vocab_entry_id = $1 " +
+	finishActiveQuizSessions = "UPDATE quiz_session SET finished_at = $2 " +
+		"WHERE vocab_id = (SELECT id FROM vocab WHERE user_id = $1 AND active) AND finished_at IS NULL"
+	addQuizSession = "INSERT INTO quiz_session(vocab_id, direction, started_at) " +
+		"SELECT id, $1, $2 FROM vocab WHERE user_id = $3 AND active RETURNING id"
+	addQuizSessionEntry = "INSERT INTO quiz_session_entry(session_id, entry_id, position) " +
+		"VALUES ($1, $2, $3)"
+	getActiveQuizSession = "SELECT s.id, s.direction, s.started_at " +
+		"FROM vocab v " +
+		"JOIN quiz_session s on v.id = s.vocab_id " +
+		"WHERE v.user_id = $1 AND v.active AND s.finished_at IS NULL"
+	getQuizSessionEntries = "SELECT entry_id, grade " +
+		"FROM quiz_session_entry WHERE session_id = $1 " +
 		"ORDER BY position"
+	gradeQuizSessionEntry = "UPDATE quiz_session_entry SET grade = $3 " +
+		"WHERE session_id = $1 AND entry_id = $2 AND grade IS NULL"
+	finishQuizSession = "UPDATE quiz_session SET finished_at = $2 WHERE id = $1"
+
+	countEntries = "SELECT count(DISTINCT l.entry_id) " +
+		"FROM vocab v " +
+		"JOIN vocab_to_entry_link l on v.id = l.vocab_id " +
+		"WHERE v.user_id = $1"
+	countEntriesAddedSince = "SELECT count(DISTINCT l.entry_id) " +
+		"FROM vocab v " +
+		"JOIN vocab_to_entry_link l on v.id = l.vocab_id " +
+		"WHERE v.user_id = $1 AND l.added_at >= $2"
+	countReviewsSince = "SELECT count(*), count(*) FILTER (WHERE r.grade <> $3) " +
+		"FROM vocab v " +
+		"JOIN review r on v.id = r.vocab_id " +
+		"WHERE v.user_id = $1 AND r.reviewed_at >= $2"
+	getDailyReviewCounts = "SELECT (r.reviewed_at AT TIME ZONE $2)::date AS reviewed_on, count(*) " +
+		"FROM vocab v " +
+		"JOIN review r on v.id = r.vocab_id " +
+		"WHERE v.user_id = $1 AND r.reviewed_at >= $3 " +
+		"GROUP BY reviewed_on " +
+		"ORDER BY reviewed_on DESC"
+
+	getUserSettings = "SELECT user_id, chat_id, remind_at, timezone, reminders_enabled, last_reminded_on, daily_goal, " +
+		"source_lang, target_lang " +
+		"FROM user_settings WHERE user_id = $1"
+	saveUserSettings = "INSERT INTO user_settings(user_id, chat_id, remind_at, timezone, reminders_enabled, daily_goal, " +
+		"source_lang, target_lang) " +
+		"VALUES ($1, $2, $3, $4, $5, $6, $7, $8) " +
+		"ON CONFLICT (user_id) DO UPDATE " +
+		"SET chat_id = excluded.chat_id, remind_at = excluded.remind_at, timezone = excluded.timezone, " +
+		"reminders_enabled = excluded.reminders_enabled, daily_goal = excluded.daily_goal, " +
+		"source_lang = excluded.source_lang, target_lang = excluded.target_lang"
+	getUserSettingsWithRemindersEnabled = "SELECT user_id, chat_id, remind_at, timezone, reminders_enabled, " +
+		"last_reminded_on, daily_goal, source_lang, target_lang " +
+		"FROM user_settings WHERE reminders_enabled"
+	markReminded = "UPDATE user_settings SET last_reminded_on = $2 " +
+		"WHERE user_id = $1 AND (last_reminded_on IS NULL OR last_reminded_on < $2)"
+	markGoalReached = "UPDATE user_settings SET goal_reached_on = $2 " +
+		"WHERE user_id = $1 AND (goal_reached_on IS NULL OR goal_reached_on < $2)"
 )
 
 // AddVocab inserts the given vocab to DB and returns it with inserted ID.
+// If the vocab is active then other vocabs of the user are deactivated.
 func (p *Postgres) AddVocab(vocab *domain.Vocab) (*domain.Vocab, error) {
+	tx, err := p.pool.Begin(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("getting transaction: %s", err)
+	}
+	defer tx.Rollback(context.Background())
+
 	logger := p.logger.WithField("vocab", vocab)
 	logger.Debug("Inserting vocab into DB")
-	row := p.pool.QueryRow(context.Background(), addVocab, vocab.UserID)
-	err := row.Scan(&vocab.ID)
+	if vocab.Active {
+		_, err = tx.Exec(context.Background(), deactivateVocabs, vocab.UserID)
+		if err != nil {
+			return nil, fmt.Errorf("deactivating user's vocabs in DB: %s", err)
+		}
+	}
+	row := tx.QueryRow(context.Background(), addVocab, vocab.UserID, vocab.Name, vocab.Active)
+	err = row.Scan(&vocab.ID)
 	if err != nil {
 		return nil, fmt.Errorf("inserting vocab into DB: %s", err)
 	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("commiting transaction: %s", err)
+	}
 	logger.Debug("Vocab inserted into DB")
 	return vocab, nil
 }
 
-// GetVocabByUserID returns the vocab found by the given user ID.
+// GetVocabsByUserID returns all vocabs of the user ordered by creation.
+func (p *Postgres) GetVocabsByUserID(userID int) ([]*domain.Vocab, error) {
+	logger := p.logger.WithField("userID", userID)
+	logger.Debug("Getting vocabs by user ID from DB")
+	rows, err := p.pool.Query(context.Background(), getVocabsByUserID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("getting vocabs from DB: %s", err)
+	}
+	var vocabs []*domain.Vocab
+	for rows.Next() {
+		vocab, err := scanVocab(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning vocab row: %s", err)
+		}
+		vocabs = append(vocabs, vocab)
+	}
+	return vocabs, nil
+}
+
+// GetActiveVocabByUserID returns the active vocab of the user.
 // Returns nil and no error if vocab was not found.
-func (p *Postgres) GetVocabByUserID(userID int) (*domain.Vocab, error) {
+func (p *Postgres) GetActiveVocabByUserID(userID int) (*domain.Vocab, error) {
 	logger := p.logger.WithField("userID", userID)
-	logger.Debug("Getting vocab by user ID from DB")
-	row := p.pool.QueryRow(context.Background(), getVocabByUserID, userID)
-	vocab := new(domain.Vocab)
-	err := row.Scan(&vocab.ID, &vocab.UserID)
+	logger.Debug("Getting active vocab by user ID from DB")
+	row := p.pool.QueryRow(context.Background(), getActiveVocabByUserID, userID)
+	return p.getVocab(logger, row)
+}
+
+// GetVocabByName returns the user's vocab with the given name ignoring case.
+// Returns nil and no error if vocab was not found.
+func (p *Postgres) GetVocabByName(userID int, name string) (*domain.Vocab, error) {
+	logger := p.logger.WithFields(map[string]interface{}{
+		"userID": userID,
+		"name":   name,
+	})
+	logger.Debug("Getting vocab by name from DB")
+	row := p.pool.QueryRow(context.Background(), getVocabByName, userID, name)
+	return p.getVocab(logger, row)
+}
+
+func (p *Postgres) getVocab(logger log.Logger, row pgx.Row) (*domain.Vocab, error) {
+	vocab, err := scanVocab(row)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			logger.Debug("Vocab not found in DB")
@@ -92,13 +313,49 @@ func (p *Postgres) GetVocabByUserID(userID int) (*domain.Vocab, error) {
 	return vocab, nil
 }
 
-// ClearVocabByUserID removes all links to entries from the user's vocab.
-func (p *Postgres) ClearVocabByUserID(userID int) error {
-	logger := p.logger.WithField("userID", userID)
-	logger.Debug("Removing all links to entries from the user's vocab in DB")
-	_, err := p.pool.Exec(context.Background(), clearVocabByUserID, userID)
+func scanVocab(row pgx.Row) (*domain.Vocab, error) {
+	vocab := new(domain.Vocab)
+	err := row.Scan(&vocab.ID, &vocab.UserID, &vocab.Name, &vocab.Active)
+	if err != nil {
+		return nil, err
+	}
+	return vocab, nil
+}
+
+// SetActiveVocab makes the given vocab the only active vocab of its user.
+func (p *Postgres) SetActiveVocab(vocab *domain.Vocab) error {
+	tx, err := p.pool.Begin(context.Background())
+	if err != nil {
+		return fmt.Errorf("getting transaction: %s", err)
+	}
+	defer tx.Rollback(context.Background())
+
+	logger := p.logger.WithField("vocab", vocab)
+	logger.Debug("Setting active vocab in DB")
+	_, err = tx.Exec(context.Background(), deactivateVocabs, vocab.UserID)
+	if err != nil {
+		return fmt.Errorf("deactivating user's vocabs in DB: %s", err)
+	}
+	_, err = tx.Exec(context.Background(), activateVocab, vocab.ID, vocab.UserID)
+	if err != nil {
+		return fmt.Errorf("activating vocab in DB: %s", err)
+	}
+
+	err = tx.Commit(context.Background())
 	if err != nil {
-		return fmt.Errorf("removing all links to entries from user's vocab in DB: %s", err)
+		return fmt.Errorf("commiting transaction: %s", err)
+	}
+	vocab.Active = true
+	return nil
+}
+
+// ClearVocab removes all links to entries from the vocab.
+func (p *Postgres) ClearVocab(vocabID int) error {
+	logger := p.logger.WithField("vocabID", vocabID)
+	logger.Debug("Removing all links to entries from the vocab in DB")
+	_, err := p.pool.Exec(context.Background(), clearVocab, vocabID)
+	if err != nil {
+		return fmt.Errorf("removing all links to entries from vocab in DB: %s", err)
 	}
 	return nil
 }
@@ -113,19 +370,15 @@ func (p *Postgres) AddVocabEntry(entry *domain.VocabEntry) (*domain.VocabEntry,
 
 	logger := p.logger.WithField("vocabEntry", entry)
 	logger.Debugf("Inserting vocab entry into DB")
-	row := p.pool.QueryRow(context.Background(), addVocabEntry,
-		entry.Text, entry.Transcription)
+	row := tx.QueryRow(context.Background(), addVocabEntry,
+		entry.Text, entry.LangPair.Source, entry.LangPair.Target, entry.Transcription, entry.Source, entry.UserID)
 	err = row.Scan(&entry.ID)
 	if err != nil {
 		return nil, fmt.Errorf("inserting vocab entry into DB: %s", err)
 	}
-	for _, t := range entry.Translations {
-		row := p.pool.QueryRow(context.Background(), addTranslation,
-			entry.ID, t.Text, t.Class, t.Position)
-		err = row.Scan(&t.ID)
-		if err != nil {
-			return nil, fmt.Errorf("inserting translation into DB: %s", err)
-		}
+	err = addTranslations(tx, entry)
+	if err != nil {
+		return nil, err
 	}
 
 	err = tx.Commit(context.Background())
@@ -136,12 +389,217 @@ func (p *Postgres) AddVocabEntry(entry *domain.VocabEntry) (*domain.VocabEntry,
 	return entry, nil
 }
 
-// GetVocabEntryByText returns the vocab entry found by the given text.
+// AddVocabEntries inserts the given vocab entries to DB in one transaction and returns the number of inserted ones.
+// The entries already existing in DB are skipped and left unchanged, only the first of the entries
+// with the same text and language pair is inserted.
+// The entries are copied to the staging table and inserted from it by one statement,
+// their translations are copied to DB as well.
+func (p *Postgres) AddVocabEntries(entries []*domain.VocabEntry) (int, error) {
+	tx, err := p.pool.Begin(context.Background())
+	if err != nil {
+		return 0, fmt.Errorf("getting transaction: %s", err)
+	}
+	defer tx.Rollback(context.Background())
+
+	p.logger.Debugf("Inserting %v vocab entries into DB", len(entries))
+	_, err = tx.Exec(context.Background(), createVocabEntryStaging)
+	if err != nil {
+		return 0, fmt.Errorf("creating vocab entry staging table in DB: %s", err)
+	}
+	stagingRows := make([][]interface{}, 0, len(entries))
+	for i, e := range entries {
+		stagingRows = append(stagingRows,
+			[]interface{}{i, e.Text, e.LangPair.Source, e.LangPair.Target, e.Transcription, e.Source})
+	}
+	_, err = tx.CopyFrom(context.Background(), pgx.Identifier{"vocab_entry_staging"},
+		[]string{"idx", "text", "source_lang", "target_lang", "transcription", "source"},
+		pgx.CopyFromRows(stagingRows))
+	if err != nil {
+		return 0, fmt.Errorf("copying vocab entries into DB staging table: %s", err)
+	}
+
+	byKey := make(map[string]*domain.VocabEntry, len(entries))
+	for _, e := range entries {
+		key := e.LangPair.String() + ":" + e.Text
+		if _, ok := byKey[key]; !ok {
+			byKey[key] = e
+		}
+	}
+	rows, err := tx.Query(context.Background(), addVocabEntriesFromStaging)
+	if err != nil {
+		return 0, fmt.Errorf("inserting vocab entries into DB: %s", err)
+	}
+	var inserted []*domain.VocabEntry
+	for rows.Next() {
+		var id int
+		var text string
+		var langPair domain.LangPair
+		err = rows.Scan(&id, &text, &langPair.Source, &langPair.Target)
+		if err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scanning inserted vocab entry: %s", err)
+		}
+		entry := byKey[langPair.String()+":"+text]
+		entry.ID = id
+		inserted = append(inserted, entry)
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return 0, fmt.Errorf("inserting vocab entries into DB: %s", rows.Err())
+	}
+	err = copyTranslations(tx, inserted)
+	if err != nil {
+		return 0, err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return 0, fmt.Errorf("commiting transaction: %s", err)
+	}
+	p.logger.Debugf("%v vocab entries inserted into DB", len(inserted))
+	return len(inserted), nil
+}
+
+// copyTranslations copies the translations of the inserted entries with their synonyms, meanings and examples to DB.
+// The IDs of the translations are needed only for their details so they are read back only if there are any.
+func copyTranslations(tx pgx.Tx, entries []*domain.VocabEntry) error {
+	var translationRows [][]interface{}
+	var entryIDs []int
+	hasDetails := false
+	for _, e := range entries {
+		entryIDs = append(entryIDs, e.ID)
+		for _, t := range e.Translations {
+			translationRows = append(translationRows,
+				[]interface{}{e.ID, t.Text, t.Class, t.Position, t.Gender, t.Aspect})
+			hasDetails = hasDetails || len(t.Synonyms) != 0 || len(t.Meanings) != 0 || len(t.Examples) != 0
+		}
+	}
+	_, err := tx.CopyFrom(context.Background(), pgx.Identifier{"translation"},
+		[]string{"vocab_entry_id", "text", "class", "position", "gender", "aspect"},
+		pgx.CopyFromRows(translationRows))
+	if err != nil {
+		return fmt.Errorf("copying translations into DB: %s", err)
+	}
+	if !hasDetails {
+		return nil
+	}
+
+	type translationKey struct{ entryID, position int }
+	ids := make(map[translationKey]int)
+	rows, err := tx.Query(context.Background(), getTranslationIDsByEntryIDs, entryIDs)
+	if err != nil {
+		return fmt.Errorf("getting translation IDs from DB: %s", err)
+	}
+	for rows.Next() {
+		var id int
+		var key translationKey
+		err = rows.Scan(&id, &key.entryID, &key.position)
+		if err != nil {
+			rows.Close()
+			return fmt.Errorf("scanning translation ID: %s", err)
+		}
+		ids[key] = id
+	}
+	rows.Close()
+	if rows.Err() != nil {
+		return fmt.Errorf("getting translation IDs from DB: %s", rows.Err())
+	}
+	var synonymRows, meaningRows, exampleRows [][]interface{}
+	for _, e := range entries {
+		for _, t := range e.Translations {
+			t.ID = ids[translationKey{e.ID, t.Position}]
+			for i, s := range t.Synonyms {
+				synonymRows = append(synonymRows, []interface{}{t.ID, s, i})
+			}
+			for i, m := range t.Meanings {
+				meaningRows = append(meaningRows, []interface{}{t.ID, m, i})
+			}
+			for i, ex := range t.Examples {
+				exampleRows = append(exampleRows, []interface{}{t.ID, ex.Text, ex.Translation, i})
+			}
+		}
+	}
+	details := []struct {
+		table   string
+		columns []string
+		rows    [][]interface{}
+	}{
+		{table: "translation_synonym", columns: []string{"translation_id", "text", "position"}, rows: synonymRows},
+		{table: "translation_meaning", columns: []string{"translation_id", "text", "position"}, rows: meaningRows},
+		{
+			table:   "translation_example",
+			columns: []string{"translation_id", "text", "translation", "position"},
+			rows:    exampleRows,
+		},
+	}
+	for _, d := range details {
+		_, err = tx.CopyFrom(context.Background(), pgx.Identifier{d.table}, d.columns, pgx.CopyFromRows(d.rows))
+		if err != nil {
+			return fmt.Errorf("copying %s into DB: %s", d.table, err)
+		}
+	}
+	return nil
+}
+
+// addTranslations inserts the translations of the entry with their synonyms, meanings and examples.
+func addTranslations(tx pgx.Tx, entry *domain.VocabEntry) error {
+	for _, t := range entry.Translations {
+		row := tx.QueryRow(context.Background(), addTranslation,
+			entry.ID, t.Text, t.Class, t.Position, t.Gender, t.Aspect)
+		err := row.Scan(&t.ID)
+		if err != nil {
+			return fmt.Errorf("inserting translation into DB: %s", err)
+		}
+		for i, s := range t.Synonyms {
+			_, err = tx.Exec(context.Background(), addTranslationSynonym, t.ID, s, i)
+			if err != nil {
+				return fmt.Errorf("inserting translation synonym into DB: %s", err)
+			}
+		}
+		for i, m := range t.Meanings {
+			_, err = tx.Exec(context.Background(), addTranslationMeaning, t.ID, m, i)
+			if err != nil {
+				return fmt.Errorf("inserting translation meaning into DB: %s", err)
+			}
+		}
+		for i, e := range t.Examples {
+			_, err = tx.Exec(context.Background(), addTranslationExample, t.ID, e.Text, e.Translation, i)
+			if err != nil {
+				return fmt.Errorf("inserting translation example into DB: %s", err)
+			}
+		}
+	}
+	return nil
+}
+
+// GetVocabEntryByText returns the dictionary vocab entry found by the given text in the given language pair.
+// The custom entries of the users aren't returned.
 // Returns nil and no error if vocab entry was not found.
-func (p *Postgres) GetVocabEntryByText(text string) (*domain.VocabEntry, error) {
-	logger := p.logger.WithField("text", text)
+func (p *Postgres) GetVocabEntryByText(text string, langPair domain.LangPair) (*domain.VocabEntry, error) {
+	logger := p.logger.WithFields(map[string]interface{}{
+		"text":     text,
+		"langPair": langPair,
+	})
 	logger.Debug("Getting vocab entry by text from DB")
-	row := p.pool.QueryRow(context.Background(), getVocabEntryByText, text)
+	row := p.pool.QueryRow(context.Background(), getVocabEntryByText, text, langPair.Source, langPair.Target)
+	return p.getVocabEntry(logger, row)
+}
+
+// GetCustomVocabEntryByText returns the user's custom vocab entry found by the given text in the given language pair.
+// Returns nil and no error if vocab entry was not found.
+func (p *Postgres) GetCustomVocabEntryByText(
+	text string,
+	langPair domain.LangPair,
+	userID int,
+) (*domain.VocabEntry, error) {
+	logger := p.logger.WithFields(map[string]interface{}{
+		"text":     text,
+		"langPair": langPair,
+		"userID":   userID,
+	})
+	logger.Debug("Getting custom vocab entry by text from DB")
+	row := p.pool.QueryRow(context.Background(), getCustomVocabEntryByText,
+		text, langPair.Source, langPair.Target, userID)
 	return p.getVocabEntry(logger, row)
 }
 
@@ -154,9 +612,37 @@ func (p *Postgres) GetVocabEntryByID(id int) (*domain.VocabEntry, error) {
 	return p.getVocabEntry(logger, row)
 }
 
+// AddVocabEntryAlias links the word form to the entry, e.g. "went" to the entry of "go".
+// Does nothing if the form is already linked to the entry.
+func (p *Postgres) AddVocabEntryAlias(alias string, entryID int) error {
+	logger := p.logger.WithFields(map[string]interface{}{
+		"alias":   alias,
+		"entryID": entryID,
+	})
+	logger.Debug("Adding vocab entry alias to DB")
+	_, err := p.pool.Exec(context.Background(), addVocabEntryAlias, alias, entryID)
+	if err != nil {
+		return fmt.Errorf("inserting vocab entry alias into DB: %s", err)
+	}
+	return nil
+}
+
+// GetVocabEntryByAlias returns the vocab entry the given word form is linked to in the given language pair.
+// Returns nil and no error if vocab entry was not found.
+func (p *Postgres) GetVocabEntryByAlias(alias string, langPair domain.LangPair) (*domain.VocabEntry, error) {
+	logger := p.logger.WithFields(map[string]interface{}{
+		"alias":    alias,
+		"langPair": langPair,
+	})
+	logger.Debug("Getting vocab entry by alias from DB")
+	row := p.pool.QueryRow(context.Background(), getVocabEntryByAlias, alias, langPair.Source, langPair.Target)
+	return p.getVocabEntry(logger, row)
+}
+
 func (p *Postgres) getVocabEntry(logger log.Logger, row pgx.Row) (*domain.VocabEntry, error) {
 	entry := new(domain.VocabEntry)
-	err := row.Scan(&entry.ID, &entry.Text, &entry.Transcription)
+	err := row.Scan(&entry.ID, &entry.Text, &entry.LangPair.Source, &entry.LangPair.Target, &entry.Transcription,
+		&entry.Source, &entry.UserID)
 	if err != nil {
 		if err == pgx.ErrNoRows {
 			logger.Debug("Vocab entry not found in DB")
@@ -171,7 +657,7 @@ func (p *Postgres) getVocabEntry(logger log.Logger, row pgx.Row) (*domain.VocabE
 	for rows.Next() {
 		t := new(domain.Translation)
 		entry.Translations = append(entry.Translations, t)
-		err := rows.Scan(&t.ID, &t.Text, &t.Class, &t.Position)
+		err := rows.Scan(&t.ID, &t.Text, &t.Class, &t.Position, &t.Gender, &t.Aspect)
 		if err != nil {
 			return nil, fmt.Errorf("scanning translation row: %s", err)
 		}
@@ -179,33 +665,94 @@ func (p *Postgres) getVocabEntry(logger log.Logger, row pgx.Row) (*domain.VocabE
 			entry.MainTranslation = t.Text
 		}
 	}
+	err = p.getTranslationDetails(entry)
+	if err != nil {
+		return nil, err
+	}
 	logger = logger.WithField("vocabEntry", entry)
 	logger.Debug("Entry found in DB")
 	return entry, nil
 }
 
-// AddEntryToUserVocab links entry with the given ID to the user's vocab.
-func (p *Postgres) AddEntryToUserVocab(entryID, userID int) error {
+// getTranslationDetails loads the synonyms, meanings and examples of the entry translations.
+func (p *Postgres) getTranslationDetails(entry *domain.VocabEntry) error {
+	translations := make(map[int]*domain.Translation, len(entry.Translations))
+	for _, t := range entry.Translations {
+		translations[t.ID] = t
+	}
+	rows, err := p.pool.Query(context.Background(), getSynonymsByEntryID, entry.ID)
+	if err != nil {
+		return fmt.Errorf("getting translation synonyms by entry ID: %s", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var translationID int
+		var text string
+		err = rows.Scan(&translationID, &text)
+		if err != nil {
+			return fmt.Errorf("scanning translation synonym row: %s", err)
+		}
+		if t, ok := translations[translationID]; ok {
+			t.Synonyms = append(t.Synonyms, text)
+		}
+	}
+	rows, err = p.pool.Query(context.Background(), getMeaningsByEntryID, entry.ID)
+	if err != nil {
+		return fmt.Errorf("getting translation meanings by entry ID: %s", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var translationID int
+		var text string
+		err = rows.Scan(&translationID, &text)
+		if err != nil {
+			return fmt.Errorf("scanning translation meaning row: %s", err)
+		}
+		if t, ok := translations[translationID]; ok {
+			t.Meanings = append(t.Meanings, text)
+		}
+	}
+	rows, err = p.pool.Query(context.Background(), getExamplesByEntryID, entry.ID)
+	if err != nil {
+		return fmt.Errorf("getting translation examples by entry ID: %s", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var translationID int
+		e := new(domain.Example)
+		err = rows.Scan(&translationID, &e.Text, &e.Translation)
+		if err != nil {
+			return fmt.Errorf("scanning translation example row: %s", err)
+		}
+		if t, ok := translations[translationID]; ok {
+			t.Examples = append(t.Examples, e)
+		}
+	}
+	return nil
+}
+
+// AddEntryToVocab links entry with the given ID to the vocab.
+func (p *Postgres) AddEntryToVocab(entryID, vocabID int) error {
 	logger := p.logger.WithFields(map[string]interface{}{
 		"entryID": entryID,
-		"userID":  userID,
+		"vocabID": vocabID,
 	})
-	logger.Debug("Adding the entry to the user's vocab in DB")
-	_, err := p.pool.Exec(context.Background(), addEntryToUserVocab, entryID, userID)
+	logger.Debug("Adding the entry to the vocab in DB")
+	_, err := p.pool.Exec(context.Background(), addEntryToVocab, entryID, vocabID)
 	if err != nil {
-		return fmt.Errorf("adding entry to user's vocab in DB: %s", err)
+		return fmt.Errorf("adding entry to vocab in DB: %s", err)
 	}
 	return nil
 }
 
-// CheckEntryInUserVocab returns if the entry is linked to the user's vocab.
-func (p *Postgres) CheckEntryInUserVocab(entryID, userID int) (bool, error) {
+// CheckEntryInVocab returns if the entry is linked to the vocab.
+func (p *Postgres) CheckEntryInVocab(entryID, vocabID int) (bool, error) {
 	logger := p.logger.WithFields(map[string]interface{}{
 		"entryID": entryID,
-		"userID":  userID,
+		"vocabID": vocabID,
 	})
-	logger.Debug("Checking if the entry is added to the user's vocab in DB")
-	row := p.pool.QueryRow(context.Background(), checkEntryInUserVocab, entryID, userID)
+	logger.Debug("Checking if the entry is added to the vocab in DB")
+	row := p.pool.QueryRow(context.Background(), checkEntryInVocab, entryID, vocabID)
 	id := new(int)
 	err := row.Scan(id)
 	if err != nil {
@@ -217,13 +764,13 @@ func (p *Postgres) CheckEntryInUserVocab(entryID, userID int) (bool, error) {
 	return true, nil
 }
 
-// GetEntryIDsByUserID returns IDs of all entries linked to the user's vocab.
-func (p *Postgres) GetEntryIDsByUserID(userID int) ([]int, error) {
-	contextLog := p.logger.WithField("userID", userID)
-	contextLog.Debug("Getting entry IDs from the user's vocab from DB")
-	rows, err := p.pool.Query(context.Background(), getEntryIDsByUserID, userID)
+// GetEntryIDsByVocabID returns IDs of all entries linked to the vocab.
+func (p *Postgres) GetEntryIDsByVocabID(vocabID int) ([]int, error) {
+	contextLog := p.logger.WithField("vocabID", vocabID)
+	contextLog.Debug("Getting entry IDs from the vocab from DB")
+	rows, err := p.pool.Query(context.Background(), getEntryIDsByVocabID, vocabID)
 	if err != nil {
-		return nil, fmt.Errorf("getting entry IDs from the user's vocab from DB: %s", err)
+		return nil, fmt.Errorf("getting entry IDs from the vocab from DB: %s", err)
 	}
 	var ids []int
 	for rows.Next() {
@@ -237,41 +784,575 @@ func (p *Postgres) GetEntryIDsByUserID(userID int) ([]int, error) {
 	return ids, nil
 }
 
-// GetEntriesByUserID returns all entries linked to the user's vocab.
-// Returned entries have only main translation.
-func (p *Postgres) GetEntriesByUserID(userID int) ([]*domain.VocabEntry, error) {
-	contextLog := p.logger.WithField("userID", userID)
-	contextLog.Debug("Getting entries from the user's vocab from DB")
-	rows, err := p.pool.Query(context.Background(), getEntriesByUserID, userID)
+// GetEntriesByVocabID returns all entries linked to the vocab.
+// Returned entries have only main translation which is also the only element of translations.
+// Mastery of the returned entries is filled.
+func (p *Postgres) GetEntriesByVocabID(vocabID int) ([]*domain.VocabEntry, error) {
+	contextLog := p.logger.WithField("vocabID", vocabID)
+	contextLog.Debug("Getting entries from the vocab from DB")
+	rows, err := p.pool.Query(context.Background(), getEntriesByVocabID, vocabID)
+	if err != nil {
+		return nil, fmt.Errorf("getting entries from the vocab from DB: %s", err)
+	}
+	return scanEntriesWithMastery(rows)
+}
+
+// GetExportEntriesByVocabID returns all entries linked to the vocab with all their translations,
+// the dates they were added and the learning progress. Entries are ordered by the date they were added.
+func (p *Postgres) GetExportEntriesByVocabID(vocabID int) ([]*domain.ExportEntry, error) {
+	contextLog := p.logger.WithField("vocabID", vocabID)
+	contextLog.Debug("Getting entries to export from the vocab from DB")
+	rows, err := p.pool.Query(context.Background(), getExportEntriesByVocabID, vocabID)
+	if err != nil {
+		return nil, fmt.Errorf("getting entries to export from the vocab from DB: %s", err)
+	}
+	defer rows.Close()
+	var entries []*domain.ExportEntry
+	entriesByID := make(map[int]*domain.VocabEntry)
+	for rows.Next() {
+		e := &domain.ExportEntry{Entry: new(domain.VocabEntry)}
+		var addedAt *time.Time
+		err := rows.Scan(&e.Entry.ID, &e.Entry.Text, &e.Entry.LangPair.Source, &e.Entry.LangPair.Target,
+			&e.Entry.Transcription, &addedAt, &e.Progress.CorrectStreak, &e.Progress.State)
+		if err != nil {
+			return nil, fmt.Errorf("scanning entry to export row: %s", err)
+		}
+		if addedAt != nil {
+			e.AddedAt = *addedAt
+		}
+		e.Progress.EntryID = e.Entry.ID
+		e.Entry.Mastery = domain.MasteryLevelOf(e.Progress.CorrectStreak)
+		entries = append(entries, e)
+		entriesByID[e.Entry.ID] = e.Entry
+	}
+	rows, err = p.pool.Query(context.Background(), getTranslationsByVocabID, vocabID)
+	if err != nil {
+		return nil, fmt.Errorf("getting translations of the vocab entries from DB: %s", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		t := new(domain.Translation)
+		var entryID int
+		err := rows.Scan(&entryID, &t.ID, &t.Text, &t.Class, &t.Position)
+		if err != nil {
+			return nil, fmt.Errorf("scanning translation row: %s", err)
+		}
+		entry, ok := entriesByID[entryID]
+		if !ok {
+			continue
+		}
+		entry.Translations = append(entry.Translations, t)
+		if t.Position == 0 {
+			entry.MainTranslation = t.Text
+		}
+	}
+	return entries, nil
+}
+
+// GetEntriesPageByVocabID returns at most limit entries linked to the vocab skipping the first offset entries.
+// Entries are ordered by mastery level and then alphabetically.
+// Returned entries have only main translation which is also the only element of translations.
+// Mastery of the returned entries is filled.
+func (p *Postgres) GetEntriesPageByVocabID(vocabID, limit, offset int) ([]*domain.VocabEntry, error) {
+	contextLog := p.logger.WithFields(map[string]interface{}{
+		"vocabID": vocabID,
+		"limit":   limit,
+		"offset":  offset,
+	})
+	contextLog.Debug("Getting page of entries from the vocab from DB")
+	rows, err := p.pool.Query(context.Background(), getEntriesPageByVocabID, vocabID, limit, offset,
+		domain.LearnedCorrectStreak, domain.FamiliarCorrectStreak, domain.LearningCorrectStreak)
+	if err != nil {
+		return nil, fmt.Errorf("getting page of entries from the vocab from DB: %s", err)
+	}
+	return scanEntriesWithMastery(rows)
+}
+
+// CountEntriesByVocabID returns the number of entries linked to the vocab.
+func (p *Postgres) CountEntriesByVocabID(vocabID int) (int, error) {
+	contextLog := p.logger.WithField("vocabID", vocabID)
+	contextLog.Debug("Counting entries in the vocab in DB")
+	var count int
+	err := p.pool.QueryRow(context.Background(), countEntriesByVocabID, vocabID).Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("counting entries in the vocab in DB: %s", err)
+	}
+	return count, nil
+}
+
+// FindEntriesByVocabID returns at most limit entries linked to the vocab matching the query skipping the first offset entries.
+// The entry matches if its text starts with the query, any of its translations contains the query
+// or the query is the class of any of its translations. Case is ignored.
+// Entries are ordered alphabetically.
+// Returned entries have only main translation which is also the only element of translations.
+// Mastery of the returned entries is filled.
+func (p *Postgres) FindEntriesByVocabID(vocabID int, query string, limit, offset int) ([]*domain.VocabEntry, error) {
+	contextLog := p.logger.WithFields(map[string]interface{}{
+		"vocabID": vocabID,
+		"query":   query,
+		"limit":   limit,
+		"offset":  offset,
+	})
+	contextLog.Debug("Finding entries in the vocab in DB")
+	prefix, substring, class := searchPatterns(query)
+	rows, err := p.pool.Query(context.Background(), findEntriesByVocabID,
+		vocabID, prefix, substring, class, limit, offset)
 	if err != nil {
-		return nil, fmt.Errorf("getting entries from the user's vocab from DB: %s", err)
+		return nil, fmt.Errorf("finding entries in the vocab in DB: %s", err)
 	}
+	return scanEntriesWithMastery(rows)
+}
+
+// CountFoundEntriesByVocabID returns the number of entries linked to the vocab matching the query.
+// See FindEntriesByVocabID for the matching rules.
+func (p *Postgres) CountFoundEntriesByVocabID(vocabID int, query string) (int, error) {
+	contextLog := p.logger.WithFields(map[string]interface{}{
+		"vocabID": vocabID,
+		"query":   query,
+	})
+	contextLog.Debug("Counting found entries in the vocab in DB")
+	prefix, substring, class := searchPatterns(query)
+	var count int
+	err := p.pool.QueryRow(context.Background(), countFoundEntriesByVocabID, vocabID, prefix, substring, class).
+		Scan(&count)
+	if err != nil {
+		return 0, fmt.Errorf("counting found entries in the vocab in DB: %s", err)
+	}
+	return count, nil
+}
+
+// searchPatterns returns LIKE patterns for the text prefix and the translation substring
+// with escaped wildcards of the query, and the lowercase query to compare classes with.
+func searchPatterns(query string) (prefix, substring, class string) {
+	escaped := likeEscaper.Replace(query)
+	return escaped + "%", "%" + escaped + "%", strings.ToLower(query)
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+func scanEntriesWithMastery(rows pgx.Rows) ([]*domain.VocabEntry, error) {
+	defer rows.Close()
 	var entries []*domain.VocabEntry
 	for rows.Next() {
 		e := new(domain.VocabEntry)
+		t := new(domain.Translation)
+		var correctStreak int
 		entries = append(entries, e)
-		err := rows.Scan(&e.ID, &e.Text, &e.Transcription, &e.MainTranslation)
+		err := rows.Scan(&e.ID, &e.Text, &e.LangPair.Source, &e.LangPair.Target, &e.Transcription,
+			&t.ID, &t.Text, &t.Class, &correctStreak)
 		if err != nil {
 			return nil, fmt.Errorf("scanning entry row: %s", err)
 		}
+		e.Mastery = domain.MasteryLevelOf(correctStreak)
+		e.MainTranslation = t.Text
+		e.Translations = []*domain.Translation{t}
 	}
 	return entries, nil
 }
 
-// RemoveEntryFromUserVocab removes link of the entry with given ID to the user's vocab.
-func (p *Postgres) RemoveEntryFromUserVocab(entryID, userID int) error {
+// RemoveEntryFromVocab removes link of the entry with given ID to the vocab.
+func (p *Postgres) RemoveEntryFromVocab(entryID, vocabID int) error {
 	logger := p.logger.WithFields(map[string]interface{}{
 		"entryID": entryID,
-		"userID":  userID,
+		"vocabID": vocabID,
+	})
+	logger.Debug("Removing the entry from the vocab in DB")
+	_, err := p.pool.Exec(context.Background(), removeEntryFromVocab, entryID, vocabID)
+	if err != nil {
+		return fmt.Errorf("removing entry from vocab in DB: %s", err)
+	}
+	return nil
+}
+
+// GetEntryIDsToReview returns IDs of the entries linked to the user's vocab
+// ordered by the review due date in the given direction. The most overdue entry goes first.
+// Entries which have never been reviewed are considered to be due now.
+// Learned entries are returned only when they are due for refresh.
+func (p *Postgres) GetEntryIDsToReview(userID int, direction domain.Direction) ([]int, error) {
+	logger := p.logger.WithFields(map[string]interface{}{
+		"userID":    userID,
+		"direction": direction,
+	})
+	logger.Debug("Getting entry IDs to review from the user's vocab from DB")
+	rows, err := p.pool.Query(context.Background(), getEntryIDsToReview, userID, direction, domain.EntryStateActive)
+	if err != nil {
+		return nil, fmt.Errorf("getting entry IDs to review from the user's vocab from DB: %s", err)
+	}
+	var ids []int
+	for rows.Next() {
+		var id int
+		err := rows.Scan(&id)
+		if err != nil {
+			return nil, fmt.Errorf("scanning row with id: %s", err)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// CountEntriesToReview returns the number of entries linked to the user's vocab
+// which are due for review in the given direction at the given time.
+// Entries which have never been reviewed are considered to be due.
+func (p *Postgres) CountEntriesToReview(userID int, direction domain.Direction, at time.Time) (int, error) {
+	logger := p.logger.WithFields(map[string]interface{}{
+		"userID":    userID,
+		"direction": direction,
+		"at":        at,
+	})
+	logger.Debug("Counting entries to review in the user's vocab in DB")
+	var qnt int
+	err := p.pool.QueryRow(context.Background(), countEntriesToReview, userID, direction, at).Scan(&qnt)
+	if err != nil {
+		return 0, fmt.Errorf("counting entries to review in the user's vocab in DB: %s", err)
+	}
+	return qnt, nil
+}
+
+// GetSchedule returns the review schedule of the entry in the user's vocab in the given direction.
+// Returns the initial schedule if the entry has never been reviewed in the direction.
+// Returns nil and no error if the entry is not linked to the user's vocab.
+func (p *Postgres) GetSchedule(entryID, userID int, direction domain.Direction) (*domain.Schedule, error) {
+	logger := p.logger.WithFields(map[string]interface{}{
+		"entryID":   entryID,
+		"userID":    userID,
+		"direction": direction,
+	})
+	logger.Debug("Getting schedule of the entry in the user's vocab from DB")
+	row := p.pool.QueryRow(context.Background(), getSchedule, entryID, userID, direction)
+	schedule := &domain.Schedule{Direction: direction}
+	err := row.Scan(&schedule.EntryID, &schedule.EaseFactor, &schedule.Interval, &schedule.Repetitions, &schedule.DueAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			logger.Debug("Schedule not found in DB")
+			return nil, nil
+		}
+		return nil, fmt.Errorf("getting schedule from DB: %s", err)
+	}
+	logger.WithField("schedule", schedule).Debug("Schedule found in DB")
+	return schedule, nil
+}
+
+// SaveReview inserts the given review of the entry from the user's vocab to DB, saves the review schedule
+// of the entry and advances the user's progress in learning it in one transaction, so the review log,
+// the schedule and the progress always agree. Returns the advanced progress or nil
+// if the entry is not linked to the user's vocab anymore.
+func (p *Postgres) SaveReview(review *domain.Review, schedule *domain.Schedule, userID int) (*domain.EntryProgress, error) {
+	tx, err := p.pool.Begin(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("getting transaction: %s", err)
+	}
+	defer tx.Rollback(context.Background())
+
+	logger := p.logger.WithFields(map[string]interface{}{
+		"review":   review,
+		"schedule": schedule,
+		"userID":   userID,
+	})
+	logger.Debug("Inserting review and updating schedule and progress of the entry in the user's vocab in DB")
+	_, err = tx.Exec(context.Background(), addReview,
+		review.EntryID, review.Direction, review.Grade, review.ReviewedAt, userID)
+	if err != nil {
+		return nil, fmt.Errorf("inserting review into DB: %s", err)
+	}
+	_, err = tx.Exec(context.Background(), updateSchedule, schedule.EntryID, schedule.Direction,
+		schedule.EaseFactor, schedule.Interval, schedule.Repetitions, schedule.DueAt, userID)
+	if err != nil {
+		return nil, fmt.Errorf("updating schedule in DB: %s", err)
+	}
+	progress, err := advanceEntryProgress(tx, review, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("commiting transaction: %s", err)
+	}
+	return progress, nil
+}
+
+// advanceEntryProgress locks the user's progress in learning the reviewed entry, advances it according to
+// the grade of the review and saves it. Returns nil and no error if the entry is not linked to the user's vocab.
+func advanceEntryProgress(tx pgx.Tx, review *domain.Review, userID int) (*domain.EntryProgress, error) {
+	row := tx.QueryRow(context.Background(), getEntryProgress, review.EntryID, userID)
+	progress := new(domain.EntryProgress)
+	err := row.Scan(&progress.EntryID, &progress.CorrectStreak, &progress.State)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("getting entry progress from DB: %s", err)
+	}
+	progress.Advance(review.Grade)
+	_, err = tx.Exec(context.Background(), updateEntryProgress,
+		progress.EntryID, progress.CorrectStreak, progress.State, userID)
+	if err != nil {
+		return nil, fmt.Errorf("updating entry progress in DB: %s", err)
+	}
+	return progress, nil
+}
+
+// AddQuizSession inserts the given quiz session of the user to DB and returns it with inserted ID.
+// Finishes the user's active session if there is one, so the user has at most one active session.
+func (p *Postgres) AddQuizSession(session *domain.QuizSession, userID int) (*domain.QuizSession, error) {
+	tx, err := p.pool.Begin(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("getting transaction: %s", err)
+	}
+	defer tx.Rollback(context.Background())
+
+	logger := p.logger.WithFields(map[string]interface{}{
+		"quizSession": session,
+		"userID":      userID,
+	})
+	logger.Debug("Inserting quiz session into DB")
+	_, err = tx.Exec(context.Background(), finishActiveQuizSessions, userID, session.StartedAt)
+	if err != nil {
+		return nil, fmt.Errorf("finishing active quiz sessions in DB: %s", err)
+	}
+	row := tx.QueryRow(context.Background(), addQuizSession, session.Direction, session.StartedAt, userID)
+	err = row.Scan(&session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("inserting quiz session into DB: %s", err)
+	}
+	for i, e := range session.Entries {
+		_, err = tx.Exec(context.Background(), addQuizSessionEntry, session.ID, e.EntryID, i)
+		if err != nil {
+			return nil, fmt.Errorf("inserting quiz session entry into DB: %s", err)
+		}
+	}
+
+	err = tx.Commit(context.Background())
+	if err != nil {
+		return nil, fmt.Errorf("commiting transaction: %s", err)
+	}
+	logger.Debug("Quiz session inserted into DB")
+	return session, nil
+}
+
+// GetActiveQuizSession returns the user's quiz session which is not finished yet.
+// Returns nil and no error if there is no active session.
+func (p *Postgres) GetActiveQuizSession(userID int) (*domain.QuizSession, error) {
+	logger := p.logger.WithField("userID", userID)
+	logger.Debug("Getting active quiz session from DB")
+	row := p.pool.QueryRow(context.Background(), getActiveQuizSession, userID)
+	session := new(domain.QuizSession)
+	err := row.Scan(&session.ID, &session.Direction, &session.StartedAt)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			logger.Debug("Active quiz session not found in DB")
+			return nil, nil
+		}
+		return nil, fmt.Errorf("getting active quiz session from DB: %s", err)
+	}
+	rows, err := p.pool.Query(context.Background(), getQuizSessionEntries, session.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting quiz session entries from DB: %s", err)
+	}
+	for rows.Next() {
+		e := new(domain.QuizSessionEntry)
+		session.Entries = append(session.Entries, e)
+		err := rows.Scan(&e.EntryID, &e.Grade)
+		if err != nil {
+			return nil, fmt.Errorf("scanning quiz session entry row: %s", err)
+		}
+	}
+	logger.WithField("quizSession", session).Debug("Active quiz session found in DB")
+	return session, nil
+}
+
+// GradeQuizSessionEntry saves the grade of the entry answered in the quiz session.
+// Returns false if the entry is already graded, so only one of the concurrent answers is accepted.
+func (p *Postgres) GradeQuizSessionEntry(sessionID, entryID int, grade domain.Grade) (bool, error) {
+	logger := p.logger.WithFields(map[string]interface{}{
+		"sessionID": sessionID,
+		"entryID":   entryID,
+		"grade":     grade,
+	})
+	logger.Debug("Grading quiz session entry in DB")
+	tag, err := p.pool.Exec(context.Background(), gradeQuizSessionEntry, sessionID, entryID, grade)
+	if err != nil {
+		return false, fmt.Errorf("grading quiz session entry in DB: %s", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// FinishQuizSession marks the quiz session as finished at the given time.
+func (p *Postgres) FinishQuizSession(sessionID int, finishedAt time.Time) error {
+	logger := p.logger.WithFields(map[string]interface{}{
+		"sessionID":  sessionID,
+		"finishedAt": finishedAt,
+	})
+	logger.Debug("Finishing quiz session in DB")
+	_, err := p.pool.Exec(context.Background(), finishQuizSession, sessionID, finishedAt)
+	if err != nil {
+		return fmt.Errorf("finishing quiz session in DB: %s", err)
+	}
+	return nil
+}
+
+// CountEntries returns the number of entries linked to the user's vocab.
+func (p *Postgres) CountEntries(userID int) (int, error) {
+	logger := p.logger.WithField("userID", userID)
+	logger.Debug("Counting entries in the user's vocab in DB")
+	var qnt int
+	err := p.pool.QueryRow(context.Background(), countEntries, userID).Scan(&qnt)
+	if err != nil {
+		return 0, fmt.Errorf("counting entries in the user's vocab in DB: %s", err)
+	}
+	return qnt, nil
+}
+
+// CountEntriesAddedSince returns the number of entries linked to the user's vocab since the given time.
+// Entries added before the time of adding was recorded are not counted.
+func (p *Postgres) CountEntriesAddedSince(userID int, since time.Time) (int, error) {
+	logger := p.logger.WithFields(map[string]interface{}{
+		"userID": userID,
+		"since":  since,
+	})
+	logger.Debug("Counting entries added to the user's vocab in DB")
+	var qnt int
+	err := p.pool.QueryRow(context.Background(), countEntriesAddedSince, userID, since).Scan(&qnt)
+	if err != nil {
+		return 0, fmt.Errorf("counting entries added to the user's vocab in DB: %s", err)
+	}
+	return qnt, nil
+}
+
+// CountReviewsSince returns the number of the user's reviews since the given time
+// and the number of them which were not graded as "again".
+func (p *Postgres) CountReviewsSince(userID int, since time.Time) (total, recalled int, err error) {
+	logger := p.logger.WithFields(map[string]interface{}{
+		"userID": userID,
+		"since":  since,
 	})
-	logger.Debug("Removing the entry from the user's vocab in DB")
-	_, err := p.pool.Exec(context.Background(), removeEntryFromUserVocab, entryID, userID)
+	logger.Debug("Counting the user's reviews in DB")
+	err = p.pool.QueryRow(context.Background(), countReviewsSince, userID, since, domain.GradeAgain).
+		Scan(&total, &recalled)
 	if err != nil {
-		return fmt.Errorf("removing entry from user's vocab in DB: %s", err)
+		return 0, 0, fmt.Errorf("counting the user's reviews in DB: %s", err)
+	}
+	return total, recalled, nil
+}
+
+// GetDailyReviewCounts returns numbers of the user's reviews made since the given time grouped by dates
+// in the given timezone. Dates without reviews are omitted. The latest date goes first.
+func (p *Postgres) GetDailyReviewCounts(userID int, timezone string, since time.Time) ([]*domain.DailyReviews, error) {
+	logger := p.logger.WithFields(map[string]interface{}{
+		"userID":   userID,
+		"timezone": timezone,
+		"since":    since,
+	})
+	logger.Debug("Getting the user's daily review counts from DB")
+	rows, err := p.pool.Query(context.Background(), getDailyReviewCounts, userID, timezone, since)
+	if err != nil {
+		return nil, fmt.Errorf("getting the user's daily review counts from DB: %s", err)
+	}
+	var counts []*domain.DailyReviews
+	for rows.Next() {
+		c := new(domain.DailyReviews)
+		err := rows.Scan(&c.Date, &c.Qnt)
+		if err != nil {
+			return nil, fmt.Errorf("scanning row with daily review count: %s", err)
+		}
+		counts = append(counts, c)
+	}
+	return counts, nil
+}
+
+// GetUserSettings returns the user's settings.
+// Returns nil and no error if the user has never changed settings.
+func (p *Postgres) GetUserSettings(userID int) (*domain.UserSettings, error) {
+	logger := p.logger.WithField("userID", userID)
+	logger.Debug("Getting user settings from DB")
+	row := p.pool.QueryRow(context.Background(), getUserSettings, userID)
+	settings, err := scanUserSettings(row)
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			logger.Debug("User settings not found in DB")
+			return nil, nil
+		}
+		return nil, fmt.Errorf("getting user settings from DB: %s", err)
+	}
+	logger.WithField("userSettings", settings).Debug("User settings found in DB")
+	return settings, nil
+}
+
+// SaveUserSettings inserts or updates the given user's settings.
+// Date of the last reminder is not changed, use MarkReminded for it.
+func (p *Postgres) SaveUserSettings(settings *domain.UserSettings) error {
+	logger := p.logger.WithField("userSettings", settings)
+	logger.Debug("Saving user settings in DB")
+	_, err := p.pool.Exec(context.Background(), saveUserSettings, settings.UserID, settings.ChatID,
+		settings.RemindAt, settings.Timezone, settings.RemindersEnabled, settings.DailyGoal,
+		settings.LangPair.Source, settings.LangPair.Target)
+	if err != nil {
+		return fmt.Errorf("saving user settings in DB: %s", err)
 	}
 	return nil
 }
 
+// GetUserSettingsWithRemindersEnabled returns settings of all users who enabled reminders.
+func (p *Postgres) GetUserSettingsWithRemindersEnabled() ([]*domain.UserSettings, error) {
+	p.logger.Debug("Getting user settings with reminders enabled from DB")
+	rows, err := p.pool.Query(context.Background(), getUserSettingsWithRemindersEnabled)
+	if err != nil {
+		return nil, fmt.Errorf("getting user settings with reminders enabled from DB: %s", err)
+	}
+	var settings []*domain.UserSettings
+	for rows.Next() {
+		s, err := scanUserSettings(rows)
+		if err != nil {
+			return nil, fmt.Errorf("scanning user settings row: %s", err)
+		}
+		settings = append(settings, s)
+	}
+	return settings, nil
+}
+
+func scanUserSettings(row pgx.Row) (*domain.UserSettings, error) {
+	settings := new(domain.UserSettings)
+	err := row.Scan(&settings.UserID, &settings.ChatID, &settings.RemindAt, &settings.Timezone,
+		&settings.RemindersEnabled, &settings.LastRemindedOn, &settings.DailyGoal,
+		&settings.LangPair.Source, &settings.LangPair.Target)
+	if err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// MarkReminded saves the date of the reminder sent to the user if the user has not been reminded on this date yet.
+// Returns false if the user has already been reminded on this or later date,
+// so concurrent or repeated calls for the same date succeed only once.
+func (p *Postgres) MarkReminded(userID int, date time.Time) (bool, error) {
+	logger := p.logger.WithFields(map[string]interface{}{
+		"userID": userID,
+		"date":   date,
+	})
+	logger.Debug("Marking the user as reminded in DB")
+	tag, err := p.pool.Exec(context.Background(), markReminded, userID, date)
+	if err != nil {
+		return false, fmt.Errorf("marking user as reminded in DB: %s", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
+// MarkGoalReached saves the date the user reached the daily goal on if it has not been reached on this date yet.
+// Returns false if the goal has already been reached on this or later date,
+// so concurrent or repeated calls for the same date succeed only once.
+func (p *Postgres) MarkGoalReached(userID int, date time.Time) (bool, error) {
+	logger := p.logger.WithFields(map[string]interface{}{
+		"userID": userID,
+		"date":   date,
+	})
+	logger.Debug("Marking the user's daily goal as reached in DB")
+	tag, err := p.pool.Exec(context.Background(), markGoalReached, userID, date)
+	if err != nil {
+		return false, fmt.Errorf("marking daily goal as reached in DB: %s", err)
+	}
+	return tag.RowsAffected() == 1, nil
+}
+
 func (p *Postgres) ClosePool() {
 	p.pool.Close()
 }
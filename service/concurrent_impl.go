@@ -36,6 +36,30 @@ func (v *ConcurrentVocab) CreateVocab(userID int) (*domain.Vocab, error) {
 	return v.wrappedService.CreateVocab(userID)
 }
 
+// AddUserVocab calls AddUserVocab of wrapped vocabService with concurrent safe logic.
+// Makes one call of the wrapped method at a time per user.
+func (v *ConcurrentVocab) AddUserVocab(userID int, name string) (*domain.Vocab, error) {
+	v.vocabSync.startWork(userID)
+	defer v.vocabSync.endWork(userID)
+	return v.wrappedService.AddUserVocab(userID, name)
+}
+
+// GetUserVocabs calls GetUserVocabs of wrapped vocabService with concurrent safe logic.
+// Makes one call of the wrapped method at a time per user.
+func (v *ConcurrentVocab) GetUserVocabs(userID int) ([]*domain.Vocab, error) {
+	v.vocabSync.startWork(userID)
+	defer v.vocabSync.endWork(userID)
+	return v.wrappedService.GetUserVocabs(userID)
+}
+
+// UseUserVocab calls UseUserVocab of wrapped vocabService with concurrent safe logic.
+// Makes one call of the wrapped method at a time per user.
+func (v *ConcurrentVocab) UseUserVocab(userID int, name string) (*domain.Vocab, error) {
+	v.vocabSync.startWork(userID)
+	defer v.vocabSync.endWork(userID)
+	return v.wrappedService.UseUserVocab(userID, name)
+}
+
 // ClearUserVocab calls ClearUserVocab of wrapped vocabService with concurrent safe logic.
 // Makes one call of the wrapped method at a time per user.
 func (v *ConcurrentVocab) ClearUserVocab(userID int) error {
@@ -60,20 +84,36 @@ func (v *ConcurrentVocab) CheckEntryInUserVocab(entryID, userID int) (bool, erro
 	return v.wrappedService.CheckEntryInUserVocab(entryID, userID)
 }
 
-// GetRandomEntryFromUserVocab calls GetRandomEntryFromUserVocab of wrapped vocabService with concurrent safe logic.
+// GetEntriesByUserID calls RemoveEntryFromUserVocab of wrapped vocabService with concurrent safe logic.
 // Makes one call of the wrapped method at a time per user.
-func (v *ConcurrentVocab) GetRandomEntryFromUserVocab(userID int, previousEntryID int) (*domain.VocabEntry, error) {
+func (v *ConcurrentVocab) GetEntriesFromUserVocab(userID int) ([]*domain.VocabEntry, error) {
 	v.vocabSync.startWork(userID)
 	defer v.vocabSync.endWork(userID)
-	return v.wrappedService.GetRandomEntryFromUserVocab(userID, previousEntryID)
+	return v.wrappedService.GetEntriesFromUserVocab(userID)
 }
 
-// GetEntriesByUserID calls RemoveEntryFromUserVocab of wrapped vocabService with concurrent safe logic.
+// GetExportEntriesFromUserVocab calls GetExportEntriesFromUserVocab of wrapped vocabService
+// with concurrent safe logic. Makes one call of the wrapped method at a time per user.
+func (v *ConcurrentVocab) GetExportEntriesFromUserVocab(userID int) ([]*domain.ExportEntry, error) {
+	v.vocabSync.startWork(userID)
+	defer v.vocabSync.endWork(userID)
+	return v.wrappedService.GetExportEntriesFromUserVocab(userID)
+}
+
+// GetEntriesPageFromUserVocab calls GetEntriesPageFromUserVocab of wrapped vocabService with concurrent safe logic.
 // Makes one call of the wrapped method at a time per user.
-func (v *ConcurrentVocab) GetEntriesFromUserVocab(userID int) ([]*domain.VocabEntry, error) {
+func (v *ConcurrentVocab) GetEntriesPageFromUserVocab(userID, number, size int) (*domain.EntriesPage, error) {
 	v.vocabSync.startWork(userID)
 	defer v.vocabSync.endWork(userID)
-	return v.wrappedService.GetEntriesFromUserVocab(userID)
+	return v.wrappedService.GetEntriesPageFromUserVocab(userID, number, size)
+}
+
+// FindEntriesInUserVocab calls FindEntriesInUserVocab of wrapped vocabService with concurrent safe logic.
+// Makes one call of the wrapped method at a time per user.
+func (v *ConcurrentVocab) FindEntriesInUserVocab(userID int, query string, number, size int) (*domain.EntriesPage, error) {
+	v.vocabSync.startWork(userID)
+	defer v.vocabSync.endWork(userID)
+	return v.wrappedService.FindEntriesInUserVocab(userID, query, number, size)
 }
 
 // RemoveEntryFromUserVocab calls RemoveEntryFromUserVocab of wrapped vocabService with concurrent safe logic.
@@ -85,11 +125,32 @@ func (v *ConcurrentVocab) RemoveEntryFromUserVocab(entryID, userID int) error {
 }
 
 // GetVocabEntryByText calls GetVocabEntryByText of wrapped vocabService with concurrent safe logic.
-// Makes one call of the wrapped method at a time per text.
-func (v *ConcurrentVocab) GetVocabEntryByText(text string) (*domain.VocabEntry, error) {
-	v.vocabEntrySync.startWork(text)
-	defer v.vocabEntrySync.endWork(text)
-	return v.wrappedService.GetVocabEntryByText(text)
+// Makes one call of the wrapped method at a time per lemma of the text and language pair,
+// so the forms of the same word don't add the same entry concurrently.
+func (v *ConcurrentVocab) GetVocabEntryByText(text string, langPair domain.LangPair) (*domain.VocabEntry, error) {
+	key := entrySyncKey(text, langPair)
+	v.vocabEntrySync.startWork(key)
+	defer v.vocabEntrySync.endWork(key)
+	return v.wrappedService.GetVocabEntryByText(text, langPair)
+}
+
+// AddCustomVocabEntry calls AddCustomVocabEntry of wrapped vocabService with concurrent safe logic.
+// Makes one call of the wrapped method at a time per lemma of the text and language pair,
+// the calls are synced with GetVocabEntryByText calls for the same text.
+func (v *ConcurrentVocab) AddCustomVocabEntry(
+	text, translation string,
+	langPair domain.LangPair,
+	userID int,
+) (*domain.VocabEntry, error) {
+	key := entrySyncKey(text, langPair)
+	v.vocabEntrySync.startWork(key)
+	defer v.vocabEntrySync.endWork(key)
+	return v.wrappedService.AddCustomVocabEntry(text, translation, langPair, userID)
+}
+
+func entrySyncKey(text string, langPair domain.LangPair) string {
+	lemma, _ := lemmaOf(text, langPair)
+	return langPair.String() + ":" + lemma
 }
 
 // GetVocabEntryByID just calls GetVocabEntryByID of wrapped vocabService.
@@ -98,6 +159,41 @@ func (v *ConcurrentVocab) GetVocabEntryByID(id int) (*domain.VocabEntry, error)
 	return v.wrappedService.GetVocabEntryByID(id)
 }
 
+// ConcurrentScheduler wraps another implementation of service.Scheduler and adds logic for correct concurrency work.
+// Implements service.Scheduler itself.
+type ConcurrentScheduler struct {
+	wrappedService Scheduler
+	reviewSync     *userIDSync
+}
+
+// Returns ready to use ConcurrentScheduler.
+func NewConcurrentScheduler(wrappedService Scheduler) *ConcurrentScheduler {
+	return &ConcurrentScheduler{
+		wrappedService: wrappedService,
+		reviewSync: &userIDSync{
+			mu:     sync.Mutex{},
+			inWork: make(map[int]chan struct{}),
+		},
+	}
+}
+
+// GetNextEntryToReview calls GetNextEntryToReview of wrapped schedulerService with concurrent safe logic.
+// Makes one call of the wrapped method at a time per user.
+func (s *ConcurrentScheduler) GetNextEntryToReview(userID, previousEntryID int, direction domain.Direction) (*domain.VocabEntry, error) {
+	s.reviewSync.startWork(userID)
+	defer s.reviewSync.endWork(userID)
+	return s.wrappedService.GetNextEntryToReview(userID, previousEntryID, direction)
+}
+
+// ReviewEntry calls ReviewEntry of wrapped schedulerService with concurrent safe logic.
+// Makes one call of the wrapped method at a time per user, so the concurrent reviews of the same entry
+// don't reschedule it from the same stale schedule.
+func (s *ConcurrentScheduler) ReviewEntry(entryID, userID int, direction domain.Direction, grade domain.Grade) error {
+	s.reviewSync.startWork(userID)
+	defer s.reviewSync.endWork(userID)
+	return s.wrappedService.ReviewEntry(entryID, userID, direction, grade)
+}
+
 type userIDSync struct {
 	mu     sync.Mutex
 	inWork map[int]chan struct{}
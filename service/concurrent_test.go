@@ -29,6 +29,56 @@ func TestVocabConcurrent_CreateVocab(t *testing.T) {
 	}
 }
 
+func TestConcurrentVocab_AddUserVocab(t *testing.T) {
+	mockedService := mock.NewVocabServiceConcurrencyCheck()
+	mockedService.AddUserVocabFn = func(userID int, name string) (*domain.Vocab, error) {
+		return &domain.Vocab{UserID: userID, Name: name}, nil
+	}
+	testService := NewConcurrentVocab(mockedService)
+	var wg sync.WaitGroup
+	for i := 0; i < 300; i++ {
+		wg.Add(3)
+		go addUserVocab(&wg, testService, 1, "verbs")
+		go addUserVocab(&wg, testService, 1, "nouns")
+		go addUserVocab(&wg, testService, 2, "verbs")
+	}
+	wg.Wait()
+	if !mockedService.AddUserVocabInvoked {
+		t.Error("AddUserVocab wasn't invoked")
+	}
+	if mockedService.UserIDConcurrentlyInvoked {
+		t.Error("Underlying service was invoked concurrently by the same user")
+	}
+}
+
+func TestConcurrentVocab_UseUserVocab(t *testing.T) {
+	mockedService := mock.NewVocabServiceConcurrencyCheck()
+	mockedService.UseUserVocabFn = func(userID int, name string) (*domain.Vocab, error) {
+		return &domain.Vocab{UserID: userID, Name: name, Active: true}, nil
+	}
+	mockedService.GetUserVocabsFn = func(userID int) ([]*domain.Vocab, error) {
+		return []*domain.Vocab{{UserID: userID}}, nil
+	}
+	testService := NewConcurrentVocab(mockedService)
+	var wg sync.WaitGroup
+	for i := 0; i < 300; i++ {
+		wg.Add(3)
+		go useUserVocab(&wg, testService, 1, "verbs")
+		go getUserVocabs(&wg, testService, 1)
+		go useUserVocab(&wg, testService, 2, "verbs")
+	}
+	wg.Wait()
+	if !mockedService.UseUserVocabInvoked {
+		t.Error("UseUserVocab wasn't invoked")
+	}
+	if !mockedService.GetUserVocabsInvoked {
+		t.Error("GetUserVocabs wasn't invoked")
+	}
+	if mockedService.UserIDConcurrentlyInvoked {
+		t.Error("Underlying service was invoked concurrently by the same user")
+	}
+}
+
 func TestVocabConcurrent_ClearUserVocab(t *testing.T) {
 	mockedService := mock.NewVocabServiceConcurrencyCheck()
 	mockedService.ClearUserVocabFn = func(userID int) error {
@@ -95,47 +145,91 @@ func TestConcurrentVocab_CheckEntryInUserVocab(t *testing.T) {
 	}
 }
 
-func TestConcurrentVocab_GetRandomEntryFromUserVocab(t *testing.T) {
+func TestConcurrentVocab_GetEntriesFromUserVocab(t *testing.T) {
 	mockedService := mock.NewVocabServiceConcurrencyCheck()
-	mockedService.GetRandomEntryFromUserVocabFn = func(userID, previousEntryID int) (*domain.VocabEntry, error) {
-		return &domain.VocabEntry{}, nil
+	mockedService.GetEntriesFromUserVocabFn = func(userID int) ([]*domain.VocabEntry, error) {
+		return []*domain.VocabEntry{}, nil
 	}
 	testService := NewConcurrentVocab(mockedService)
 	var wg sync.WaitGroup
 	for i := 0; i < 300; i++ {
 		wg.Add(3)
-		go getRandomEntryFromUserVocab(&wg, testService, 1)
-		go getRandomEntryFromUserVocab(&wg, testService, 2)
-		go getRandomEntryFromUserVocab(&wg, testService, 3)
+		go getEntriesFromUserVocab(&wg, testService, 1)
+		go getEntriesFromUserVocab(&wg, testService, 2)
+		go getEntriesFromUserVocab(&wg, testService, 3)
 	}
 	wg.Wait()
-	if !mockedService.GetRandomEntryFromUserVocabInvoked {
-		t.Error("GetRandomEntryFromUserVocab wasn't invoked")
+	if !mockedService.GetEntriesFromUserVocabInvoked {
+		t.Error("GetEntriesFromUserVocab wasn't invoked")
 	}
 	if mockedService.UserIDConcurrentlyInvoked {
 		t.Error("Underlying service was invoked concurrently for the same text")
 	}
 }
 
-func TestConcurrentVocab_GetEntriesFromUserVocab(t *testing.T) {
+func TestConcurrentVocab_GetExportEntriesFromUserVocab(t *testing.T) {
 	mockedService := mock.NewVocabServiceConcurrencyCheck()
-	mockedService.GetEntriesFromUserVocabFn = func(userID int) ([]*domain.VocabEntry, error) {
-		return []*domain.VocabEntry{}, nil
+	mockedService.GetExportEntriesFromUserVocabFn = func(userID int) ([]*domain.ExportEntry, error) {
+		return []*domain.ExportEntry{}, nil
 	}
 	testService := NewConcurrentVocab(mockedService)
 	var wg sync.WaitGroup
 	for i := 0; i < 300; i++ {
 		wg.Add(3)
-		go getEntriesFromUserVocab(&wg, testService, 1)
-		go getEntriesFromUserVocab(&wg, testService, 2)
-		go getEntriesFromUserVocab(&wg, testService, 3)
+		go getExportEntriesFromUserVocab(&wg, testService, 1)
+		go getExportEntriesFromUserVocab(&wg, testService, 2)
+		go getExportEntriesFromUserVocab(&wg, testService, 3)
 	}
 	wg.Wait()
-	if !mockedService.GetEntriesFromUserVocabInvoked {
-		t.Error("GetEntriesFromUserVocab wasn't invoked")
+	if !mockedService.GetExportEntriesFromUserVocabInvoked {
+		t.Error("GetExportEntriesFromUserVocab wasn't invoked")
 	}
 	if mockedService.UserIDConcurrentlyInvoked {
-		t.Error("Underlying service was invoked concurrently for the same text")
+		t.Error("Underlying service was invoked concurrently for the same user")
+	}
+}
+
+func TestConcurrentVocab_GetEntriesPageFromUserVocab(t *testing.T) {
+	mockedService := mock.NewVocabServiceConcurrencyCheck()
+	mockedService.GetEntriesPageFromUserVocabFn = func(userID, number, size int) (*domain.EntriesPage, error) {
+		return &domain.EntriesPage{Number: number, Size: size}, nil
+	}
+	testService := NewConcurrentVocab(mockedService)
+	var wg sync.WaitGroup
+	for i := 0; i < 300; i++ {
+		wg.Add(3)
+		go getEntriesPageFromUserVocab(&wg, testService, 1)
+		go getEntriesPageFromUserVocab(&wg, testService, 2)
+		go getEntriesPageFromUserVocab(&wg, testService, 3)
+	}
+	wg.Wait()
+	if !mockedService.GetEntriesPageFromUserVocabInvoked {
+		t.Error("GetEntriesPageFromUserVocab wasn't invoked")
+	}
+	if mockedService.UserIDConcurrentlyInvoked {
+		t.Error("Underlying service was invoked concurrently for the same user")
+	}
+}
+
+func TestConcurrentVocab_FindEntriesInUserVocab(t *testing.T) {
+	mockedService := mock.NewVocabServiceConcurrencyCheck()
+	mockedService.FindEntriesInUserVocabFn = func(userID int, query string, number, size int) (*domain.EntriesPage, error) {
+		return &domain.EntriesPage{Number: number, Size: size}, nil
+	}
+	testService := NewConcurrentVocab(mockedService)
+	var wg sync.WaitGroup
+	for i := 0; i < 300; i++ {
+		wg.Add(3)
+		go findEntriesInUserVocab(&wg, testService, 1)
+		go findEntriesInUserVocab(&wg, testService, 2)
+		go findEntriesInUserVocab(&wg, testService, 3)
+	}
+	wg.Wait()
+	if !mockedService.FindEntriesInUserVocabInvoked {
+		t.Error("FindEntriesInUserVocab wasn't invoked")
+	}
+	if mockedService.UserIDConcurrentlyInvoked {
+		t.Error("Underlying service was invoked concurrently for the same user")
 	}
 }
 
@@ -163,16 +257,17 @@ func TestConcurrentVocab_RemoveEntryFromUserVocab(t *testing.T) {
 
 func TestConcurrentVocab_GetVocabEntryByText(t *testing.T) {
 	mockedService := mock.NewVocabServiceConcurrencyCheck()
-	mockedService.GetVocabEntryByTextFn = func(text string) (*domain.VocabEntry, error) {
-		return &domain.VocabEntry{Text: text}, nil
+	mockedService.GetVocabEntryByTextFn = func(text string, langPair domain.LangPair) (*domain.VocabEntry, error) {
+		return &domain.VocabEntry{Text: text, LangPair: langPair}, nil
 	}
 	testService := NewConcurrentVocab(mockedService)
 	var wg sync.WaitGroup
 	for i := 0; i < 300; i++ {
-		wg.Add(3)
-		go getVocabEntryByText(&wg, testService, "text")
-		go getVocabEntryByText(&wg, testService, "another")
-		go getVocabEntryByText(&wg, testService, "one")
+		wg.Add(4)
+		go getVocabEntryByText(&wg, testService, "text", domain.LangPair{Source: "en", Target: "ru"})
+		go getVocabEntryByText(&wg, testService, "text", domain.LangPair{Source: "de", Target: "ru"})
+		go getVocabEntryByText(&wg, testService, "another", domain.LangPair{Source: "en", Target: "ru"})
+		go getVocabEntryByText(&wg, testService, "one", domain.LangPair{Source: "en", Target: "uk"})
 	}
 	wg.Wait()
 	if !mockedService.GetVocabEntryByTextInvoked {
@@ -183,6 +278,36 @@ func TestConcurrentVocab_GetVocabEntryByText(t *testing.T) {
 	}
 }
 
+func TestConcurrentVocab_AddCustomVocabEntry(t *testing.T) {
+	mockedService := mock.NewVocabServiceConcurrencyCheck()
+	mockedService.GetVocabEntryByTextFn = func(text string, langPair domain.LangPair) (*domain.VocabEntry, error) {
+		return &domain.VocabEntry{Text: text, LangPair: langPair}, nil
+	}
+	mockedService.AddCustomVocabEntryFn = func(
+		text, translation string,
+		langPair domain.LangPair,
+		userID int,
+	) (*domain.VocabEntry, error) {
+		return &domain.VocabEntry{Text: text, LangPair: langPair, MainTranslation: translation, UserID: userID}, nil
+	}
+	testService := NewConcurrentVocab(mockedService)
+	var wg sync.WaitGroup
+	for i := 0; i < 300; i++ {
+		wg.Add(4)
+		go addCustomVocabEntry(&wg, testService, "text", domain.LangPair{Source: "en", Target: "ru"})
+		go getVocabEntryByText(&wg, testService, "text", domain.LangPair{Source: "en", Target: "ru"})
+		go addCustomVocabEntry(&wg, testService, "another", domain.LangPair{Source: "en", Target: "ru"})
+		go addCustomVocabEntry(&wg, testService, "text", domain.LangPair{Source: "de", Target: "ru"})
+	}
+	wg.Wait()
+	if !mockedService.AddCustomVocabEntryInvoked {
+		t.Error("AddCustomVocabEntry wasn't invoked")
+	}
+	if mockedService.TextConcurrentlyInvoked {
+		t.Error("Underlying service was invoked concurrently for the same text")
+	}
+}
+
 func TestConcurrentVocab_GetVocabEntryByID(t *testing.T) {
 	mockedService := mock.NewVocabServiceConcurrencyCheck()
 	mockedService.GetVocabEntryByIDFn = func(id int) (*domain.VocabEntry, error) {
@@ -212,9 +337,6 @@ func TestConcurrentVocab_SyncByUserIDTest(t *testing.T) {
 	mockedService.CheckEntryInUserVocabFn = func(entryID, userID int) (bool, error) {
 		return true, nil
 	}
-	mockedService.GetRandomEntryFromUserVocabFn = func(userId, previousEntryID int) (*domain.VocabEntry, error) {
-		return &domain.VocabEntry{}, nil
-	}
 	mockedService.GetEntriesFromUserVocabFn = func(userID int) ([]*domain.VocabEntry, error) {
 		return []*domain.VocabEntry{}, nil
 	}
@@ -224,12 +346,11 @@ func TestConcurrentVocab_SyncByUserIDTest(t *testing.T) {
 	testService := NewConcurrentVocab(mockedService)
 	var wg sync.WaitGroup
 	for i := 0; i < 300; i++ {
-		wg.Add(7)
+		wg.Add(6)
 		go createVocab(&wg, testService, 1)
 		go clearVocab(&wg, testService, 1)
 		go addEntryToUserVocab(&wg, testService, 1, 1)
 		go checkEntryInUserVocab(&wg, testService, 1, 1)
-		go getRandomEntryFromUserVocab(&wg, testService, 1)
 		go getEntriesFromUserVocab(&wg, testService, 1)
 		go removeEntryFromUserVocab(&wg, testService, 1, 1)
 	}
@@ -239,6 +360,48 @@ func TestConcurrentVocab_SyncByUserIDTest(t *testing.T) {
 	}
 }
 
+func TestConcurrentScheduler_ReviewEntry(t *testing.T) {
+	mockedService := mock.NewSchedulerServiceConcurrencyCheck()
+	mockedService.ReviewEntryFn = func(entryID, userID int, direction domain.Direction, grade domain.Grade) error {
+		return nil
+	}
+	mockedService.GetNextEntryToReviewFn = func(
+		userID, previousEntryID int,
+		direction domain.Direction,
+	) (*domain.VocabEntry, error) {
+		return &domain.VocabEntry{ID: 1}, nil
+	}
+	testService := NewConcurrentScheduler(mockedService)
+	var wg sync.WaitGroup
+	for i := 0; i < 300; i++ {
+		wg.Add(4)
+		go reviewEntry(&wg, testService, 1, 1)
+		go reviewEntry(&wg, testService, 1, 1)
+		go getNextEntryToReview(&wg, testService, 1)
+		go reviewEntry(&wg, testService, 1, 2)
+	}
+	wg.Wait()
+	if !mockedService.ReviewEntryInvoked {
+		t.Error("ReviewEntry wasn't invoked")
+	}
+	if !mockedService.GetNextEntryToReviewInvoked {
+		t.Error("GetNextEntryToReview wasn't invoked")
+	}
+	if mockedService.UserIDConcurrentlyInvoked {
+		t.Error("Underlying service was invoked concurrently by the same user")
+	}
+}
+
+func reviewEntry(wg *sync.WaitGroup, s *ConcurrentScheduler, entryID int, userID int) {
+	_ = s.ReviewEntry(entryID, userID, domain.DirectionForward, domain.GradeGood)
+	wg.Done()
+}
+
+func getNextEntryToReview(wg *sync.WaitGroup, s *ConcurrentScheduler, userID int) {
+	_, _ = s.GetNextEntryToReview(userID, -1, domain.DirectionForward)
+	wg.Done()
+}
+
 func createVocab(wg *sync.WaitGroup, s *ConcurrentVocab, userID int) {
 	_, _ = s.CreateVocab(userID)
 	wg.Done()
@@ -259,13 +422,18 @@ func checkEntryInUserVocab(wg *sync.WaitGroup, s *ConcurrentVocab, entryID int,
 	wg.Done()
 }
 
-func getRandomEntryFromUserVocab(wg *sync.WaitGroup, s *ConcurrentVocab, userID int) {
-	_, _ = s.GetRandomEntryFromUserVocab(userID, 0)
+func getEntriesFromUserVocab(wg *sync.WaitGroup, s *ConcurrentVocab, userID int) {
+	_, _ = s.GetEntriesFromUserVocab(userID)
 	wg.Done()
 }
 
-func getEntriesFromUserVocab(wg *sync.WaitGroup, s *ConcurrentVocab, userID int) {
-	_, _ = s.GetEntriesFromUserVocab(userID)
+func getEntriesPageFromUserVocab(wg *sync.WaitGroup, s *ConcurrentVocab, userID int) {
+	_, _ = s.GetEntriesPageFromUserVocab(userID, 0, 10)
+	wg.Done()
+}
+
+func findEntriesInUserVocab(wg *sync.WaitGroup, s *ConcurrentVocab, userID int) {
+	_, _ = s.FindEntriesInUserVocab(userID, "he", 0, 10)
 	wg.Done()
 }
 
@@ -274,7 +442,32 @@ func removeEntryFromUserVocab(wg *sync.WaitGroup, s *ConcurrentVocab, entryID in
 	wg.Done()
 }
 
-func getVocabEntryByText(wg *sync.WaitGroup, s *ConcurrentVocab, word string) {
-	_, _ = s.GetVocabEntryByText(word)
+func addUserVocab(wg *sync.WaitGroup, s *ConcurrentVocab, userID int, name string) {
+	_, _ = s.AddUserVocab(userID, name)
+	wg.Done()
+}
+
+func getUserVocabs(wg *sync.WaitGroup, s *ConcurrentVocab, userID int) {
+	_, _ = s.GetUserVocabs(userID)
+	wg.Done()
+}
+
+func useUserVocab(wg *sync.WaitGroup, s *ConcurrentVocab, userID int, name string) {
+	_, _ = s.UseUserVocab(userID, name)
+	wg.Done()
+}
+
+func getVocabEntryByText(wg *sync.WaitGroup, s *ConcurrentVocab, word string, langPair domain.LangPair) {
+	_, _ = s.GetVocabEntryByText(word, langPair)
+	wg.Done()
+}
+
+func getExportEntriesFromUserVocab(wg *sync.WaitGroup, s *ConcurrentVocab, userID int) {
+	_, _ = s.GetExportEntriesFromUserVocab(userID)
+	wg.Done()
+}
+
+func addCustomVocabEntry(wg *sync.WaitGroup, s *ConcurrentVocab, word string, langPair domain.LangPair) {
+	_, _ = s.AddCustomVocabEntry(word, "translation", langPair, 1)
 	wg.Done()
 }
@@ -0,0 +1,68 @@
+package service
+
+import (
+	"fmt"
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"github.com/dmalyar/pimpmyvocab/log"
+	"github.com/dmalyar/pimpmyvocab/nlp"
+	"github.com/dmalyar/pimpmyvocab/repo"
+	"strings"
+)
+
+// ExtractorWithLocalRepo implements service.Extractor interface for working with local repository.
+type ExtractorWithLocalRepo struct {
+	logger    log.Logger
+	localRepo repo.Vocab
+}
+
+func NewExtractorWithLocalRepo(logger log.Logger, localRepo repo.Vocab) *ExtractorWithLocalRepo {
+	return &ExtractorWithLocalRepo{
+		logger:    logger,
+		localRepo: localRepo,
+	}
+}
+
+// ExtractUnknownWords returns the words of the text in the source language of the pair
+// which are not in the user's active vocab yet, ordered from the rarest one.
+// See nlp.ExtractCandidates for the way the words are picked.
+func (e *ExtractorWithLocalRepo) ExtractUnknownWords(
+	userID int,
+	text string,
+	langPair domain.LangPair,
+) ([]*domain.WordCandidate, error) {
+	logger := e.logger.WithFields(map[string]interface{}{
+		"userID":   userID,
+		"langPair": langPair,
+	})
+	candidates := nlp.ExtractCandidates(text, langPair.Source)
+	logger.Debugf("Extracted %v candidate(s)", len(candidates))
+	if len(candidates) == 0 {
+		return candidates, nil
+	}
+	vocab, err := e.localRepo.GetActiveVocabByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("getting active vocab by user ID: %s", err)
+	}
+	if vocab == nil {
+		logger.Info("User has no vocab, all candidates are unknown")
+		return candidates, nil
+	}
+	entries, err := e.localRepo.GetEntriesByVocabID(vocab.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting vocab entries by vocab ID: %s", err)
+	}
+	known := make(map[string]struct{}, len(entries))
+	for _, entry := range entries {
+		if entry.LangPair == langPair {
+			known[strings.ToLower(entry.Text)] = struct{}{}
+		}
+	}
+	unknown := make([]*domain.WordCandidate, 0, len(candidates))
+	for _, c := range candidates {
+		if _, ok := known[c.Text]; !ok {
+			unknown = append(unknown, c)
+		}
+	}
+	logger.Infof("Found %v unknown word(s) of %v", len(unknown), len(candidates))
+	return unknown, nil
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"fmt"
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"github.com/dmalyar/pimpmyvocab/mock"
+	"testing"
+)
+
+func TestExtractorWithLocalRepo_ExtractUnknownWords(t *testing.T) {
+	enRu := domain.LangPair{Source: "en", Target: "ru"}
+	testCases := []struct {
+		name           string
+		userID         int
+		text           string
+		langPair       domain.LangPair
+		expectedWords  []string
+		expectGetEntry bool
+		expectErr      bool
+	}{
+		{
+			name:           "Positive",
+			userID:         1,
+			text:           "The hedgehog sat in the garden under a tree.",
+			langPair:       enRu,
+			expectedWords:  []string{"hedgehog", "sit"},
+			expectGetEntry: true,
+		},
+		{
+			name:           "Positive entries of other language pair are unknown",
+			userID:         1,
+			text:           "Garden",
+			langPair:       domain.LangPair{Source: "de", Target: "ru"},
+			expectedWords:  []string{"garden"},
+			expectGetEntry: true,
+		},
+		{
+			name:          "Positive user has no vocab",
+			userID:        0,
+			text:          "garden",
+			langPair:      enRu,
+			expectedWords: []string{"garden"},
+		},
+		{
+			name:          "Positive no words in text",
+			userID:        1,
+			text:          "42",
+			langPair:      enRu,
+			expectedWords: []string{},
+		},
+		{
+			name:      "Get active vocab returns err",
+			userID:    2,
+			text:      "garden",
+			langPair:  enRu,
+			expectErr: true,
+		},
+		{
+			name:           "Get entries returns err",
+			userID:         3,
+			text:           "garden",
+			langPair:       enRu,
+			expectGetEntry: true,
+			expectErr:      true,
+		},
+	}
+
+	mockedRepo := &mock.VocabRepo{
+		GetActiveVocabByUserIDFn: func(userID int) (*domain.Vocab, error) {
+			switch userID {
+			case 0:
+				return nil, nil
+			case 2:
+				return nil, fmt.Errorf("error")
+			default:
+				return &domain.Vocab{ID: userID, UserID: userID, Active: true}, nil
+			}
+		},
+		GetEntriesByVocabIDFn: func(vocabID int) ([]*domain.VocabEntry, error) {
+			if vocabID == 3 {
+				return nil, fmt.Errorf("error")
+			}
+			return []*domain.VocabEntry{
+				{ID: 1, Text: "Garden", LangPair: enRu},
+				{ID: 2, Text: "tree", LangPair: enRu},
+			}, nil
+		},
+	}
+
+	extractor := NewExtractorWithLocalRepo(mock.Logger{}, mockedRepo)
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			candidates, err := extractor.ExtractUnknownWords(c.userID, c.text, c.langPair)
+			if !c.expectErr && err != nil {
+				t.Errorf("Expected no error, but got %s", err)
+			}
+			if c.expectErr && err == nil {
+				t.Errorf("Expected error, but got nothing")
+			}
+			if !c.expectErr {
+				words := make([]string, 0, len(candidates))
+				for _, candidate := range candidates {
+					words = append(words, candidate.Text)
+				}
+				if fmt.Sprint(words) != fmt.Sprint(c.expectedWords) {
+					t.Errorf("Expected words:%v;Actual:%v", c.expectedWords, words)
+				}
+			}
+			if c.expectGetEntry != mockedRepo.GetEntriesByVocabIDInvoked {
+				t.Errorf("GetEntriesByVocabID invoked:%v;Expected:%v", mockedRepo.GetEntriesByVocabIDInvoked, c.expectGetEntry)
+			}
+			mockedRepo.Reset()
+		})
+	}
+}
@@ -3,20 +3,72 @@ package service
 import "github.com/dmalyar/pimpmyvocab/domain"
 
 // Vocab provides use cases for vocabs.
+// The user can have several vocabs, methods working with the user's vocab use the active one.
 type Vocab interface {
 	CreateVocab(userID int) (*domain.Vocab, error)
+	AddUserVocab(userID int, name string) (*domain.Vocab, error)
+	GetUserVocabs(userID int) ([]*domain.Vocab, error)
+	UseUserVocab(userID int, name string) (*domain.Vocab, error)
 	ClearUserVocab(userID int) error
 
 	AddEntryToUserVocab(entryID, userID int) error
 	CheckEntryInUserVocab(entryID, userID int) (bool, error)
-	GetRandomEntryFromUserVocab(userID, previousEntryID int) (*domain.VocabEntry, error)
 	GetEntriesFromUserVocab(userID int) ([]*domain.VocabEntry, error)
+	GetExportEntriesFromUserVocab(userID int) ([]*domain.ExportEntry, error)
+	GetEntriesPageFromUserVocab(userID, number, size int) (*domain.EntriesPage, error)
+	FindEntriesInUserVocab(userID int, query string, number, size int) (*domain.EntriesPage, error)
 	RemoveEntryFromUserVocab(entryID, userID int) error
 
+	AddCustomVocabEntry(text, translation string, langPair domain.LangPair, userID int) (*domain.VocabEntry, error)
+
 	VocabEntry
 }
 
 type VocabEntry interface {
-	GetVocabEntryByText(text string) (*domain.VocabEntry, error)
+	GetVocabEntryByText(text string, langPair domain.LangPair) (*domain.VocabEntry, error)
 	GetVocabEntryByID(id int) (*domain.VocabEntry, error)
 }
+
+// Scheduler provides use cases for spaced repetition of the entries from the user's vocab.
+type Scheduler interface {
+	GetNextEntryToReview(userID, previousEntryID int, direction domain.Direction) (*domain.VocabEntry, error)
+	ReviewEntry(entryID, userID int, direction domain.Direction, grade domain.Grade) error
+}
+
+// Quiz provides use cases for quizzes built from the user's vocab.
+type Quiz interface {
+	GetChoiceQuestion(userID, previousEntryID, optionsQnt int) (*domain.ChoiceQuestion, error)
+
+	StartQuizSession(userID int, direction domain.Direction, size int) (*domain.QuizSession, error)
+	AnswerQuizSessionEntry(entryID, userID int, direction domain.Direction, grade domain.Grade) (*domain.QuizSession, error)
+	StopQuizSession(userID int) (*domain.QuizSession, error)
+	GetQuizSummary(session *domain.QuizSession) (*domain.QuizSummary, error)
+}
+
+// Stats provides use cases for the user's learning statistics.
+type Stats interface {
+	GetStats(userID int) (*domain.Stats, error)
+	GetDailyProgress(userID int) (*domain.DailyProgress, error)
+	CheckDailyGoalReached(userID int) (*domain.DailyProgress, bool, error)
+	SetDailyGoal(userID int, chatID int64, goal int) (*domain.DailyProgress, error)
+}
+
+// Reminder provides use cases for daily reminders about entries waiting for review.
+type Reminder interface {
+	GetUserSettings(userID int) (*domain.UserSettings, error)
+	SetReminder(userID int, chatID int64, remindAt domain.TimeOfDay, timezone string) (*domain.UserSettings, error)
+	SetRemindersEnabled(userID int, chatID int64, enabled bool) (*domain.UserSettings, error)
+	GetRemindersToSend() ([]*domain.Reminder, error)
+	MarkReminderSent(reminder *domain.Reminder) (bool, error)
+}
+
+// Lang provides use cases for the language pair the user looks words up in.
+type Lang interface {
+	GetLangPair(userID int) (domain.LangPair, error)
+	SetLangPair(userID int, chatID int64, langPair domain.LangPair) error
+}
+
+// Extractor provides use cases for picking vocabulary from texts the user reads.
+type Extractor interface {
+	ExtractUnknownWords(userID int, text string, langPair domain.LangPair) ([]*domain.WordCandidate, error)
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"fmt"
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"github.com/dmalyar/pimpmyvocab/log"
+	"github.com/dmalyar/pimpmyvocab/repo"
+)
+
+// LangWithLocalRepo implements service.Lang interface for working with local repository.
+type LangWithLocalRepo struct {
+	logger       log.Logger
+	settingsRepo repo.UserSettings
+}
+
+func NewLangWithLocalRepo(logger log.Logger, settingsRepo repo.UserSettings) *LangWithLocalRepo {
+	return &LangWithLocalRepo{
+		logger:       logger,
+		settingsRepo: settingsRepo,
+	}
+}
+
+// GetLangPair returns the language pair chosen by the user.
+// Returns the default pair if the user has never chosen one.
+func (l *LangWithLocalRepo) GetLangPair(userID int) (domain.LangPair, error) {
+	settings, err := l.settingsRepo.GetUserSettings(userID)
+	if err != nil {
+		return domain.LangPair{}, fmt.Errorf("getting user settings: %s", err)
+	}
+	if settings == nil {
+		return domain.DefaultLangPair, nil
+	}
+	return settings.LangPair, nil
+}
+
+// SetLangPair saves the language pair the user looks words up in.
+// Returns error if the pair is not supported.
+func (l *LangWithLocalRepo) SetLangPair(userID int, chatID int64, langPair domain.LangPair) error {
+	logger := l.logger.WithFields(map[string]interface{}{
+		"userID":   userID,
+		"langPair": langPair,
+	})
+	logger.Debug("Setting language pair")
+	if !langPair.Supported() {
+		return fmt.Errorf("language pair %s is not supported", langPair)
+	}
+	settings, err := l.settingsRepo.GetUserSettings(userID)
+	if err != nil {
+		return fmt.Errorf("getting user settings: %s", err)
+	}
+	if settings == nil {
+		settings = domain.DefaultUserSettings(userID)
+	}
+	settings.ChatID = chatID
+	settings.LangPair = langPair
+	err = l.settingsRepo.SaveUserSettings(settings)
+	if err != nil {
+		return fmt.Errorf("saving user settings: %s", err)
+	}
+	logger.Info("Language pair set")
+	return nil
+}
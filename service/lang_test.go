@@ -0,0 +1,134 @@
+package service
+
+import (
+	"fmt"
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"github.com/dmalyar/pimpmyvocab/mock"
+	"testing"
+)
+
+func TestLangWithLocalRepo_GetLangPair(t *testing.T) {
+	testCases := []struct {
+		name      string
+		userID    int
+		expected  domain.LangPair
+		expectErr bool
+	}{
+		{name: "Positive chosen pair", userID: 1, expected: domain.LangPair{Source: "de", Target: "ru"}},
+		{name: "Positive default pair", userID: 2, expected: domain.DefaultLangPair},
+		{name: "Get settings returns error", userID: 3, expectErr: true},
+	}
+
+	mockedRepo := &mock.UserSettingsRepo{
+		GetUserSettingsFn: func(userID int) (*domain.UserSettings, error) {
+			switch userID {
+			case 1:
+				return &domain.UserSettings{UserID: 1, LangPair: domain.LangPair{Source: "de", Target: "ru"}}, nil
+			case 3:
+				return nil, fmt.Errorf("error")
+			default:
+				return nil, nil
+			}
+		},
+	}
+
+	langService := NewLangWithLocalRepo(mock.Logger{}, mockedRepo)
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			langPair, err := langService.GetLangPair(c.userID)
+			if !c.expectErr && err != nil {
+				t.Errorf("Expected no error, but got %s", err)
+			}
+			if c.expectErr && err == nil {
+				t.Errorf("Expected error, but got nothing")
+			}
+			if langPair != c.expected {
+				t.Errorf("Expected language pair:%s;Actual:%s", c.expected, langPair)
+			}
+		})
+	}
+}
+
+func TestLangWithLocalRepo_SetLangPair(t *testing.T) {
+	testCases := []struct {
+		name          string
+		userID        int
+		langPair      domain.LangPair
+		expectErr     bool
+		expectSaveInv bool
+	}{
+		{
+			name:          "Positive new settings",
+			userID:        1,
+			langPair:      domain.LangPair{Source: "en", Target: "uk"},
+			expectSaveInv: true,
+		},
+		{
+			name:          "Positive existing settings",
+			userID:        2,
+			langPair:      domain.LangPair{Source: "de", Target: "ru"},
+			expectSaveInv: true,
+		},
+		{
+			name:      "Unsupported pair",
+			userID:    1,
+			langPair:  domain.LangPair{Source: "ru", Target: "de"},
+			expectErr: true,
+		},
+		{
+			name:      "Get settings returns error",
+			userID:    3,
+			langPair:  domain.DefaultLangPair,
+			expectErr: true,
+		},
+		{
+			name:          "Save settings returns error",
+			userID:        4,
+			langPair:      domain.DefaultLangPair,
+			expectErr:     true,
+			expectSaveInv: true,
+		},
+	}
+
+	var saved *domain.UserSettings
+	mockedRepo := &mock.UserSettingsRepo{
+		GetUserSettingsFn: func(userID int) (*domain.UserSettings, error) {
+			switch userID {
+			case 2:
+				return &domain.UserSettings{UserID: 2, ChatID: 5, RemindAt: 60, Timezone: "Asia/Tokyo"}, nil
+			case 3:
+				return nil, fmt.Errorf("error")
+			default:
+				return nil, nil
+			}
+		},
+		SaveUserSettingsFn: func(settings *domain.UserSettings) error {
+			if settings.UserID == 4 {
+				return fmt.Errorf("error")
+			}
+			saved = settings
+			return nil
+		},
+	}
+
+	langService := NewLangWithLocalRepo(mock.Logger{}, mockedRepo)
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			saved = nil
+			err := langService.SetLangPair(c.userID, 10, c.langPair)
+			if !c.expectErr && err != nil {
+				t.Errorf("Expected no error, but got %s", err)
+			}
+			if c.expectErr && err == nil {
+				t.Errorf("Expected error, but got nothing")
+			}
+			if c.expectSaveInv != mockedRepo.SaveUserSettingsInvoked {
+				t.Errorf("Actual invocation of SaveUserSettings(%v) doesn't match expectations", mockedRepo.SaveUserSettingsInvoked)
+			}
+			if !c.expectErr && (saved.LangPair != c.langPair || saved.ChatID != 10) {
+				t.Errorf("Expected saved language pair:%s;Actual:%s", c.langPair, saved.LangPair)
+			}
+			mockedRepo.Reset()
+		})
+	}
+}
@@ -4,8 +4,9 @@ import (
 	"fmt"
 	"github.com/dmalyar/pimpmyvocab/domain"
 	"github.com/dmalyar/pimpmyvocab/log"
+	"github.com/dmalyar/pimpmyvocab/nlp"
 	"github.com/dmalyar/pimpmyvocab/repo"
-	"math/rand"
+	"strings"
 )
 
 // VocabWithLocalRepo implements service.Vocab interface for working with local repository.
@@ -23,22 +24,22 @@ func NewVocabWithLocalRepo(logger log.Logger, localRepo repo.Vocab, entryService
 	}
 }
 
-// CreateVocab creates a vocab in local localRepo for user.
+// CreateVocab creates the default vocab in local localRepo for user and makes it active.
 // Returns the created vocab entity.
 // Returns nil and skips vocab creation if user already has a vocab.
 func (v *VocabWithLocalRepo) CreateVocab(userID int) (*domain.Vocab, error) {
 	logger := v.logger.WithField("userID", userID)
 	logger.Debug("Checking if user already has a vocab")
-	vocab, err := v.localRepo.GetVocabByUserID(userID)
+	vocabs, err := v.localRepo.GetVocabsByUserID(userID)
 	if err != nil {
-		return nil, fmt.Errorf("getting vocab by user ID: %s", err)
+		return nil, fmt.Errorf("getting vocabs by user ID: %s", err)
 	}
-	if vocab != nil {
+	if len(vocabs) != 0 {
 		logger.Info("User already has vocab, no need to create a new one")
 		return nil, nil
 	}
 	logger.Debug("Creating vocab")
-	vocab, err = v.localRepo.AddVocab(&domain.Vocab{UserID: userID})
+	vocab, err := v.localRepo.AddVocab(&domain.Vocab{UserID: userID, Name: domain.DefaultVocabName, Active: true})
 	if err != nil {
 		return nil, fmt.Errorf("creating vocab: %s", err)
 	}
@@ -46,11 +47,76 @@ func (v *VocabWithLocalRepo) CreateVocab(userID int) (*domain.Vocab, error) {
 	return vocab, nil
 }
 
-// ClearUserVocab clears the user's vocab by removing all entries from it.
+// AddUserVocab creates a new vocab with the given name for user and makes it active.
+// Returns nil and skips vocab creation if user already has a vocab with this name.
+func (v *VocabWithLocalRepo) AddUserVocab(userID int, name string) (*domain.Vocab, error) {
+	logger := v.logger.WithFields(map[string]interface{}{
+		"userID": userID,
+		"name":   name,
+	})
+	logger.Debug("Checking if user already has a vocab with the name")
+	vocab, err := v.localRepo.GetVocabByName(userID, name)
+	if err != nil {
+		return nil, fmt.Errorf("getting vocab by name: %s", err)
+	}
+	if vocab != nil {
+		logger.Info("User already has vocab with the name")
+		return nil, nil
+	}
+	logger.Debug("Creating vocab")
+	vocab, err = v.localRepo.AddVocab(&domain.Vocab{UserID: userID, Name: name, Active: true})
+	if err != nil {
+		return nil, fmt.Errorf("creating vocab: %s", err)
+	}
+	logger.Infof("Vocab created: %s", vocab)
+	return vocab, nil
+}
+
+// GetUserVocabs returns all vocabs of the user.
+func (v *VocabWithLocalRepo) GetUserVocabs(userID int) ([]*domain.Vocab, error) {
+	logger := v.logger.WithField("userID", userID)
+	logger.Debug("Getting the user's vocabs")
+	vocabs, err := v.localRepo.GetVocabsByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("getting vocabs by user ID: %s", err)
+	}
+	logger.Infof("Found %v vocab(s)", len(vocabs))
+	return vocabs, nil
+}
+
+// UseUserVocab makes the user's vocab with the given name active.
+// Returns nil if user has no vocab with this name.
+func (v *VocabWithLocalRepo) UseUserVocab(userID int, name string) (*domain.Vocab, error) {
+	logger := v.logger.WithFields(map[string]interface{}{
+		"userID": userID,
+		"name":   name,
+	})
+	logger.Debug("Switching the user's active vocab")
+	vocab, err := v.localRepo.GetVocabByName(userID, name)
+	if err != nil {
+		return nil, fmt.Errorf("getting vocab by name: %s", err)
+	}
+	if vocab == nil {
+		logger.Info("User has no vocab with the name")
+		return nil, nil
+	}
+	err = v.localRepo.SetActiveVocab(vocab)
+	if err != nil {
+		return nil, fmt.Errorf("setting active vocab: %s", err)
+	}
+	logger.Infof("Active vocab switched: %s", vocab)
+	return vocab, nil
+}
+
+// ClearUserVocab clears the user's active vocab by removing all entries from it.
 func (v *VocabWithLocalRepo) ClearUserVocab(userID int) error {
 	logger := v.logger.WithField("userID", userID)
+	vocab, err := v.getActiveVocab(userID)
+	if err != nil || vocab == nil {
+		return err
+	}
 	logger.Debugf("Clearing the user's vocab")
-	err := v.localRepo.ClearVocabByUserID(userID)
+	err = v.localRepo.ClearVocab(vocab.ID)
 	if err != nil {
 		return fmt.Errorf("removing all entries from the user's vocab: %s", err)
 	}
@@ -58,14 +124,22 @@ func (v *VocabWithLocalRepo) ClearUserVocab(userID int) error {
 	return nil
 }
 
-// AddEntryToUserVocab adds the vocab entry to the user's vocab.
+// AddEntryToUserVocab adds the vocab entry to the user's active vocab.
 // If user already has this entry added to the vocab then do nothing.
+// Returns error if user has no vocab.
 func (v *VocabWithLocalRepo) AddEntryToUserVocab(entryID, userID int) error {
 	logger := v.logger.WithFields(map[string]interface{}{
 		"entryID": entryID,
 		"userID":  userID,
 	})
-	inVocab, err := v.CheckEntryInUserVocab(entryID, userID)
+	vocab, err := v.getActiveVocab(userID)
+	if err != nil {
+		return err
+	}
+	if vocab == nil {
+		return fmt.Errorf("user has no vocab")
+	}
+	inVocab, err := v.checkEntryInVocab(logger, entryID, vocab)
 	if err != nil {
 		return err
 	}
@@ -73,7 +147,7 @@ func (v *VocabWithLocalRepo) AddEntryToUserVocab(entryID, userID int) error {
 		return nil
 	}
 	logger.Debug("Adding the entry to the user's vocab")
-	err = v.localRepo.AddEntryToUserVocab(entryID, userID)
+	err = v.localRepo.AddEntryToVocab(entryID, vocab.ID)
 	if err != nil {
 		return fmt.Errorf("adding entry to user's vocab: %s", err)
 	}
@@ -81,14 +155,22 @@ func (v *VocabWithLocalRepo) AddEntryToUserVocab(entryID, userID int) error {
 	return nil
 }
 
-// CheckEntryInUserVocab checks if user already has the entry added to the vocab.
+// CheckEntryInUserVocab checks if user already has the entry added to the active vocab.
 func (v *VocabWithLocalRepo) CheckEntryInUserVocab(entryID, userID int) (bool, error) {
 	logger := v.logger.WithFields(map[string]interface{}{
 		"entryID": entryID,
 		"userID":  userID,
 	})
+	vocab, err := v.getActiveVocab(userID)
+	if err != nil || vocab == nil {
+		return false, err
+	}
+	return v.checkEntryInVocab(logger, entryID, vocab)
+}
+
+func (v *VocabWithLocalRepo) checkEntryInVocab(logger log.Logger, entryID int, vocab *domain.Vocab) (bool, error) {
 	logger.Debug("Checking if the entry is added to the user's vocab")
-	inVocab, err := v.localRepo.CheckEntryInUserVocab(entryID, userID)
+	inVocab, err := v.localRepo.CheckEntryInVocab(entryID, vocab.ID)
 	if err != nil {
 		return false, fmt.Errorf("checking if entry is added to vocab: %s", err)
 	}
@@ -100,62 +182,146 @@ func (v *VocabWithLocalRepo) CheckEntryInUserVocab(entryID, userID int) (bool, e
 	return inVocab, nil
 }
 
-// GetRandomEntryFromUserVocab returns a random vocab entry from the user's vocab.
-// If there is no entry in the user's vocab then returns nil.
-// If the given previousEntryID is not 0 then uses it to not return the same entry.
-// Ignores if it is the only entry in the vocab.
-func (v *VocabWithLocalRepo) GetRandomEntryFromUserVocab(userID int, previousEntryID int) (*domain.VocabEntry, error) {
+// GetEntriesFromUserVocab returns all entries linked to the user's active vocab.
+func (v *VocabWithLocalRepo) GetEntriesFromUserVocab(userID int) ([]*domain.VocabEntry, error) {
+	logger := v.logger.WithField("userID", userID)
+	vocab, err := v.getActiveVocab(userID)
+	if err != nil || vocab == nil {
+		return nil, err
+	}
+	logger.Debugf("Getting vocab entries")
+	entries, err := v.localRepo.GetEntriesByVocabID(vocab.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting vocab entries by vocab ID: %s", err)
+	}
+	logger.Infof("Found %v entry(-ies)", len(entries))
+	return entries, nil
+}
+
+// GetExportEntriesFromUserVocab returns the entries from the user's active vocab with all their translations,
+// the dates they were added and the learning progress.
+func (v *VocabWithLocalRepo) GetExportEntriesFromUserVocab(userID int) ([]*domain.ExportEntry, error) {
+	logger := v.logger.WithField("userID", userID)
+	vocab, err := v.getActiveVocab(userID)
+	if err != nil || vocab == nil {
+		return nil, err
+	}
+	logger.Debugf("Getting vocab entries to export")
+	entries, err := v.localRepo.GetExportEntriesByVocabID(vocab.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting vocab entries to export by vocab ID: %s", err)
+	}
+	logger.Infof("Found %v entry(-ies) to export", len(entries))
+	return entries, nil
+}
+
+// GetEntriesPageFromUserVocab returns the page of the entries from the user's active vocab.
+// If the page number is out of range (e.g. the entries were removed since the page was shown)
+// then the closest existing page is returned.
+func (v *VocabWithLocalRepo) GetEntriesPageFromUserVocab(userID, number, size int) (*domain.EntriesPage, error) {
 	logger := v.logger.WithFields(map[string]interface{}{
-		"userID":          userID,
-		"previousEntryID": previousEntryID,
+		"userID": userID,
+		"number": number,
+		"size":   size,
 	})
-	logger.Debug("Getting random vocab entry")
-	entryIDs, err := v.localRepo.GetEntryIDsByUserID(userID)
+	vocab, err := v.getActiveVocab(userID)
 	if err != nil {
 		return nil, err
 	}
-	qnt := len(entryIDs)
-	var id int
-	if qnt == 0 {
-		logger.Info("User's vocab is empty")
-		return nil, nil
-	}
-	if qnt == 1 {
-		id = entryIDs[0]
-	} else {
-		r := rand.Intn(qnt)
-		id = entryIDs[r]
-		if id == previousEntryID {
-			rr := rand.Intn(qnt)
-			for rr == r {
-				rr = rand.Intn(qnt)
-			}
-			id = entryIDs[rr]
-		}
+	return getEntriesPage(
+		logger,
+		vocab,
+		number,
+		size,
+		func() (int, error) {
+			return v.localRepo.CountEntriesByVocabID(vocab.ID)
+		},
+		func(limit, offset int) ([]*domain.VocabEntry, error) {
+			return v.localRepo.GetEntriesPageByVocabID(vocab.ID, limit, offset)
+		},
+	)
+}
+
+// FindEntriesInUserVocab returns the page of the entries from the user's active vocab matching the query.
+// The entry matches if its text starts with the query, any of its translations contains the query
+// or the query is the part of speech of any of its translations.
+// If the page number is out of range then the closest existing page is returned.
+func (v *VocabWithLocalRepo) FindEntriesInUserVocab(userID int, query string, number, size int) (*domain.EntriesPage, error) {
+	logger := v.logger.WithFields(map[string]interface{}{
+		"userID": userID,
+		"query":  query,
+		"number": number,
+		"size":   size,
+	})
+	vocab, err := v.getActiveVocab(userID)
+	if err != nil {
+		return nil, err
 	}
-	return v.GetVocabEntryByID(id)
+	return getEntriesPage(
+		logger,
+		vocab,
+		number,
+		size,
+		func() (int, error) {
+			return v.localRepo.CountFoundEntriesByVocabID(vocab.ID, query)
+		},
+		func(limit, offset int) ([]*domain.VocabEntry, error) {
+			return v.localRepo.FindEntriesByVocabID(vocab.ID, query, limit, offset)
+		},
+	)
 }
 
-// GetEntriesByUserID returns all entries linked to the user's vocab.
-func (v *VocabWithLocalRepo) GetEntriesFromUserVocab(userID int) ([]*domain.VocabEntry, error) {
-	logger := v.logger.WithField("userID", userID)
-	logger.Debugf("Getting vocab entries")
-	entries, err := v.localRepo.GetEntriesByUserID(userID)
+// getEntriesPage counts the entries, moves the page number into the range of existing pages
+// and gets the entries of the page. Returns an empty page if vocab is nil.
+func getEntriesPage(
+	logger log.Logger,
+	vocab *domain.Vocab,
+	number, size int,
+	count func() (int, error),
+	get func(limit, offset int) ([]*domain.VocabEntry, error),
+) (*domain.EntriesPage, error) {
+	page := &domain.EntriesPage{Size: size}
+	if vocab == nil {
+		return page, nil
+	}
+	logger.Debug("Counting vocab entries")
+	total, err := count()
 	if err != nil {
-		return nil, fmt.Errorf("getting vocab entries by user ID: %s", err)
+		return nil, fmt.Errorf("counting vocab entries: %s", err)
 	}
-	logger.Infof("Found %v entry(-ies)", len(entries))
-	return entries, nil
+	page.Total = total
+	page.Number = number
+	if page.Number >= page.PagesQnt() {
+		page.Number = page.PagesQnt() - 1
+	}
+	if page.Number < 0 {
+		page.Number = 0
+	}
+	if page.Total == 0 {
+		logger.Info("No vocab entries found")
+		return page, nil
+	}
+	logger.Debug("Getting page of vocab entries")
+	page.Entries, err = get(page.Size, page.Number*page.Size)
+	if err != nil {
+		return nil, fmt.Errorf("getting page of vocab entries: %s", err)
+	}
+	logger.Infof("Found %v entry(-ies) on page %v", len(page.Entries), page.Number)
+	return page, nil
 }
 
-// RemoveEntryFromUserVocab removes the vocab entry from the user's vocab.
+// RemoveEntryFromUserVocab removes the vocab entry from the user's active vocab.
 // If the entry is not in the user's vocab then do nothing.
 func (v *VocabWithLocalRepo) RemoveEntryFromUserVocab(entryID, userID int) error {
 	logger := v.logger.WithFields(map[string]interface{}{
 		"entryID": entryID,
 		"userID":  userID,
 	})
-	inVocab, err := v.CheckEntryInUserVocab(entryID, userID)
+	vocab, err := v.getActiveVocab(userID)
+	if err != nil || vocab == nil {
+		return err
+	}
+	inVocab, err := v.checkEntryInVocab(logger, entryID, vocab)
 	if err != nil {
 		return err
 	}
@@ -163,7 +329,7 @@ func (v *VocabWithLocalRepo) RemoveEntryFromUserVocab(entryID, userID int) error
 		return nil
 	}
 	logger.Debug("Removing the entry from the user's vocab")
-	err = v.localRepo.RemoveEntryFromUserVocab(entryID, userID)
+	err = v.localRepo.RemoveEntryFromVocab(entryID, vocab.ID)
 	if err != nil {
 		return fmt.Errorf("removing entry from user's vocab: %s", err)
 	}
@@ -171,14 +337,35 @@ func (v *VocabWithLocalRepo) RemoveEntryFromUserVocab(entryID, userID int) error
 	return nil
 }
 
-// GetVocabEntryByText looks for vocab entry in the local repo by the given text.
-// If it's found then returns it. If not then calls entry service method. If entry is found there then adds it
-// to the local repo.
-// If it's not found there then returns nil.
-func (v *VocabWithLocalRepo) GetVocabEntryByText(text string) (*domain.VocabEntry, error) {
-	logger := v.logger.WithField("text", text)
+// getActiveVocab returns the user's active vocab.
+// Returns nil if user has no vocab yet.
+func (v *VocabWithLocalRepo) getActiveVocab(userID int) (*domain.Vocab, error) {
+	vocab, err := v.localRepo.GetActiveVocabByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("getting active vocab by user ID: %s", err)
+	}
+	if vocab == nil {
+		v.logger.WithField("userID", userID).Info("User has no vocab")
+	}
+	return vocab, nil
+}
+
+// GetVocabEntryByText looks for vocab entry in the local repo by the given text and language pair,
+// then by the word forms linked to the entries. If it's found then returns it.
+// If not then the text itself is looked up in the entry service. If the entry service doesn't know it either
+// then the inflected English word is looked up by its lemma in the local repo and the entry service,
+// e.g. "went" by "go", and the found entry is returned with the text linked to it unless the lemma is only a guess,
+// so the text of the returned entry may differ from the given one. The words which are the lemmas themselves, e.g. "building",
+// are never linked to the other entries this way.
+// The entries found in the entry service are added to the local repo.
+// If it's not found anywhere then returns nil.
+func (v *VocabWithLocalRepo) GetVocabEntryByText(text string, langPair domain.LangPair) (*domain.VocabEntry, error) {
+	logger := v.logger.WithFields(map[string]interface{}{
+		"text":     text,
+		"langPair": langPair,
+	})
 	logger.Debug("Getting vocab entry")
-	entry, err := v.localRepo.GetVocabEntryByText(text)
+	entry, err := v.localRepo.GetVocabEntryByText(text, langPair)
 	if err != nil {
 		return nil, fmt.Errorf("getting vocab entry by text in the local repo: %s", err)
 	}
@@ -186,8 +373,62 @@ func (v *VocabWithLocalRepo) GetVocabEntryByText(text string) (*domain.VocabEntr
 		logger.WithField("entry", entry).Info("Vocab entry found in the local repo")
 		return entry, nil
 	}
+	entry, err = v.localRepo.GetVocabEntryByAlias(text, langPair)
+	if err != nil {
+		return nil, fmt.Errorf("getting vocab entry by alias in the local repo: %s", err)
+	}
+	if entry != nil {
+		logger.WithField("entry", entry).Info("Vocab entry found in the local repo by alias")
+		return entry, nil
+	}
 	logger.Info("Vocab entry not found in the local repo")
-	entry, err = v.entryService.GetVocabEntryByText(text)
+	entry, err = v.addVocabEntryFromEntryService(logger, text, langPair)
+	if err != nil || entry != nil {
+		return entry, err
+	}
+	if lemma, known := lemmaOf(text, langPair); lemma != text {
+		return v.getVocabEntryByLemma(logger.WithField("lemma", lemma), text, lemma, known, langPair)
+	}
+	return nil, nil
+}
+
+// getVocabEntryByLemma looks for the entry of the lemma in the local repo and then in the entry service.
+// If it's found and the lemma is known then links the text to it. The guessed lemmas aren't linked
+// as the link would be used for everyone.
+func (v *VocabWithLocalRepo) getVocabEntryByLemma(
+	logger log.Logger,
+	text, lemma string,
+	known bool,
+	langPair domain.LangPair,
+) (*domain.VocabEntry, error) {
+	entry, err := v.localRepo.GetVocabEntryByText(lemma, langPair)
+	if err != nil {
+		return nil, fmt.Errorf("getting vocab entry by lemma in the local repo: %s", err)
+	}
+	if entry == nil {
+		entry, err = v.addVocabEntryFromEntryService(logger, lemma, langPair)
+		if err != nil || entry == nil {
+			return nil, err
+		}
+	}
+	if known {
+		err = v.localRepo.AddVocabEntryAlias(text, entry.ID)
+		if err != nil {
+			return nil, fmt.Errorf("adding vocab entry alias to the local repo: %s", err)
+		}
+	}
+	logger.WithField("entry", entry).Info("Vocab entry found by lemma")
+	return entry, nil
+}
+
+// addVocabEntryFromEntryService calls entry service method. If entry is found there then adds it
+// to the local repo. If it's not found there then returns nil.
+func (v *VocabWithLocalRepo) addVocabEntryFromEntryService(
+	logger log.Logger,
+	text string,
+	langPair domain.LangPair,
+) (*domain.VocabEntry, error) {
+	entry, err := v.entryService.GetVocabEntryByText(text, langPair)
 	if err != nil {
 		return nil, fmt.Errorf("getting vocab entry from the vocab entry service: %s", err)
 	}
@@ -205,6 +446,63 @@ func (v *VocabWithLocalRepo) GetVocabEntryByText(text string) (*domain.VocabEntr
 	return entry, nil
 }
 
+// lemmaOf returns the lemma of the English word and whether it's certain,
+// the words of other languages and phrases are returned as is.
+func lemmaOf(text string, langPair domain.LangPair) (string, bool) {
+	if langPair.Source != "en" || strings.ContainsRune(text, ' ') {
+		return text, true
+	}
+	return nlp.KnownLemma(text)
+}
+
+// AddCustomVocabEntry adds the entry with the user's own translation to the local repo.
+// It's used for the words the vocab entry service doesn't know. The entry belongs to the user,
+// it isn't returned by GetVocabEntryByText to anyone, and has the only translation without class.
+// If the dictionary entry or the user's custom entry with the given text and language pair
+// is already in the local repo then returns it.
+func (v *VocabWithLocalRepo) AddCustomVocabEntry(
+	text, translation string,
+	langPair domain.LangPair,
+	userID int,
+) (*domain.VocabEntry, error) {
+	logger := v.logger.WithFields(map[string]interface{}{
+		"text":        text,
+		"translation": translation,
+		"langPair":    langPair,
+		"userID":      userID,
+	})
+	logger.Debug("Adding custom vocab entry")
+	entry, err := v.localRepo.GetVocabEntryByText(text, langPair)
+	if err != nil {
+		return nil, fmt.Errorf("getting vocab entry by text in the local repo: %s", err)
+	}
+	if entry != nil {
+		logger.WithField("entry", entry).Info("Vocab entry found in the local repo")
+		return entry, nil
+	}
+	entry, err = v.localRepo.GetCustomVocabEntryByText(text, langPair, userID)
+	if err != nil {
+		return nil, fmt.Errorf("getting custom vocab entry by text in the local repo: %s", err)
+	}
+	if entry != nil {
+		logger.WithField("entry", entry).Info("Custom vocab entry found in the local repo")
+		return entry, nil
+	}
+	entry, err = v.localRepo.AddVocabEntry(&domain.VocabEntry{
+		Text:            text,
+		LangPair:        langPair,
+		Source:          domain.CustomEntrySource,
+		UserID:          userID,
+		MainTranslation: translation,
+		Translations:    []*domain.Translation{{Text: translation}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("adding custom vocab entry to the local repo: %s", err)
+	}
+	logger.Info("Custom vocab entry added to the local repo")
+	return entry, nil
+}
+
 // GetVocabEntryByID returns vocab entry found in the local repo by ID.
 // Returns nil if entry was not found.
 func (v *VocabWithLocalRepo) GetVocabEntryByID(id int) (*domain.VocabEntry, error) {
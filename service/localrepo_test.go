@@ -11,12 +11,12 @@ import (
 
 func TestVocabWithLocalRepo_CreateVocab(t *testing.T) {
 	testCases := []struct {
-		name                      string
-		userID                    int
-		expectedVocab             *domain.Vocab
-		expectGetVocabByUserIDInv bool
-		expectAddVocabInv         bool
-		expectErr                 bool
+		name               string
+		userID             int
+		expectedVocab      *domain.Vocab
+		expectGetVocabsInv bool
+		expectAddVocabInv  bool
+		expectErr          bool
 	}{
 		{
 			name:   "Positive",
@@ -24,51 +24,47 @@ func TestVocabWithLocalRepo_CreateVocab(t *testing.T) {
 			expectedVocab: &domain.Vocab{
 				ID:     1,
 				UserID: 1,
+				Name:   domain.DefaultVocabName,
+				Active: true,
 			},
-			expectGetVocabByUserIDInv: true,
-			expectAddVocabInv:         true,
+			expectGetVocabsInv: true,
+			expectAddVocabInv:  true,
 		},
 		{
-			name:                      "GetVocabByUserID returns error",
-			userID:                    2,
-			expectErr:                 true,
-			expectGetVocabByUserIDInv: true,
+			name:               "GetVocabsByUserID returns error",
+			userID:             2,
+			expectErr:          true,
+			expectGetVocabsInv: true,
 		},
 		{
-			name:                      "GetVocabByUserID returns vocab",
-			userID:                    3,
-			expectGetVocabByUserIDInv: true,
+			name:               "GetVocabsByUserID returns vocab",
+			userID:             3,
+			expectGetVocabsInv: true,
 		},
 		{
-			name:                      "AddVocab returns error",
-			userID:                    4,
-			expectErr:                 true,
-			expectGetVocabByUserIDInv: true,
-			expectAddVocabInv:         true,
+			name:               "AddVocab returns error",
+			userID:             4,
+			expectErr:          true,
+			expectGetVocabsInv: true,
+			expectAddVocabInv:  true,
 		},
 	}
 
 	mockedRepo := &mock.VocabRepo{
-		GetVocabByUserIDFn: func(userID int) (*domain.Vocab, error) {
+		GetVocabsByUserIDFn: func(userID int) ([]*domain.Vocab, error) {
 			switch userID {
-			case 1:
-				return nil, nil
 			case 2:
 				return nil, errors.New("err")
 			case 3:
-				return &domain.Vocab{}, nil
-			case 4:
-				return nil, nil
+				return []*domain.Vocab{{}}, nil
 			}
 			return nil, nil
 		},
 		AddVocabFn: func(vocab *domain.Vocab) (*domain.Vocab, error) {
 			switch vocab.UserID {
 			case 1:
-				return &domain.Vocab{
-					ID:     1,
-					UserID: 1,
-				}, nil
+				vocab.ID = 1
+				return vocab, nil
 			case 4:
 				return nil, errors.New("err")
 			}
@@ -89,8 +85,8 @@ func TestVocabWithLocalRepo_CreateVocab(t *testing.T) {
 			if c.expectAddVocabInv != mockedRepo.AddVocabInvoked {
 				t.Errorf("Actual invocation of AddVocab(%v) doesn't match expectations", mockedRepo.AddVocabInvoked)
 			}
-			if c.expectGetVocabByUserIDInv != mockedRepo.GetVocabByUserIDInvoked {
-				t.Errorf("Actual invocation of GetVocabByUserID(%v) doesn't match expectations", mockedRepo.GetVocabByUserIDInvoked)
+			if c.expectGetVocabsInv != mockedRepo.GetVocabsByUserIDInvoked {
+				t.Errorf("Actual invocation of GetVocabsByUserID(%v) doesn't match expectations", mockedRepo.GetVocabsByUserIDInvoked)
 			}
 			if c.expectedVocab == nil && vocab != nil {
 				t.Errorf("Nil vocab expected")
@@ -106,6 +102,158 @@ func TestVocabWithLocalRepo_CreateVocab(t *testing.T) {
 	}
 }
 
+func TestVocabWithLocalRepo_AddUserVocab(t *testing.T) {
+	testCases := []struct {
+		name              string
+		userID            int
+		vocabName         string
+		expectedVocab     *domain.Vocab
+		expectAddVocabInv bool
+		expectErr         bool
+	}{
+		{
+			name:              "Positive",
+			userID:            1,
+			vocabName:         "Verbs",
+			expectedVocab:     &domain.Vocab{ID: 10, UserID: 1, Name: "Verbs", Active: true},
+			expectAddVocabInv: true,
+		},
+		{
+			name:      "Vocab with the name exists",
+			userID:    1,
+			vocabName: "Nouns",
+		},
+		{
+			name:      "GetVocabByName returns error",
+			userID:    2,
+			vocabName: "Verbs",
+			expectErr: true,
+		},
+		{
+			name:              "AddVocab returns error",
+			userID:            3,
+			vocabName:         "Verbs",
+			expectAddVocabInv: true,
+			expectErr:         true,
+		},
+	}
+
+	mockedRepo := &mock.VocabRepo{
+		GetVocabByNameFn: func(userID int, name string) (*domain.Vocab, error) {
+			switch {
+			case userID == 2:
+				return nil, errors.New("err")
+			case name == "Nouns":
+				return &domain.Vocab{ID: 5, UserID: userID, Name: name}, nil
+			}
+			return nil, nil
+		},
+		AddVocabFn: func(vocab *domain.Vocab) (*domain.Vocab, error) {
+			if vocab.UserID == 3 {
+				return nil, errors.New("err")
+			}
+			vocab.ID = 10
+			return vocab, nil
+		},
+	}
+
+	vocabService := NewVocabWithLocalRepo(mock.Logger{}, mockedRepo, &mock.VocabEntryService{})
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			vocab, err := vocabService.AddUserVocab(c.userID, c.vocabName)
+			if !c.expectErr && err != nil {
+				t.Errorf("Expected no error, but got %s", err)
+			}
+			if c.expectErr && err == nil {
+				t.Errorf("Expected error, but got nothing")
+			}
+			if c.expectAddVocabInv != mockedRepo.AddVocabInvoked {
+				t.Errorf("Actual invocation of AddVocab(%v) doesn't match expectations", mockedRepo.AddVocabInvoked)
+			}
+			if !reflect.DeepEqual(c.expectedVocab, vocab) {
+				t.Errorf("Expected vocab:%+v;Actual:%+v", c.expectedVocab, vocab)
+			}
+			mockedRepo.Reset()
+		})
+	}
+}
+
+func TestVocabWithLocalRepo_UseUserVocab(t *testing.T) {
+	testCases := []struct {
+		name               string
+		userID             int
+		vocabName          string
+		expectedVocab      *domain.Vocab
+		expectSetActiveInv bool
+		expectErr          bool
+	}{
+		{
+			name:               "Positive",
+			userID:             1,
+			vocabName:          "verbs",
+			expectedVocab:      &domain.Vocab{ID: 10, UserID: 1, Name: "Verbs", Active: true},
+			expectSetActiveInv: true,
+		},
+		{
+			name:      "Vocab not found",
+			userID:    1,
+			vocabName: "Nouns",
+		},
+		{
+			name:      "GetVocabByName returns error",
+			userID:    2,
+			vocabName: "Verbs",
+			expectErr: true,
+		},
+		{
+			name:               "SetActiveVocab returns error",
+			userID:             3,
+			vocabName:          "Verbs",
+			expectSetActiveInv: true,
+			expectErr:          true,
+		},
+	}
+
+	mockedRepo := &mock.VocabRepo{
+		GetVocabByNameFn: func(userID int, name string) (*domain.Vocab, error) {
+			switch {
+			case userID == 2:
+				return nil, errors.New("err")
+			case name == "Nouns":
+				return nil, nil
+			}
+			return &domain.Vocab{ID: 10, UserID: userID, Name: "Verbs"}, nil
+		},
+		SetActiveVocabFn: func(vocab *domain.Vocab) error {
+			if vocab.UserID == 3 {
+				return errors.New("err")
+			}
+			vocab.Active = true
+			return nil
+		},
+	}
+
+	vocabService := NewVocabWithLocalRepo(mock.Logger{}, mockedRepo, &mock.VocabEntryService{})
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			vocab, err := vocabService.UseUserVocab(c.userID, c.vocabName)
+			if !c.expectErr && err != nil {
+				t.Errorf("Expected no error, but got %s", err)
+			}
+			if c.expectErr && err == nil {
+				t.Errorf("Expected error, but got nothing")
+			}
+			if c.expectSetActiveInv != mockedRepo.SetActiveVocabInvoked {
+				t.Errorf("Actual invocation of SetActiveVocab(%v) doesn't match expectations", mockedRepo.SetActiveVocabInvoked)
+			}
+			if !reflect.DeepEqual(c.expectedVocab, vocab) {
+				t.Errorf("Expected vocab:%+v;Actual:%+v", c.expectedVocab, vocab)
+			}
+			mockedRepo.Reset()
+		})
+	}
+}
+
 func TestVocabWithLocalRepo_ClearUserVocab(t *testing.T) {
 	testCases := []struct {
 		name      string
@@ -124,8 +272,9 @@ func TestVocabWithLocalRepo_ClearUserVocab(t *testing.T) {
 	}
 
 	mockedRepo := &mock.VocabRepo{
-		ClearVocabByUserIDFn: func(userID int) error {
-			switch userID {
+		GetActiveVocabByUserIDFn: getActiveVocabByUserID,
+		ClearVocabFn: func(vocabID int) error {
+			switch vocabID {
 			case 2:
 				return errors.New("err")
 			}
@@ -143,8 +292,8 @@ func TestVocabWithLocalRepo_ClearUserVocab(t *testing.T) {
 			if c.expectErr && err == nil {
 				t.Errorf("Expected error, but got nothing")
 			}
-			if !mockedRepo.ClearVocabByUserIDInvoked {
-				t.Errorf("ClearVocabByUserID wasn't invoked")
+			if !mockedRepo.ClearVocabInvoked {
+				t.Errorf("ClearVocab wasn't invoked")
 			}
 			mockedRepo.Reset()
 		})
@@ -187,22 +336,29 @@ func TestVocabWithLocalRepo_AddEntryToUserVocab(t *testing.T) {
 			expectAddEntryInv:   true,
 			expectErr:           true,
 		},
+		{
+			name:      "User has no vocab",
+			entryID:   5,
+			userID:    0,
+			expectErr: true,
+		},
 	}
 
 	mockedRepo := &mock.VocabRepo{
-		CheckEntryInUserVocabFn: func(entryID, userID int) (bool, error) {
+		GetActiveVocabByUserIDFn: getActiveVocabByUserID,
+		CheckEntryInVocabFn: func(entryID, vocabID int) (bool, error) {
 			switch {
-			case entryID == 2 && userID == 2:
+			case entryID == 2 && vocabID == 2:
 				return true, nil
-			case entryID == 3 && userID == 3:
+			case entryID == 3 && vocabID == 3:
 				return false, fmt.Errorf("error")
 			default:
 				return false, nil
 			}
 		},
-		AddEntryToUserVocabFn: func(entryID, userID int) error {
+		AddEntryToVocabFn: func(entryID, vocabID int) error {
 			switch {
-			case entryID == 4 && userID == 4:
+			case entryID == 4 && vocabID == 4:
 				return fmt.Errorf("error")
 			default:
 				return nil
@@ -217,11 +373,11 @@ func TestVocabWithLocalRepo_AddEntryToUserVocab(t *testing.T) {
 			if c.expectErr == false && err != nil {
 				t.Errorf("Expected no error, but got %s", err)
 			}
-			if c.expectCheckEntryInv != mockedRepo.CheckEntryInUserVocabInvoked {
-				t.Errorf("Actual invocation of CheckEntryInUserVocab(%v) doesn't match expectations", mockedRepo.CheckEntryInUserVocabInvoked)
+			if c.expectCheckEntryInv != mockedRepo.CheckEntryInVocabInvoked {
+				t.Errorf("Actual invocation of CheckEntryInVocab(%v) doesn't match expectations", mockedRepo.CheckEntryInVocabInvoked)
 			}
-			if c.expectAddEntryInv != mockedRepo.AddEntryToUserVocabInvoked {
-				t.Errorf("Actual invocation of AddEntryToUserVocab(%v) doesn't match expectations", mockedRepo.AddEntryToUserVocabInvoked)
+			if c.expectAddEntryInv != mockedRepo.AddEntryToVocabInvoked {
+				t.Errorf("Actual invocation of AddEntryToVocab(%v) doesn't match expectations", mockedRepo.AddEntryToVocabInvoked)
 			}
 			if c.expectErr && err == nil {
 				t.Errorf("Expected error, but got nothing")
@@ -253,11 +409,12 @@ func TestVocabWithLocalRepo_CheckEntryInUserVocab(t *testing.T) {
 	}
 
 	mockedRepo := &mock.VocabRepo{
-		CheckEntryInUserVocabFn: func(entryID, userID int) (bool, error) {
+		GetActiveVocabByUserIDFn: getActiveVocabByUserID,
+		CheckEntryInVocabFn: func(entryID, vocabID int) (bool, error) {
 			switch {
-			case entryID == 1 && userID == 1:
+			case entryID == 1 && vocabID == 1:
 				return true, nil
-			case entryID == 2 && userID == 2:
+			case entryID == 2 && vocabID == 2:
 				return false, fmt.Errorf("error")
 			default:
 				return false, nil
@@ -278,125 +435,90 @@ func TestVocabWithLocalRepo_CheckEntryInUserVocab(t *testing.T) {
 			if c.expectedRes != res {
 				t.Errorf("Expected res:%+v;Actual:%+v", c.expectedRes, res)
 			}
-			if !mockedRepo.CheckEntryInUserVocabInvoked {
-				t.Errorf("CheckEntryInUserVocab was not invoked")
+			if !mockedRepo.CheckEntryInVocabInvoked {
+				t.Errorf("CheckEntryInVocab was not invoked")
 			}
 			mockedRepo.Reset()
 		})
 	}
 }
 
-func TestVocabWithLocalRepo_GetRandomEntryFromUserVocab(t *testing.T) {
+func TestVocabWithLocalRepo_GetEntriesFromUserVocab(t *testing.T) {
 	testCases := []struct {
-		name                    string
-		userID, previousEntryID int
-		expectedEntry           *domain.VocabEntry
-		expectErr               bool
-		expectGetEntryIDsInv    bool
-		expectGetEntryByIDInv   bool
+		name            string
+		userID          int
+		expectedEntries []*domain.VocabEntry
+		expectErr       bool
 	}{
 		{
-			name:                  "Positive multiple entries",
-			userID:                1,
-			previousEntryID:       1,
-			expectedEntry:         &domain.VocabEntry{ID: 2},
-			expectGetEntryIDsInv:  true,
-			expectGetEntryByIDInv: true,
-		},
-		{
-			name:                  "Positive single entry",
-			userID:                2,
-			previousEntryID:       1,
-			expectedEntry:         &domain.VocabEntry{ID: 1},
-			expectGetEntryIDsInv:  true,
-			expectGetEntryByIDInv: true,
-		},
-		{
-			name:                 "Positive no entries",
-			userID:               3,
-			previousEntryID:      1,
-			expectGetEntryIDsInv: true,
+			name:   "Positive",
+			userID: 1,
+			expectedEntries: []*domain.VocabEntry{
+				{ID: 1, Text: "One"},
+				{ID: 2, Text: "Two"},
+			},
 		},
 		{
-			name:                 "Get entry IDs returns error",
-			userID:               4,
-			previousEntryID:      1,
-			expectGetEntryIDsInv: true,
-			expectErr:            true,
+			name:   "Positive no entries found",
+			userID: 2,
 		},
 		{
-			name:                  "Get entry by ID returns error",
-			userID:                5,
-			previousEntryID:       1,
-			expectGetEntryIDsInv:  true,
-			expectGetEntryByIDInv: true,
-			expectErr:             true,
+			name:      "Get vocab entries returns err",
+			userID:    3,
+			expectErr: true,
 		},
 	}
 
 	mockedRepo := &mock.VocabRepo{
-		GetEntryIDsByUserIDFn: func(userID int) ([]int, error) {
-			switch userID {
+		GetActiveVocabByUserIDFn: getActiveVocabByUserID,
+		GetEntriesByVocabIDFn: func(vocabID int) ([]*domain.VocabEntry, error) {
+			switch vocabID {
 			case 1:
-				return []int{1, 2}, nil
-			case 2:
-				return []int{1}, nil
-			case 4:
+				return []*domain.VocabEntry{
+					{ID: 1, Text: "One"},
+					{ID: 2, Text: "Two"},
+				}, nil
+			case 3:
 				return nil, fmt.Errorf("error")
-			case 5:
-				return []int{5}, nil
 			default:
 				return nil, nil
 			}
 		},
-		GetVocabEntryByIDFn: func(id int) (*domain.VocabEntry, error) {
-			switch id {
-			case 5:
-				return nil, fmt.Errorf("error")
-			default:
-				return &domain.VocabEntry{ID: id}, nil
-			}
-		},
 	}
 
 	vocabService := NewVocabWithLocalRepo(mock.Logger{}, mockedRepo, &mock.VocabEntryService{})
 	for _, c := range testCases {
 		t.Run(c.name, func(t *testing.T) {
-			entry, err := vocabService.GetRandomEntryFromUserVocab(c.userID, c.previousEntryID)
-			if !c.expectErr && err != nil {
-				t.Errorf("Expected no error, but got %s", err)
-			}
+			entries, err := vocabService.GetEntriesFromUserVocab(c.userID)
 			if c.expectErr && err == nil {
 				t.Errorf("Expected error, but got nothing")
 			}
-			if c.expectGetEntryIDsInv != mockedRepo.GetEntryIDsByUserIDInvoked {
-				t.Errorf("Actual invocation of GetEntryIDsByUserID(%v) doesn't match expectations", mockedRepo.GetEntryIDsByUserIDInvoked)
-			}
-			if c.expectGetEntryByIDInv != mockedRepo.GetVocabEntryByIDInvoked {
-				t.Errorf("Actual invocation of GetVocabEntryByID(%v) doesn't match expectations", mockedRepo.GetVocabEntryByIDInvoked)
+			if !reflect.DeepEqual(c.expectedEntries, entries) {
+				t.Errorf("Expected res:%+v;Actual:%+v", c.expectedEntries, entries)
 			}
-			if !reflect.DeepEqual(c.expectedEntry, entry) {
-				t.Errorf("Expected entry:%+v;Actual:%+v", c.expectedEntry, entry)
+			if !mockedRepo.GetEntriesByVocabIDInvoked {
+				t.Errorf("GetEntriesByVocabID was not invoked")
 			}
 			mockedRepo.Reset()
 		})
 	}
 }
 
-func TestVocabWithLocalRepo_GetEntriesFromUserVocab(t *testing.T) {
+func TestVocabWithLocalRepo_GetExportEntriesFromUserVocab(t *testing.T) {
+	exportEntries := []*domain.ExportEntry{
+		{Entry: &domain.VocabEntry{ID: 1, Text: "One"}, Progress: domain.EntryProgress{EntryID: 1, CorrectStreak: 2}},
+		{Entry: &domain.VocabEntry{ID: 2, Text: "Two"}, Progress: domain.EntryProgress{EntryID: 2}},
+	}
 	testCases := []struct {
 		name            string
 		userID          int
-		expectedEntries []*domain.VocabEntry
+		expectedEntries []*domain.ExportEntry
 		expectErr       bool
 	}{
 		{
-			name:   "Positive",
-			userID: 1,
-			expectedEntries: []*domain.VocabEntry{
-				{ID: 1, Text: "One"},
-				{ID: 2, Text: "Two"},
-			},
+			name:            "Positive",
+			userID:          1,
+			expectedEntries: exportEntries,
 		},
 		{
 			name:   "Positive no entries found",
@@ -410,13 +532,11 @@ func TestVocabWithLocalRepo_GetEntriesFromUserVocab(t *testing.T) {
 	}
 
 	mockedRepo := &mock.VocabRepo{
-		GetEntriesByUserIDFn: func(userID int) ([]*domain.VocabEntry, error) {
-			switch userID {
+		GetActiveVocabByUserIDFn: getActiveVocabByUserID,
+		GetExportEntriesByVocabIDFn: func(vocabID int) ([]*domain.ExportEntry, error) {
+			switch vocabID {
 			case 1:
-				return []*domain.VocabEntry{
-					{ID: 1, Text: "One"},
-					{ID: 2, Text: "Two"},
-				}, nil
+				return exportEntries, nil
 			case 3:
 				return nil, fmt.Errorf("error")
 			default:
@@ -428,15 +548,230 @@ func TestVocabWithLocalRepo_GetEntriesFromUserVocab(t *testing.T) {
 	vocabService := NewVocabWithLocalRepo(mock.Logger{}, mockedRepo, &mock.VocabEntryService{})
 	for _, c := range testCases {
 		t.Run(c.name, func(t *testing.T) {
-			entries, err := vocabService.GetEntriesFromUserVocab(c.userID)
+			entries, err := vocabService.GetExportEntriesFromUserVocab(c.userID)
 			if c.expectErr && err == nil {
 				t.Errorf("Expected error, but got nothing")
 			}
+			if !c.expectErr && err != nil {
+				t.Errorf("Expected no error, but got %s", err)
+			}
 			if !reflect.DeepEqual(c.expectedEntries, entries) {
 				t.Errorf("Expected res:%+v;Actual:%+v", c.expectedEntries, entries)
 			}
-			if !mockedRepo.GetEntriesByUserIDInvoked {
-				t.Errorf("GetEntriesByUserIDInvoked was not invoked")
+			if !mockedRepo.GetExportEntriesByVocabIDInvoked {
+				t.Errorf("GetExportEntriesByVocabID was not invoked")
+			}
+			mockedRepo.Reset()
+		})
+	}
+}
+
+func TestVocabWithLocalRepo_GetEntriesPageFromUserVocab(t *testing.T) {
+	pageEntries := []*domain.VocabEntry{
+		{ID: 3, Text: "Three"},
+		{ID: 4, Text: "Four"},
+	}
+	testCases := []struct {
+		name                 string
+		userID               int
+		number               int
+		expectedPage         *domain.EntriesPage
+		expectedOffset       int
+		expectGetPageInvoked bool
+		expectErr            bool
+	}{
+		{
+			name:                 "Positive",
+			userID:               1,
+			number:               1,
+			expectedPage:         &domain.EntriesPage{Entries: pageEntries, Number: 1, Size: 2, Total: 5},
+			expectedOffset:       2,
+			expectGetPageInvoked: true,
+		},
+		{
+			name:                 "Positive page number is out of range",
+			userID:               1,
+			number:               7,
+			expectedPage:         &domain.EntriesPage{Entries: pageEntries, Number: 2, Size: 2, Total: 5},
+			expectedOffset:       4,
+			expectGetPageInvoked: true,
+		},
+		{
+			name:                 "Positive negative page number",
+			userID:               1,
+			number:               -1,
+			expectedPage:         &domain.EntriesPage{Entries: pageEntries, Number: 0, Size: 2, Total: 5},
+			expectedOffset:       0,
+			expectGetPageInvoked: true,
+		},
+		{
+			name:         "Positive no entries",
+			userID:       2,
+			number:       1,
+			expectedPage: &domain.EntriesPage{Number: 0, Size: 2},
+		},
+		{
+			name:         "User has no vocab",
+			userID:       0,
+			number:       1,
+			expectedPage: &domain.EntriesPage{Number: 0, Size: 2},
+		},
+		{
+			name:      "Count entries returns err",
+			userID:    3,
+			expectErr: true,
+		},
+		{
+			name:                 "Get page of entries returns err",
+			userID:               4,
+			expectGetPageInvoked: true,
+			expectErr:            true,
+		},
+	}
+
+	var offset int
+	mockedRepo := &mock.VocabRepo{
+		GetActiveVocabByUserIDFn: getActiveVocabByUserID,
+		CountEntriesByVocabIDFn: func(vocabID int) (int, error) {
+			switch vocabID {
+			case 1, 4:
+				return 5, nil
+			case 3:
+				return 0, fmt.Errorf("error")
+			default:
+				return 0, nil
+			}
+		},
+		GetEntriesPageByVocabIDFn: func(vocabID, limit, o int) ([]*domain.VocabEntry, error) {
+			offset = o
+			if vocabID == 4 {
+				return nil, fmt.Errorf("error")
+			}
+			return pageEntries, nil
+		},
+	}
+
+	vocabService := NewVocabWithLocalRepo(mock.Logger{}, mockedRepo, &mock.VocabEntryService{})
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			offset = 0
+			page, err := vocabService.GetEntriesPageFromUserVocab(c.userID, c.number, 2)
+			if !c.expectErr && err != nil {
+				t.Errorf("Expected no error, but got %s", err)
+			}
+			if c.expectErr && err == nil {
+				t.Errorf("Expected error, but got nothing")
+			}
+			if !reflect.DeepEqual(c.expectedPage, page) {
+				t.Errorf("Expected res:%+v;Actual:%+v", c.expectedPage, page)
+			}
+			if c.expectGetPageInvoked != mockedRepo.GetEntriesPageByVocabIDInvoked {
+				t.Errorf("GetEntriesPageByVocabID invoked:%v;Expected:%v",
+					mockedRepo.GetEntriesPageByVocabIDInvoked, c.expectGetPageInvoked)
+			}
+			if c.expectGetPageInvoked && !c.expectErr && offset != c.expectedOffset {
+				t.Errorf("Expected offset:%v;Actual:%v", c.expectedOffset, offset)
+			}
+			mockedRepo.Reset()
+		})
+	}
+}
+
+func TestVocabWithLocalRepo_FindEntriesInUserVocab(t *testing.T) {
+	foundEntries := []*domain.VocabEntry{
+		{ID: 1, Text: "hedgehog"},
+		{ID: 2, Text: "hello"},
+	}
+	testCases := []struct {
+		name              string
+		userID            int
+		query             string
+		number            int
+		expectedPage      *domain.EntriesPage
+		expectFindInvoked bool
+		expectErr         bool
+	}{
+		{
+			name:              "Positive",
+			userID:            1,
+			query:             "he",
+			expectedPage:      &domain.EntriesPage{Entries: foundEntries, Number: 0, Size: 2, Total: 2},
+			expectFindInvoked: true,
+		},
+		{
+			name:              "Positive page number is out of range",
+			userID:            1,
+			query:             "he",
+			number:            3,
+			expectedPage:      &domain.EntriesPage{Entries: foundEntries, Number: 0, Size: 2, Total: 2},
+			expectFindInvoked: true,
+		},
+		{
+			name:         "Positive nothing found",
+			userID:       1,
+			query:        "xyz",
+			expectedPage: &domain.EntriesPage{Number: 0, Size: 2},
+		},
+		{
+			name:         "User has no vocab",
+			userID:       0,
+			query:        "he",
+			expectedPage: &domain.EntriesPage{Number: 0, Size: 2},
+		},
+		{
+			name:      "Count found entries returns err",
+			userID:    2,
+			query:     "he",
+			expectErr: true,
+		},
+		{
+			name:              "Find entries returns err",
+			userID:            3,
+			query:             "he",
+			expectFindInvoked: true,
+			expectErr:         true,
+		},
+	}
+
+	mockedRepo := &mock.VocabRepo{
+		GetActiveVocabByUserIDFn: getActiveVocabByUserID,
+		CountFoundEntriesByVocabIDFn: func(vocabID int, query string) (int, error) {
+			switch {
+			case vocabID == 2:
+				return 0, fmt.Errorf("error")
+			case query == "he":
+				return 2, nil
+			default:
+				return 0, nil
+			}
+		},
+		FindEntriesByVocabIDFn: func(vocabID int, query string, limit, offset int) ([]*domain.VocabEntry, error) {
+			if vocabID == 3 {
+				return nil, fmt.Errorf("error")
+			}
+			if query != "he" || limit != 2 || offset != 0 {
+				return nil, fmt.Errorf("unexpected args: %s, %v, %v", query, limit, offset)
+			}
+			return foundEntries, nil
+		},
+	}
+
+	vocabService := NewVocabWithLocalRepo(mock.Logger{}, mockedRepo, &mock.VocabEntryService{})
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			page, err := vocabService.FindEntriesInUserVocab(c.userID, c.query, c.number, 2)
+			if !c.expectErr && err != nil {
+				t.Errorf("Expected no error, but got %s", err)
+			}
+			if c.expectErr && err == nil {
+				t.Errorf("Expected error, but got nothing")
+			}
+			if !reflect.DeepEqual(c.expectedPage, page) {
+				t.Errorf("Expected res:%+v;Actual:%+v", c.expectedPage, page)
+			}
+			if c.expectFindInvoked != mockedRepo.FindEntriesByVocabIDInvoked {
+				t.Errorf("FindEntriesByVocabID invoked:%v;Expected:%v",
+					mockedRepo.FindEntriesByVocabIDInvoked, c.expectFindInvoked)
 			}
 			mockedRepo.Reset()
 		})
@@ -482,19 +817,20 @@ func TestVocabWithLocalRepo_RemoveEntryFromUserVocab(t *testing.T) {
 	}
 
 	mockedRepo := &mock.VocabRepo{
-		CheckEntryInUserVocabFn: func(entryID, userID int) (bool, error) {
+		GetActiveVocabByUserIDFn: getActiveVocabByUserID,
+		CheckEntryInVocabFn: func(entryID, vocabID int) (bool, error) {
 			switch {
-			case entryID == 2 && userID == 2:
+			case entryID == 2 && vocabID == 2:
 				return false, nil
-			case entryID == 3 && userID == 3:
+			case entryID == 3 && vocabID == 3:
 				return false, fmt.Errorf("error")
 			default:
 				return true, nil
 			}
 		},
-		RemoveEntryFromUserVocabFn: func(entryID, userID int) error {
+		RemoveEntryFromVocabFn: func(entryID, vocabID int) error {
 			switch {
-			case entryID == 4 && userID == 4:
+			case entryID == 4 && vocabID == 4:
 				return fmt.Errorf("error")
 			default:
 				return nil
@@ -509,11 +845,11 @@ func TestVocabWithLocalRepo_RemoveEntryFromUserVocab(t *testing.T) {
 			if c.expectErr == false && err != nil {
 				t.Errorf("Expected no error, but got %s", err)
 			}
-			if c.expectCheckEntryInv != mockedRepo.CheckEntryInUserVocabInvoked {
-				t.Errorf("Actual invocation of CheckEntryInUserVocab(%v) doesn't match expectations", mockedRepo.CheckEntryInUserVocabInvoked)
+			if c.expectCheckEntryInv != mockedRepo.CheckEntryInVocabInvoked {
+				t.Errorf("Actual invocation of CheckEntryInVocab(%v) doesn't match expectations", mockedRepo.CheckEntryInVocabInvoked)
 			}
-			if c.expectRemoveEntryInv != mockedRepo.RemoveEntryFromUserVocabInvoked {
-				t.Errorf("Actual invocation of RemoveEntryFromUserVocab(%v) doesn't match expectations", mockedRepo.RemoveEntryFromUserVocabInvoked)
+			if c.expectRemoveEntryInv != mockedRepo.RemoveEntryFromVocabInvoked {
+				t.Errorf("Actual invocation of RemoveEntryFromVocab(%v) doesn't match expectations", mockedRepo.RemoveEntryFromVocabInvoked)
 			}
 			if c.expectErr && err == nil {
 				t.Errorf("Expected error, but got nothing")
@@ -524,6 +860,7 @@ func TestVocabWithLocalRepo_RemoveEntryFromUserVocab(t *testing.T) {
 }
 
 func TestVocabWithLocalRepo_GetVocabEntryByText(t *testing.T) {
+	langPair := domain.LangPair{Source: "de", Target: "ru"}
 	testCases := []struct {
 		text                      string
 		expectedEntry             *domain.VocabEntry
@@ -535,7 +872,16 @@ func TestVocabWithLocalRepo_GetVocabEntryByText(t *testing.T) {
 		{
 			text: "Positive: found in local repo",
 			expectedEntry: &domain.VocabEntry{
-				Text: "Positive: found in local repo",
+				Text:     "Positive: found in local repo",
+				LangPair: langPair,
+			},
+			expectLocalGetByTextInv: true,
+		},
+		{
+			text: "Positive: found in local repo by alias",
+			expectedEntry: &domain.VocabEntry{
+				Text:     "headword",
+				LangPair: langPair,
 			},
 			expectLocalGetByTextInv: true,
 		},
@@ -547,7 +893,8 @@ func TestVocabWithLocalRepo_GetVocabEntryByText(t *testing.T) {
 		{
 			text: "Positive: found in the entry service",
 			expectedEntry: &domain.VocabEntry{
-				Text: "Positive: found in the entry service",
+				Text:     "Positive: found in the entry service",
+				LangPair: langPair,
 			},
 			expectLocalGetByTextInv:   true,
 			expectServiceGetByTextInv: true,
@@ -574,20 +921,26 @@ func TestVocabWithLocalRepo_GetVocabEntryByText(t *testing.T) {
 	}
 
 	mockedRepo := &mock.VocabRepo{
-		GetVocabEntryByTextFn: func(text string) (*domain.VocabEntry, error) {
+		GetVocabEntryByTextFn: func(text string, langPair domain.LangPair) (*domain.VocabEntry, error) {
 			switch text {
 			case "Positive: found in local repo":
-				return &domain.VocabEntry{Text: text}, nil
+				return &domain.VocabEntry{Text: text, LangPair: langPair}, nil
 			case "Local GetVocabEntryByText returns error":
 				return nil, fmt.Errorf("error")
 			default:
 				return nil, nil
 			}
 		},
+		GetVocabEntryByAliasFn: func(alias string, langPair domain.LangPair) (*domain.VocabEntry, error) {
+			if alias == "Positive: found in local repo by alias" {
+				return &domain.VocabEntry{Text: "headword", LangPair: langPair}, nil
+			}
+			return nil, nil
+		},
 		AddVocabEntryFn: func(entry *domain.VocabEntry) (*domain.VocabEntry, error) {
 			switch entry.Text {
 			case "Positive: found in the entry service":
-				return &domain.VocabEntry{Text: entry.Text}, nil
+				return &domain.VocabEntry{Text: entry.Text, LangPair: entry.LangPair}, nil
 			case "Local AddVocabEntry returns error":
 				return nil, fmt.Errorf("error")
 			default:
@@ -596,10 +949,10 @@ func TestVocabWithLocalRepo_GetVocabEntryByText(t *testing.T) {
 		},
 	}
 	mockedEntryService := &mock.VocabEntryService{
-		GetVocabEntryByTextFn: func(text string) (*domain.VocabEntry, error) {
+		GetVocabEntryByTextFn: func(text string, langPair domain.LangPair) (*domain.VocabEntry, error) {
 			switch text {
 			case "Positive: found in the entry service", "Local AddVocabEntry returns error":
-				return &domain.VocabEntry{Text: text}, nil
+				return &domain.VocabEntry{Text: text, LangPair: langPair}, nil
 			case "Entry service GetVocabEntryByText returns error":
 				return nil, fmt.Errorf("error")
 			default:
@@ -611,7 +964,7 @@ func TestVocabWithLocalRepo_GetVocabEntryByText(t *testing.T) {
 	vocabService := NewVocabWithLocalRepo(mock.Logger{}, mockedRepo, mockedEntryService)
 	for _, c := range testCases {
 		t.Run(c.text, func(t *testing.T) {
-			entry, err := vocabService.GetVocabEntryByText(c.text)
+			entry, err := vocabService.GetVocabEntryByText(c.text, langPair)
 			if c.expectErr == false && err != nil {
 				t.Errorf("Expected no error, but got %s", err)
 			}
@@ -645,6 +998,264 @@ func TestVocabWithLocalRepo_GetVocabEntryByText(t *testing.T) {
 	}
 }
 
+func TestVocabWithLocalRepo_GetVocabEntryByText_Lemma(t *testing.T) {
+	langPair := domain.LangPair{Source: "en", Target: "ru"}
+	testCases := []struct {
+		text                string
+		expectedEntry       *domain.VocabEntry
+		expectedServiceArgs []string
+		expectedAliases     []string
+		expectErr           bool
+	}{
+		{
+			text:                "went",
+			expectedEntry:       &domain.VocabEntry{ID: 1, Text: "go", LangPair: langPair},
+			expectedServiceArgs: []string{"went"},
+			// lemma found in the local repo
+			expectedAliases: []string{"went:1"},
+		},
+		{
+			text:                "mice",
+			expectedEntry:       &domain.VocabEntry{ID: 2, Text: "mouse", LangPair: langPair},
+			expectedServiceArgs: []string{"mice", "mouse"},
+			expectedAliases:     []string{"mice:2"},
+		},
+		{
+			text:                "running",
+			expectedEntry:       &domain.VocabEntry{ID: 3, Text: "running", LangPair: langPair},
+			expectedServiceArgs: []string{"running"},
+		},
+		{
+			text:          "building",
+			expectedEntry: &domain.VocabEntry{ID: 5, Text: "building", LangPair: langPair},
+			// found by itself though the lemma "build" is in the local repo
+			expectedServiceArgs: []string{"building"},
+		},
+		{
+			text:                "hedgehog",
+			expectedServiceArgs: []string{"hedgehog"},
+		},
+		{
+			text:          "porcupines",
+			expectedEntry: &domain.VocabEntry{ID: 7, Text: "porcupine", LangPair: langPair},
+			// the lemma is only a guess, so it isn't linked
+			expectedServiceArgs: []string{"porcupines", "porcupine"},
+		},
+		{
+			text:                "knives",
+			expectedEntry:       nil,
+			expectedServiceArgs: []string{"knives"},
+			// alias can't be added
+			expectedAliases: []string{"knives:4"},
+			expectErr:       true,
+		},
+	}
+	for _, c := range testCases {
+		t.Run(c.text, func(t *testing.T) {
+			var serviceArgs, aliases []string
+			mockedRepo := &mock.VocabRepo{
+				GetVocabEntryByTextFn: func(text string, langPair domain.LangPair) (*domain.VocabEntry, error) {
+					switch text {
+					case "go":
+						return &domain.VocabEntry{ID: 1, Text: text, LangPair: langPair}, nil
+					case "knife":
+						return &domain.VocabEntry{ID: 4, Text: text, LangPair: langPair}, nil
+					case "build":
+						return &domain.VocabEntry{ID: 6, Text: text, LangPair: langPair}, nil
+					default:
+						return nil, nil
+					}
+				},
+				GetVocabEntryByAliasFn: func(alias string, langPair domain.LangPair) (*domain.VocabEntry, error) {
+					return nil, nil
+				},
+				AddVocabEntryAliasFn: func(alias string, entryID int) error {
+					aliases = append(aliases, fmt.Sprintf("%s:%v", alias, entryID))
+					if alias == "knives" {
+						return fmt.Errorf("error")
+					}
+					return nil
+				},
+				AddVocabEntryFn: func(entry *domain.VocabEntry) (*domain.VocabEntry, error) {
+					ids := map[string]int{"mouse": 2, "running": 3, "building": 5, "porcupine": 7}
+					return &domain.VocabEntry{ID: ids[entry.Text], Text: entry.Text, LangPair: entry.LangPair}, nil
+				},
+			}
+			mockedEntryService := &mock.VocabEntryService{
+				GetVocabEntryByTextFn: func(text string, langPair domain.LangPair) (*domain.VocabEntry, error) {
+					serviceArgs = append(serviceArgs, text)
+					switch text {
+					case "mouse", "running", "building", "porcupine":
+						return &domain.VocabEntry{Text: text, LangPair: langPair}, nil
+					default:
+						return nil, nil
+					}
+				},
+			}
+			vocabService := NewVocabWithLocalRepo(mock.Logger{}, mockedRepo, mockedEntryService)
+			entry, err := vocabService.GetVocabEntryByText(c.text, langPair)
+			if c.expectErr == false && err != nil {
+				t.Errorf("Expected no error, but got %s", err)
+			}
+			if c.expectErr && err == nil {
+				t.Errorf("Expected error, but got nothing")
+			}
+			if !reflect.DeepEqual(c.expectedEntry, entry) {
+				t.Errorf("Expected entry:%v;Actual:%v", c.expectedEntry, entry)
+			}
+			if !reflect.DeepEqual(c.expectedServiceArgs, serviceArgs) {
+				t.Errorf("Expected entry service lookups:%v;Actual:%v", c.expectedServiceArgs, serviceArgs)
+			}
+			if !reflect.DeepEqual(c.expectedAliases, aliases) {
+				t.Errorf("Expected aliases:%v;Actual:%v", c.expectedAliases, aliases)
+			}
+		})
+	}
+}
+
+func TestVocabWithLocalRepo_AddCustomVocabEntry(t *testing.T) {
+	langPair := domain.LangPair{Source: "en", Target: "ru"}
+	userID := 1
+	testCases := []struct {
+		text                          string
+		expectedEntry                 *domain.VocabEntry
+		expectLocalGetByTextInv       bool
+		expectLocalGetCustomByTextInv bool
+		expectLocalAddInv             bool
+		expectErr                     bool
+	}{
+		{
+			text: "Positive: found in local repo",
+			expectedEntry: &domain.VocabEntry{
+				Text:     "Positive: found in local repo",
+				LangPair: langPair,
+			},
+			expectLocalGetByTextInv: true,
+		},
+		{
+			text: "Positive: user's custom entry found in local repo",
+			expectedEntry: &domain.VocabEntry{
+				Text:     "Positive: user's custom entry found in local repo",
+				LangPair: langPair,
+				Source:   domain.CustomEntrySource,
+				UserID:   userID,
+			},
+			expectLocalGetByTextInv:       true,
+			expectLocalGetCustomByTextInv: true,
+		},
+		{
+			text: "Positive: added to local repo",
+			expectedEntry: &domain.VocabEntry{
+				ID:              1,
+				Text:            "Positive: added to local repo",
+				LangPair:        langPair,
+				Source:          domain.CustomEntrySource,
+				UserID:          userID,
+				MainTranslation: "перевод",
+				Translations:    []*domain.Translation{{Text: "перевод"}},
+			},
+			expectLocalGetByTextInv:       true,
+			expectLocalGetCustomByTextInv: true,
+			expectLocalAddInv:             true,
+		},
+		{
+			text:                    "Local GetVocabEntryByText returns error",
+			expectLocalGetByTextInv: true,
+			expectErr:               true,
+		},
+		{
+			text:                          "Local GetCustomVocabEntryByText returns error",
+			expectLocalGetByTextInv:       true,
+			expectLocalGetCustomByTextInv: true,
+			expectErr:                     true,
+		},
+		{
+			text:                          "Local AddVocabEntry returns error",
+			expectLocalGetByTextInv:       true,
+			expectLocalGetCustomByTextInv: true,
+			expectLocalAddInv:             true,
+			expectErr:                     true,
+		},
+	}
+
+	mockedRepo := &mock.VocabRepo{
+		GetVocabEntryByTextFn: func(text string, langPair domain.LangPair) (*domain.VocabEntry, error) {
+			switch text {
+			case "Positive: found in local repo":
+				return &domain.VocabEntry{Text: text, LangPair: langPair}, nil
+			case "Local GetVocabEntryByText returns error":
+				return nil, fmt.Errorf("error")
+			default:
+				return nil, nil
+			}
+		},
+		GetCustomVocabEntryByTextFn: func(
+			text string,
+			langPair domain.LangPair,
+			userID int,
+		) (*domain.VocabEntry, error) {
+			switch text {
+			case "Positive: user's custom entry found in local repo":
+				return &domain.VocabEntry{
+					Text:     text,
+					LangPair: langPair,
+					Source:   domain.CustomEntrySource,
+					UserID:   userID,
+				}, nil
+			case "Local GetCustomVocabEntryByText returns error":
+				return nil, fmt.Errorf("error")
+			default:
+				return nil, nil
+			}
+		},
+		AddVocabEntryFn: func(entry *domain.VocabEntry) (*domain.VocabEntry, error) {
+			if entry.Text == "Local AddVocabEntry returns error" {
+				return nil, fmt.Errorf("error")
+			}
+			entry.ID = 1
+			return entry, nil
+		},
+	}
+
+	vocabService := NewVocabWithLocalRepo(mock.Logger{}, mockedRepo, &mock.VocabEntryService{})
+	for _, c := range testCases {
+		t.Run(c.text, func(t *testing.T) {
+			entry, err := vocabService.AddCustomVocabEntry(c.text, "перевод", langPair, userID)
+			if c.expectErr == false && err != nil {
+				t.Errorf("Expected no error, but got %s", err)
+			}
+			if c.expectErr && err == nil {
+				t.Errorf("Expected error, but got nothing")
+			}
+			if c.expectLocalGetByTextInv != mockedRepo.GetVocabEntryByTextInvoked {
+				t.Errorf(
+					"Actual invocation of local repo GetVocabEntryByText(%v) doesn't match expectations",
+					mockedRepo.GetVocabEntryByTextInvoked,
+				)
+			}
+			if c.expectLocalGetCustomByTextInv != mockedRepo.GetCustomVocabEntryByTextInvoked {
+				t.Errorf(
+					"Actual invocation of local repo GetCustomVocabEntryByText(%v) doesn't match expectations",
+					mockedRepo.GetCustomVocabEntryByTextInvoked,
+				)
+			}
+			if c.expectLocalAddInv != mockedRepo.AddVocabEntryInvoked {
+				t.Errorf(
+					"Actual invocation of local repo AddVocabEntry(%v) doesn't match expectations",
+					mockedRepo.AddVocabEntryInvoked,
+				)
+			}
+			if c.expectedEntry == nil && entry != nil {
+				t.Errorf("Nil entry expected")
+			}
+			if c.expectedEntry != nil && !reflect.DeepEqual(c.expectedEntry, entry) {
+				t.Errorf("Expected entry:%+v;Actual:%+v", c.expectedEntry, entry)
+			}
+			mockedRepo.Reset()
+		})
+	}
+}
+
 func TestVocabWithLocalRepo_GetVocabEntryByID(t *testing.T) {
 	testCases := []struct {
 		name          string
@@ -692,3 +1303,12 @@ func TestVocabWithLocalRepo_GetVocabEntryByID(t *testing.T) {
 		})
 	}
 }
+
+// getActiveVocabByUserID returns the user's active vocab with the same ID as the user has.
+// Users with zero ID have no vocab.
+func getActiveVocabByUserID(userID int) (*domain.Vocab, error) {
+	if userID == 0 {
+		return nil, nil
+	}
+	return &domain.Vocab{ID: userID, UserID: userID, Active: true}, nil
+}
@@ -0,0 +1,252 @@
+package service
+
+import (
+	"fmt"
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"github.com/dmalyar/pimpmyvocab/log"
+	"github.com/dmalyar/pimpmyvocab/repo"
+	"math/rand"
+	"time"
+)
+
+// QuizWithLocalRepo implements service.Quiz interface for working with local repository.
+type QuizWithLocalRepo struct {
+	logger       log.Logger
+	localRepo    repo.Vocab
+	scheduleRepo repo.Schedule
+	sessionRepo  repo.QuizSession
+	scheduler    Scheduler
+	now          func() time.Time
+}
+
+func NewQuizWithLocalRepo(
+	logger log.Logger,
+	localRepo repo.Vocab,
+	scheduleRepo repo.Schedule,
+	sessionRepo repo.QuizSession,
+	scheduler Scheduler,
+) *QuizWithLocalRepo {
+	return &QuizWithLocalRepo{
+		logger:       logger,
+		localRepo:    localRepo,
+		scheduleRepo: scheduleRepo,
+		sessionRepo:  sessionRepo,
+		scheduler:    scheduler,
+		now:          time.Now,
+	}
+}
+
+// GetChoiceQuestion returns a question about the next entry to review with up to optionsQnt options in random order.
+// Wrong options are taken from other entries of the user's vocab, the ones with the same class
+// of the main translation are preferred.
+// If there is no entry in the user's vocab then returns nil.
+// If there are no other entries to take wrong options from then returns question with the only option.
+func (q *QuizWithLocalRepo) GetChoiceQuestion(userID, previousEntryID, optionsQnt int) (*domain.ChoiceQuestion, error) {
+	logger := q.logger.WithFields(map[string]interface{}{
+		"userID":          userID,
+		"previousEntryID": previousEntryID,
+		"optionsQnt":      optionsQnt,
+	})
+	logger.Debug("Getting choice question")
+	entry, err := q.scheduler.GetNextEntryToReview(userID, previousEntryID, domain.DirectionForward)
+	if err != nil {
+		return nil, err
+	}
+	if entry == nil {
+		logger.Info("User's vocab is empty")
+		return nil, nil
+	}
+	vocab, err := q.localRepo.GetActiveVocabByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("getting active vocab by user ID: %s", err)
+	}
+	if vocab == nil {
+		return nil, fmt.Errorf("active vocab of the user not found")
+	}
+	entries, err := q.localRepo.GetEntriesByVocabID(vocab.ID)
+	if err != nil {
+		return nil, fmt.Errorf("getting vocab entries by vocab ID: %s", err)
+	}
+	question := &domain.ChoiceQuestion{
+		Entry:   entry,
+		Options: append(pickDistractors(entry, entries, optionsQnt-1), entry),
+	}
+	rand.Shuffle(len(question.Options), func(i, j int) {
+		question.Options[i], question.Options[j] = question.Options[j], question.Options[i]
+	})
+	logger.WithField("question", question).Info("Choice question created")
+	return question, nil
+}
+
+// pickDistractors returns up to qnt random entries with main translations different from the entry's one.
+// Entries with the same class of the main translation go first.
+func pickDistractors(entry *domain.VocabEntry, candidates []*domain.VocabEntry, qnt int) []*domain.VocabEntry {
+	if qnt <= 0 {
+		return nil
+	}
+	var sameClass, otherClass []*domain.VocabEntry
+	usedTranslations := map[string]struct{}{entry.MainTranslation: {}}
+	for _, i := range rand.Perm(len(candidates)) {
+		c := candidates[i]
+		if _, ok := usedTranslations[c.MainTranslation]; ok || c.ID == entry.ID {
+			continue
+		}
+		usedTranslations[c.MainTranslation] = struct{}{}
+		if c.MainClass() == entry.MainClass() {
+			sameClass = append(sameClass, c)
+		} else {
+			otherClass = append(otherClass, c)
+		}
+	}
+	distractors := append(sameClass, otherClass...)
+	if len(distractors) > qnt {
+		distractors = distractors[:qnt]
+	}
+	return distractors
+}
+
+// StartQuizSession starts a new quiz session of up to size most overdue in the given direction entries
+// from the user's vocab. The user's active session, if any, is finished.
+// If there is no entry in the user's vocab then returns nil.
+func (q *QuizWithLocalRepo) StartQuizSession(userID int, direction domain.Direction, size int) (*domain.QuizSession, error) {
+	logger := q.logger.WithFields(map[string]interface{}{
+		"userID":    userID,
+		"direction": direction,
+		"size":      size,
+	})
+	logger.Debug("Starting quiz session")
+	entryIDs, err := q.scheduleRepo.GetEntryIDsToReview(userID, direction)
+	if err != nil {
+		return nil, fmt.Errorf("getting entry IDs to review: %s", err)
+	}
+	if len(entryIDs) == 0 {
+		logger.Info("User's vocab is empty")
+		return nil, nil
+	}
+	if len(entryIDs) > size {
+		entryIDs = entryIDs[:size]
+	}
+	session := &domain.QuizSession{
+		Direction: direction,
+		Entries:   make([]*domain.QuizSessionEntry, 0, len(entryIDs)),
+		StartedAt: q.now(),
+	}
+	for _, id := range entryIDs {
+		session.Entries = append(session.Entries, &domain.QuizSessionEntry{EntryID: id})
+	}
+	session, err = q.sessionRepo.AddQuizSession(session, userID)
+	if err != nil {
+		return nil, fmt.Errorf("adding quiz session: %s", err)
+	}
+	logger.WithField("quizSession", session).Info("Quiz session started")
+	return session, nil
+}
+
+// AnswerQuizSessionEntry reviews the entry with the given grade and records the answer in the user's active session.
+// The session is finished when all its entries are answered.
+// Returns nil without reviewing the entry if it's not the next entry waiting for an answer in the active session,
+// e.g. the answer is given to a question from the finished session or the same answer is given twice.
+// The entry is graded before it's reviewed, so only one of the concurrent answers to it is reviewed.
+func (q *QuizWithLocalRepo) AnswerQuizSessionEntry(
+	entryID, userID int,
+	direction domain.Direction,
+	grade domain.Grade,
+) (*domain.QuizSession, error) {
+	logger := q.logger.WithFields(map[string]interface{}{
+		"entryID":   entryID,
+		"userID":    userID,
+		"direction": direction,
+		"grade":     grade,
+	})
+	logger.Debug("Answering quiz session entry")
+	session, err := q.sessionRepo.GetActiveQuizSession(userID)
+	if err != nil {
+		return nil, fmt.Errorf("getting active quiz session: %s", err)
+	}
+	if session == nil {
+		logger.Info("User has no active quiz session")
+		return nil, nil
+	}
+	if nextEntryID, ok := session.NextEntryID(); session.Direction != direction || !ok || nextEntryID != entryID {
+		logger.Info("Entry is not waiting for an answer in the active quiz session")
+		return nil, nil
+	}
+	graded, err := q.sessionRepo.GradeQuizSessionEntry(session.ID, entryID, grade)
+	if err != nil {
+		return nil, fmt.Errorf("grading quiz session entry: %s", err)
+	}
+	if !graded {
+		logger.Info("Entry is already answered in the active quiz session")
+		return nil, nil
+	}
+	err = q.scheduler.ReviewEntry(entryID, userID, direction, grade)
+	if err != nil {
+		return nil, err
+	}
+	session.Entry(entryID).Grade = &grade
+	if _, ok := session.NextEntryID(); !ok {
+		err = q.finishQuizSession(session)
+		if err != nil {
+			return nil, err
+		}
+	}
+	logger.WithField("quizSession", session).Info("Quiz session entry answered")
+	return session, nil
+}
+
+// StopQuizSession finishes the user's active session before all its entries are answered.
+// Returns nil if the user has no active session.
+func (q *QuizWithLocalRepo) StopQuizSession(userID int) (*domain.QuizSession, error) {
+	logger := q.logger.WithField("userID", userID)
+	logger.Debug("Stopping quiz session")
+	session, err := q.sessionRepo.GetActiveQuizSession(userID)
+	if err != nil {
+		return nil, fmt.Errorf("getting active quiz session: %s", err)
+	}
+	if session == nil {
+		logger.Info("User has no active quiz session")
+		return nil, nil
+	}
+	err = q.finishQuizSession(session)
+	if err != nil {
+		return nil, err
+	}
+	logger.WithField("quizSession", session).Info("Quiz session stopped")
+	return session, nil
+}
+
+func (q *QuizWithLocalRepo) finishQuizSession(session *domain.QuizSession) error {
+	finishedAt := q.now()
+	err := q.sessionRepo.FinishQuizSession(session.ID, finishedAt)
+	if err != nil {
+		return fmt.Errorf("finishing quiz session: %s", err)
+	}
+	session.FinishedAt = &finishedAt
+	return nil
+}
+
+// GetQuizSummary returns known and missed entries of the session in the order they were asked.
+// Entries which were not answered are not included.
+func (q *QuizWithLocalRepo) GetQuizSummary(session *domain.QuizSession) (*domain.QuizSummary, error) {
+	logger := q.logger.WithField("quizSession", session)
+	logger.Debug("Getting quiz summary")
+	summary := new(domain.QuizSummary)
+	for _, e := range session.Entries {
+		if e.Grade == nil {
+			continue
+		}
+		entry, err := q.localRepo.GetVocabEntryByID(e.EntryID)
+		if err != nil {
+			return nil, fmt.Errorf("getting vocab entry by ID: %s", err)
+		}
+		if entry == nil {
+			continue
+		}
+		if *e.Grade == domain.GradeAgain {
+			summary.Missed = append(summary.Missed, entry)
+		} else {
+			summary.Known = append(summary.Known, entry)
+		}
+	}
+	return summary, nil
+}
@@ -0,0 +1,503 @@
+package service
+
+import (
+	"fmt"
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"github.com/dmalyar/pimpmyvocab/mock"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestQuizWithLocalRepo_GetChoiceQuestion(t *testing.T) {
+	testCases := []struct {
+		name                    string
+		userID, previousEntryID int
+		expectedEntryID         int
+		expectedOptionIDs       map[int]struct{}
+		expectErr               bool
+		expectGetEntriesInv     bool
+	}{
+		{
+			name:                "Positive same class options preferred",
+			userID:              1,
+			expectedEntryID:     1,
+			expectedOptionIDs:   map[int]struct{}{1: {}, 2: {}, 3: {}, 4: {}},
+			expectGetEntriesInv: true,
+		},
+		{
+			name:                "Positive not enough same class options",
+			userID:              2,
+			expectedEntryID:     1,
+			expectedOptionIDs:   map[int]struct{}{1: {}, 2: {}, 5: {}, 7: {}},
+			expectGetEntriesInv: true,
+		},
+		{
+			name:                "Positive single entry",
+			userID:              3,
+			expectedEntryID:     1,
+			expectedOptionIDs:   map[int]struct{}{1: {}},
+			expectGetEntriesInv: true,
+		},
+		{
+			name:   "Positive no entries",
+			userID: 4,
+		},
+		{
+			name:      "Get next entry returns error",
+			userID:    5,
+			expectErr: true,
+		},
+		{
+			name:                "Get entries returns error",
+			userID:              6,
+			expectErr:           true,
+			expectGetEntriesInv: true,
+		},
+	}
+
+	entry := newTestEntry(1, "cat", "кошка", "noun")
+	mockedScheduler := &mock.SchedulerService{
+		GetNextEntryToReviewFn: func(userID, previousEntryID int, direction domain.Direction) (*domain.VocabEntry, error) {
+			switch userID {
+			case 4:
+				return nil, nil
+			case 5:
+				return nil, fmt.Errorf("error")
+			default:
+				return entry, nil
+			}
+		},
+	}
+	mockedRepo := &mock.VocabRepo{
+		GetActiveVocabByUserIDFn: func(userID int) (*domain.Vocab, error) {
+			return &domain.Vocab{ID: userID, UserID: userID, Active: true}, nil
+		},
+		GetEntriesByVocabIDFn: func(vocabID int) ([]*domain.VocabEntry, error) {
+			switch vocabID {
+			case 1:
+				return []*domain.VocabEntry{
+					entry,
+					newTestEntry(2, "dog", "собака", "noun"),
+					newTestEntry(3, "cow", "корова", "noun"),
+					newTestEntry(4, "pig", "свинья", "noun"),
+					newTestEntry(5, "run", "бежать", "verb"),
+					newTestEntry(6, "kitty", "кошка", "noun"),
+				}, nil
+			case 2:
+				return []*domain.VocabEntry{
+					entry,
+					newTestEntry(2, "dog", "собака", "noun"),
+					newTestEntry(5, "run", "бежать", "verb"),
+					newTestEntry(6, "kitty", "кошка", "noun"),
+					newTestEntry(7, "red", "красный", "adjective"),
+				}, nil
+			case 3:
+				return []*domain.VocabEntry{entry}, nil
+			case 6:
+				return nil, fmt.Errorf("error")
+			default:
+				return nil, nil
+			}
+		},
+	}
+
+	quizService := NewQuizWithLocalRepo(mock.Logger{}, mockedRepo, &mock.ScheduleRepo{}, &mock.QuizSessionRepo{}, mockedScheduler)
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			question, err := quizService.GetChoiceQuestion(c.userID, c.previousEntryID, 4)
+			if !c.expectErr && err != nil {
+				t.Errorf("Expected no error, but got %s", err)
+			}
+			if c.expectErr && err == nil {
+				t.Errorf("Expected error, but got nothing")
+			}
+			if c.expectGetEntriesInv != mockedRepo.GetEntriesByVocabIDInvoked {
+				t.Errorf("Actual invocation of GetEntriesByVocabID(%v) doesn't match expectations", mockedRepo.GetEntriesByVocabIDInvoked)
+			}
+			if c.expectedOptionIDs == nil && question != nil {
+				t.Errorf("Nil question expected")
+			}
+			if c.expectedOptionIDs != nil {
+				if question == nil {
+					t.Fatalf("Not nil question expected")
+				}
+				if question.Entry.ID != c.expectedEntryID {
+					t.Errorf("Expected entry ID:%v;Actual:%v", c.expectedEntryID, question.Entry.ID)
+				}
+				optionIDs := make(map[int]struct{})
+				for _, o := range question.Options {
+					optionIDs[o.ID] = struct{}{}
+				}
+				if len(optionIDs) != len(question.Options) || len(optionIDs) != len(c.expectedOptionIDs) {
+					t.Errorf("Expected %v distinct options, but got %v", len(c.expectedOptionIDs), len(question.Options))
+				}
+				for id := range c.expectedOptionIDs {
+					if _, ok := optionIDs[id]; !ok {
+						t.Errorf("Expected option with ID %v not found", id)
+					}
+				}
+			}
+			mockedRepo.Reset()
+			mockedScheduler.Reset()
+		})
+	}
+}
+
+func TestQuizWithLocalRepo_StartQuizSession(t *testing.T) {
+	testCases := []struct {
+		name                string
+		userID              int
+		expectedEntryIDs    []int
+		expectErr           bool
+		expectAddSessionInv bool
+	}{
+		{
+			name:                "Positive session is limited by size",
+			userID:              1,
+			expectedEntryIDs:    []int{3, 1, 2},
+			expectAddSessionInv: true,
+		},
+		{
+			name:                "Positive vocab is smaller than size",
+			userID:              2,
+			expectedEntryIDs:    []int{2, 1},
+			expectAddSessionInv: true,
+		},
+		{
+			name:   "Positive no entries",
+			userID: 3,
+		},
+		{
+			name:      "Get entry IDs returns error",
+			userID:    4,
+			expectErr: true,
+		},
+		{
+			name:                "Add session returns error",
+			userID:              5,
+			expectErr:           true,
+			expectAddSessionInv: true,
+		},
+	}
+
+	mockedScheduleRepo := &mock.ScheduleRepo{
+		GetEntryIDsToReviewFn: func(userID int, direction domain.Direction) ([]int, error) {
+			switch userID {
+			case 1:
+				return []int{3, 1, 2, 4}, nil
+			case 2:
+				return []int{2, 1}, nil
+			case 4:
+				return nil, fmt.Errorf("error")
+			case 5:
+				return []int{1}, nil
+			default:
+				return nil, nil
+			}
+		},
+	}
+	mockedSessionRepo := &mock.QuizSessionRepo{
+		AddQuizSessionFn: func(session *domain.QuizSession, userID int) (*domain.QuizSession, error) {
+			if userID == 5 {
+				return nil, fmt.Errorf("error")
+			}
+			session.ID = 1
+			return session, nil
+		},
+	}
+
+	quizService := NewQuizWithLocalRepo(mock.Logger{}, &mock.VocabRepo{}, mockedScheduleRepo, mockedSessionRepo, &mock.SchedulerService{})
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			session, err := quizService.StartQuizSession(c.userID, domain.DirectionReverse, 3)
+			if !c.expectErr && err != nil {
+				t.Errorf("Expected no error, but got %s", err)
+			}
+			if c.expectErr && err == nil {
+				t.Errorf("Expected error, but got nothing")
+			}
+			if c.expectAddSessionInv != mockedSessionRepo.AddQuizSessionInvoked {
+				t.Errorf("Actual invocation of AddQuizSession(%v) doesn't match expectations", mockedSessionRepo.AddQuizSessionInvoked)
+			}
+			if c.expectedEntryIDs == nil && session != nil {
+				t.Errorf("Nil session expected")
+			}
+			if c.expectedEntryIDs != nil {
+				if session == nil {
+					t.Fatalf("Not nil session expected")
+				}
+				if session.Direction != domain.DirectionReverse {
+					t.Errorf("Expected direction:%s;Actual:%s", domain.DirectionReverse, session.Direction)
+				}
+				var entryIDs []int
+				for _, e := range session.Entries {
+					entryIDs = append(entryIDs, e.EntryID)
+				}
+				if !reflect.DeepEqual(entryIDs, c.expectedEntryIDs) {
+					t.Errorf("Expected entry IDs:%v;Actual:%v", c.expectedEntryIDs, entryIDs)
+				}
+			}
+			mockedScheduleRepo.Reset()
+			mockedSessionRepo.Reset()
+		})
+	}
+}
+
+func TestQuizWithLocalRepo_AnswerQuizSessionEntry(t *testing.T) {
+	testCases := []struct {
+		name                   string
+		userID, entryID        int
+		direction              domain.Direction
+		expectSession          bool
+		expectFinished         bool
+		expectErr              bool
+		expectGetSessionInv    bool
+		expectReviewInv        bool
+		expectGradeEntryInv    bool
+		expectFinishSessionInv bool
+	}{
+		{
+			name:                "Positive not the last entry",
+			userID:              1,
+			entryID:             1,
+			expectSession:       true,
+			expectGetSessionInv: true,
+			expectReviewInv:     true,
+			expectGradeEntryInv: true,
+		},
+		{
+			name:                   "Positive the last entry",
+			userID:                 6,
+			entryID:                1,
+			expectSession:          true,
+			expectFinished:         true,
+			expectGetSessionInv:    true,
+			expectReviewInv:        true,
+			expectGradeEntryInv:    true,
+			expectFinishSessionInv: true,
+		},
+		{
+			name:                "Positive entry is already answered",
+			userID:              1,
+			entryID:             3,
+			expectGetSessionInv: true,
+		},
+		{
+			name:                "Positive entry is not the next one",
+			userID:              1,
+			entryID:             2,
+			expectGetSessionInv: true,
+		},
+		{
+			name:                "Positive entry is not in the session",
+			userID:              1,
+			entryID:             4,
+			expectGetSessionInv: true,
+		},
+		{
+			name:                "Positive other direction",
+			userID:              1,
+			entryID:             1,
+			direction:           domain.DirectionReverse,
+			expectGetSessionInv: true,
+		},
+		{
+			name:                "Positive no active session",
+			userID:              2,
+			entryID:             1,
+			expectGetSessionInv: true,
+		},
+		{
+			name:                "Positive entry is answered concurrently",
+			userID:              7,
+			entryID:             1,
+			expectGetSessionInv: true,
+			expectGradeEntryInv: true,
+		},
+		{
+			name:                "Review returns error",
+			userID:              3,
+			entryID:             1,
+			expectErr:           true,
+			expectGetSessionInv: true,
+			expectReviewInv:     true,
+			expectGradeEntryInv: true,
+		},
+		{
+			name:                "Get session returns error",
+			userID:              4,
+			entryID:             1,
+			expectErr:           true,
+			expectGetSessionInv: true,
+		},
+		{
+			name:                "Grade entry returns error",
+			userID:              5,
+			entryID:             1,
+			expectErr:           true,
+			expectGetSessionInv: true,
+			expectGradeEntryInv: true,
+		},
+	}
+
+	mockedScheduler := &mock.SchedulerService{
+		ReviewEntryFn: func(entryID, userID int, direction domain.Direction, grade domain.Grade) error {
+			if userID == 3 {
+				return fmt.Errorf("error")
+			}
+			return nil
+		},
+	}
+	mockedSessionRepo := &mock.QuizSessionRepo{
+		GetActiveQuizSessionFn: func(userID int) (*domain.QuizSession, error) {
+			switch userID {
+			case 2:
+				return nil, nil
+			case 4:
+				return nil, fmt.Errorf("error")
+			}
+			grade := domain.GradeGood
+			entries := []*domain.QuizSessionEntry{{EntryID: 3, Grade: &grade}, {EntryID: 1}}
+			if userID == 1 {
+				entries = append(entries, &domain.QuizSessionEntry{EntryID: 2})
+			}
+			return &domain.QuizSession{ID: userID, Entries: entries}, nil
+		},
+		GradeQuizSessionEntryFn: func(sessionID, entryID int, grade domain.Grade) (bool, error) {
+			switch sessionID {
+			case 5:
+				return false, fmt.Errorf("error")
+			case 7:
+				return false, nil
+			}
+			return true, nil
+		},
+		FinishQuizSessionFn: func(sessionID int, finishedAt time.Time) error {
+			return nil
+		},
+	}
+
+	quizService := NewQuizWithLocalRepo(mock.Logger{}, &mock.VocabRepo{}, &mock.ScheduleRepo{}, mockedSessionRepo, mockedScheduler)
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			session, err := quizService.AnswerQuizSessionEntry(c.entryID, c.userID, c.direction, domain.GradeAgain)
+			if !c.expectErr && err != nil {
+				t.Errorf("Expected no error, but got %s", err)
+			}
+			if c.expectErr && err == nil {
+				t.Errorf("Expected error, but got nothing")
+			}
+			if c.expectReviewInv != mockedScheduler.ReviewEntryInvoked {
+				t.Errorf("Actual invocation of ReviewEntry(%v) doesn't match expectations", mockedScheduler.ReviewEntryInvoked)
+			}
+			if c.expectGetSessionInv != mockedSessionRepo.GetActiveQuizSessionInvoked {
+				t.Errorf("Actual invocation of GetActiveQuizSession(%v) doesn't match expectations", mockedSessionRepo.GetActiveQuizSessionInvoked)
+			}
+			if c.expectGradeEntryInv != mockedSessionRepo.GradeQuizSessionEntryInvoked {
+				t.Errorf("Actual invocation of GradeQuizSessionEntry(%v) doesn't match expectations", mockedSessionRepo.GradeQuizSessionEntryInvoked)
+			}
+			if c.expectFinishSessionInv != mockedSessionRepo.FinishQuizSessionInvoked {
+				t.Errorf("Actual invocation of FinishQuizSession(%v) doesn't match expectations", mockedSessionRepo.FinishQuizSessionInvoked)
+			}
+			if c.expectSession != (session != nil) {
+				t.Errorf("Expected session:%v;Actual:%v", c.expectSession, session)
+			}
+			if session != nil {
+				sessionEntry := session.Entry(c.entryID)
+				if sessionEntry.Grade == nil || *sessionEntry.Grade != domain.GradeAgain {
+					t.Errorf("Expected entry to be graded with %s", domain.GradeAgain)
+				}
+				if c.expectFinished != (session.FinishedAt != nil) {
+					t.Errorf("Expected finished:%v;Actual:%v", c.expectFinished, session.FinishedAt)
+				}
+			}
+			mockedScheduler.Reset()
+			mockedSessionRepo.Reset()
+		})
+	}
+}
+
+func TestQuizWithLocalRepo_AnswerQuizSessionEntryTwice(t *testing.T) {
+	reviews := 0
+	mockedScheduler := &mock.SchedulerService{
+		ReviewEntryFn: func(entryID, userID int, direction domain.Direction, grade domain.Grade) error {
+			reviews++
+			return nil
+		},
+	}
+	graded := make(map[int]bool)
+	mockedSessionRepo := &mock.QuizSessionRepo{
+		GetActiveQuizSessionFn: func(userID int) (*domain.QuizSession, error) {
+			entries := []*domain.QuizSessionEntry{{EntryID: 1}, {EntryID: 2}}
+			return &domain.QuizSession{ID: 1, Entries: entries}, nil
+		},
+		GradeQuizSessionEntryFn: func(sessionID, entryID int, grade domain.Grade) (bool, error) {
+			if graded[entryID] {
+				return false, nil
+			}
+			graded[entryID] = true
+			return true, nil
+		},
+	}
+
+	quizService := NewQuizWithLocalRepo(mock.Logger{}, &mock.VocabRepo{}, &mock.ScheduleRepo{}, mockedSessionRepo, mockedScheduler)
+	session, err := quizService.AnswerQuizSessionEntry(1, 1, domain.DirectionForward, domain.GradeGood)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if session == nil {
+		t.Fatalf("Expected session on the first answer")
+	}
+	session, err = quizService.AnswerQuizSessionEntry(1, 1, domain.DirectionForward, domain.GradeAgain)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	if session != nil {
+		t.Errorf("Expected no session on the second answer, but got %+v", session)
+	}
+	if reviews != 1 {
+		t.Errorf("Expected reviews:1;Actual:%d", reviews)
+	}
+}
+
+func TestQuizWithLocalRepo_GetQuizSummary(t *testing.T) {
+	again, good, hard := domain.GradeAgain, domain.GradeGood, domain.GradeHard
+	session := &domain.QuizSession{
+		Entries: []*domain.QuizSessionEntry{
+			{EntryID: 1, Grade: &good},
+			{EntryID: 2, Grade: &again},
+			{EntryID: 3, Grade: &hard},
+			{EntryID: 4},
+		},
+	}
+	mockedRepo := &mock.VocabRepo{
+		GetVocabEntryByIDFn: func(id int) (*domain.VocabEntry, error) {
+			return &domain.VocabEntry{ID: id}, nil
+		},
+	}
+
+	quizService := NewQuizWithLocalRepo(mock.Logger{}, mockedRepo, &mock.ScheduleRepo{}, &mock.QuizSessionRepo{}, &mock.SchedulerService{})
+	summary, err := quizService.GetQuizSummary(session)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	expected := &domain.QuizSummary{
+		Known:  []*domain.VocabEntry{{ID: 1}, {ID: 3}},
+		Missed: []*domain.VocabEntry{{ID: 2}},
+	}
+	if !reflect.DeepEqual(summary, expected) {
+		t.Errorf("Expected summary:%+v;Actual:%+v", expected, summary)
+	}
+}
+
+func newTestEntry(id int, text, mainTranslation, class string) *domain.VocabEntry {
+	return &domain.VocabEntry{
+		ID:              id,
+		Text:            text,
+		MainTranslation: mainTranslation,
+		Translations: []*domain.Translation{
+			{Text: mainTranslation, Class: class},
+		},
+	}
+}
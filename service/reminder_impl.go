@@ -0,0 +1,169 @@
+package service
+
+import (
+	"fmt"
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"github.com/dmalyar/pimpmyvocab/log"
+	"github.com/dmalyar/pimpmyvocab/repo"
+	"time"
+)
+
+// ReminderWithLocalRepo implements service.Reminder interface for working with local repository.
+type ReminderWithLocalRepo struct {
+	logger       log.Logger
+	settingsRepo repo.UserSettings
+	scheduleRepo repo.Schedule
+	now          func() time.Time
+}
+
+func NewReminderWithLocalRepo(
+	logger log.Logger,
+	settingsRepo repo.UserSettings,
+	scheduleRepo repo.Schedule,
+) *ReminderWithLocalRepo {
+	return &ReminderWithLocalRepo{
+		logger:       logger,
+		settingsRepo: settingsRepo,
+		scheduleRepo: scheduleRepo,
+		now:          time.Now,
+	}
+}
+
+// GetUserSettings returns the user's settings.
+// Returns default settings with disabled reminders if the user has never changed settings.
+func (r *ReminderWithLocalRepo) GetUserSettings(userID int) (*domain.UserSettings, error) {
+	settings, err := r.settingsRepo.GetUserSettings(userID)
+	if err != nil {
+		return nil, fmt.Errorf("getting user settings: %s", err)
+	}
+	if settings == nil {
+		settings = domain.DefaultUserSettings(userID)
+	}
+	return settings, nil
+}
+
+// SetReminder enables daily reminders at the given time of day in the given IANA timezone.
+func (r *ReminderWithLocalRepo) SetReminder(
+	userID int,
+	chatID int64,
+	remindAt domain.TimeOfDay,
+	timezone string,
+) (*domain.UserSettings, error) {
+	logger := r.logger.WithFields(map[string]interface{}{
+		"userID":   userID,
+		"remindAt": remindAt,
+		"timezone": timezone,
+	})
+	logger.Debug("Setting reminder")
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return nil, fmt.Errorf("loading timezone: %s", err)
+	}
+	settings, err := r.GetUserSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	settings.ChatID = chatID
+	settings.RemindAt = remindAt
+	settings.Timezone = timezone
+	settings.RemindersEnabled = true
+	err = r.settingsRepo.SaveUserSettings(settings)
+	if err != nil {
+		return nil, fmt.Errorf("saving user settings: %s", err)
+	}
+	logger.WithField("userSettings", settings).Info("Reminder set")
+	return settings, nil
+}
+
+// SetRemindersEnabled enables or disables daily reminders keeping their time and timezone.
+func (r *ReminderWithLocalRepo) SetRemindersEnabled(userID int, chatID int64, enabled bool) (*domain.UserSettings, error) {
+	logger := r.logger.WithFields(map[string]interface{}{
+		"userID":  userID,
+		"enabled": enabled,
+	})
+	logger.Debug("Setting reminders enabled")
+	settings, err := r.GetUserSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	settings.ChatID = chatID
+	settings.RemindersEnabled = enabled
+	err = r.settingsRepo.SaveUserSettings(settings)
+	if err != nil {
+		return nil, fmt.Errorf("saving user settings: %s", err)
+	}
+	logger.WithField("userSettings", settings).Info("Reminders enabled set")
+	return settings, nil
+}
+
+// reminderWindow is how long after its time the reminder is still sent, e.g. after the bot restart.
+// Reminders missed for longer and the ones set for the time already passed today wait for the next day.
+const reminderWindow = time.Hour
+
+// GetRemindersToSend returns reminders for the users whose reminder time has come in their timezones
+// not longer than reminderWindow ago and who have not been reminded on that date yet.
+// Users with no entries due for review get no reminder. Errors with particular users are logged
+// and the users are skipped. Returned reminders should be marked with MarkReminderSent before they're delivered.
+func (r *ReminderWithLocalRepo) GetRemindersToSend() ([]*domain.Reminder, error) {
+	r.logger.Debug("Getting reminders to send")
+	settings, err := r.settingsRepo.GetUserSettingsWithRemindersEnabled()
+	if err != nil {
+		return nil, fmt.Errorf("getting user settings with reminders enabled: %s", err)
+	}
+	now := r.now()
+	var reminders []*domain.Reminder
+	for _, s := range settings {
+		logger := r.logger.WithField("userSettings", s)
+		location, err := time.LoadLocation(s.Timezone)
+		if err != nil {
+			logger.Errorf("Error loading the user's timezone: %s", err)
+			continue
+		}
+		date, ok := reminderDate(now.In(location), s.RemindAt)
+		if !ok {
+			continue
+		}
+		if s.LastRemindedOn != nil && !s.LastRemindedOn.Before(date) {
+			continue
+		}
+		dueQnt, err := r.scheduleRepo.CountEntriesToReview(s.UserID, domain.DirectionForward, now)
+		if err != nil {
+			logger.Errorf("Error counting entries to review: %s", err)
+			continue
+		}
+		if dueQnt == 0 {
+			logger.Debug("User has no entries to review")
+			continue
+		}
+		reminders = append(reminders, &domain.Reminder{UserID: s.UserID, ChatID: s.ChatID, DueQnt: dueQnt, Date: date})
+	}
+	return reminders, nil
+}
+
+// reminderDate returns the date of the reminder due at the given local time.
+// Returns false if the reminder time has not come yet or has passed more than reminderWindow ago.
+// The reminder of the previous day is due after midnight if its time is close to the end of the day.
+func reminderDate(local time.Time, remindAt domain.TimeOfDay) (time.Time, bool) {
+	for _, days := range []int{0, -1} {
+		day := local.AddDate(0, 0, days)
+		at := time.Date(day.Year(), day.Month(), day.Day(), 0, int(remindAt), 0, 0, local.Location())
+		if !local.Before(at) && local.Sub(at) < reminderWindow {
+			return time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.UTC), true
+		}
+	}
+	return time.Time{}, false
+}
+
+// MarkReminderSent saves the date of the reminder before it's delivered, so the user gets at most one reminder a day
+// even after restart. Returns false if the user has already been reminded on the date and the reminder must not be sent.
+func (r *ReminderWithLocalRepo) MarkReminderSent(reminder *domain.Reminder) (bool, error) {
+	logger := r.logger.WithField("reminder", reminder)
+	logger.Debug("Marking reminder as sent")
+	marked, err := r.settingsRepo.MarkReminded(reminder.UserID, reminder.Date)
+	if err != nil {
+		return false, fmt.Errorf("marking user as reminded: %s", err)
+	}
+	if !marked {
+		logger.Info("User has already been reminded on the date")
+	}
+	return marked, nil
+}
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"fmt"
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"github.com/dmalyar/pimpmyvocab/mock"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestReminderWithLocalRepo_SetReminder(t *testing.T) {
+	testCases := []struct {
+		name             string
+		userID           int
+		timezone         string
+		expectedSettings *domain.UserSettings
+		expectErr        bool
+		expectSaveInv    bool
+	}{
+		{
+			name:     "Positive new settings",
+			userID:   1,
+			timezone: "Europe/Moscow",
+			expectedSettings: &domain.UserSettings{
+				UserID: 1, ChatID: 10, RemindAt: 600, Timezone: "Europe/Moscow", RemindersEnabled: true,
+				LangPair: domain.DefaultLangPair,
+			},
+			expectSaveInv: true,
+		},
+		{
+			name:     "Positive existing settings",
+			userID:   2,
+			timezone: "UTC",
+			expectedSettings: &domain.UserSettings{
+				UserID: 2, ChatID: 10, RemindAt: 600, Timezone: "UTC", RemindersEnabled: true,
+			},
+			expectSaveInv: true,
+		},
+		{
+			name:      "Unknown timezone",
+			userID:    1,
+			timezone:  "Mars/Olympus",
+			expectErr: true,
+		},
+		{
+			name:      "Get settings returns error",
+			userID:    3,
+			timezone:  "UTC",
+			expectErr: true,
+		},
+		{
+			name:          "Save settings returns error",
+			userID:        4,
+			timezone:      "UTC",
+			expectErr:     true,
+			expectSaveInv: true,
+		},
+	}
+
+	mockedRepo := &mock.UserSettingsRepo{
+		GetUserSettingsFn: func(userID int) (*domain.UserSettings, error) {
+			switch userID {
+			case 2:
+				return &domain.UserSettings{UserID: 2, ChatID: 5, RemindAt: 60, Timezone: "Asia/Tokyo"}, nil
+			case 3:
+				return nil, fmt.Errorf("error")
+			default:
+				return nil, nil
+			}
+		},
+		SaveUserSettingsFn: func(settings *domain.UserSettings) error {
+			if settings.UserID == 4 {
+				return fmt.Errorf("error")
+			}
+			return nil
+		},
+	}
+
+	reminderService := NewReminderWithLocalRepo(mock.Logger{}, mockedRepo, &mock.ScheduleRepo{})
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			settings, err := reminderService.SetReminder(c.userID, 10, 600, c.timezone)
+			if !c.expectErr && err != nil {
+				t.Errorf("Expected no error, but got %s", err)
+			}
+			if c.expectErr && err == nil {
+				t.Errorf("Expected error, but got nothing")
+			}
+			if c.expectSaveInv != mockedRepo.SaveUserSettingsInvoked {
+				t.Errorf("Actual invocation of SaveUserSettings(%v) doesn't match expectations", mockedRepo.SaveUserSettingsInvoked)
+			}
+			if !reflect.DeepEqual(settings, c.expectedSettings) {
+				t.Errorf("Expected settings:%v;Actual:%v", c.expectedSettings, settings)
+			}
+			mockedRepo.Reset()
+		})
+	}
+}
+
+func TestReminderWithLocalRepo_GetRemindersToSend(t *testing.T) {
+	// 07:30 in UTC, 10:30 in Moscow, 16:30 in Tokyo, 00:30 in GMT-7.
+	now := time.Date(2020, 5, 10, 7, 30, 0, 0, time.UTC)
+	today := time.Date(2020, 5, 10, 0, 0, 0, 0, time.UTC)
+	yesterday := today.AddDate(0, 0, -1)
+	settings := []*domain.UserSettings{
+		// Time has come in the user's timezone.
+		{UserID: 1, ChatID: 11, RemindAt: 600, Timezone: "Europe/Moscow", LastRemindedOn: &yesterday},
+		// Time has not come yet.
+		{UserID: 2, ChatID: 12, RemindAt: 600, Timezone: "UTC"},
+		// Already reminded today.
+		{UserID: 3, ChatID: 13, RemindAt: 960, Timezone: "Asia/Tokyo", LastRemindedOn: &today},
+		// Time has passed long ago today.
+		{UserID: 4, ChatID: 14, RemindAt: 0, Timezone: "UTC"},
+		// Nothing to review.
+		{UserID: 5, ChatID: 15, RemindAt: 420, Timezone: "UTC"},
+		// Unknown timezone.
+		{UserID: 6, ChatID: 16, RemindAt: 420, Timezone: "Mars/Olympus"},
+		// New user.
+		{UserID: 7, ChatID: 17, RemindAt: 450, Timezone: "UTC"},
+		// Error counting entries to review.
+		{UserID: 8, ChatID: 18, RemindAt: 420, Timezone: "UTC"},
+		// Yesterday's time has come just before midnight.
+		{UserID: 9, ChatID: 19, RemindAt: 1425, Timezone: "Etc/GMT+7"},
+		// Already reminded yesterday at the time just before midnight.
+		{UserID: 10, ChatID: 20, RemindAt: 1425, Timezone: "Etc/GMT+7", LastRemindedOn: &yesterday},
+	}
+	mockedRepo := &mock.UserSettingsRepo{
+		GetUserSettingsWithRemindersEnabledFn: func() ([]*domain.UserSettings, error) {
+			return settings, nil
+		},
+		MarkRemindedFn: func(userID int, date time.Time) (bool, error) {
+			return true, nil
+		},
+	}
+	mockedScheduleRepo := &mock.ScheduleRepo{
+		CountEntriesToReviewFn: func(userID int, direction domain.Direction, at time.Time) (int, error) {
+			switch userID {
+			case 5:
+				return 0, nil
+			case 8:
+				return 0, fmt.Errorf("error")
+			}
+			return userID * 10, nil
+		},
+	}
+
+	reminderService := NewReminderWithLocalRepo(mock.Logger{}, mockedRepo, mockedScheduleRepo)
+	reminderService.now = func() time.Time {
+		return now
+	}
+	reminders, err := reminderService.GetRemindersToSend()
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	expectedReminders := []*domain.Reminder{
+		{UserID: 1, ChatID: 11, DueQnt: 10, Date: today},
+		{UserID: 7, ChatID: 17, DueQnt: 70, Date: today},
+		{UserID: 9, ChatID: 19, DueQnt: 90, Date: yesterday},
+	}
+	if !reflect.DeepEqual(reminders, expectedReminders) {
+		t.Errorf("Expected reminders:%v;Actual:%v", expectedReminders, reminders)
+	}
+	if mockedRepo.MarkRemindedInvoked {
+		t.Errorf("MarkReminded expected not to be invoked while getting reminders")
+	}
+}
+
+func TestReminderWithLocalRepo_MarkReminderSent(t *testing.T) {
+	date := time.Date(2020, 5, 10, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name         string
+		reminder     *domain.Reminder
+		expectMarked bool
+		expectErr    bool
+	}{
+		{
+			name:         "Positive",
+			reminder:     &domain.Reminder{UserID: 1, ChatID: 11, DueQnt: 10, Date: date},
+			expectMarked: true,
+		},
+		{
+			name:     "Positive already reminded",
+			reminder: &domain.Reminder{UserID: 2, ChatID: 12, DueQnt: 10, Date: date},
+		},
+		{
+			name:      "Mark reminded returns error",
+			reminder:  &domain.Reminder{UserID: 3, ChatID: 13, DueQnt: 10, Date: date},
+			expectErr: true,
+		},
+	}
+	var markedUserID int
+	var markedDate time.Time
+	mockedRepo := &mock.UserSettingsRepo{
+		MarkRemindedFn: func(userID int, date time.Time) (bool, error) {
+			markedUserID, markedDate = userID, date
+			switch userID {
+			case 2:
+				return false, nil
+			case 3:
+				return false, fmt.Errorf("error")
+			}
+			return true, nil
+		},
+	}
+	reminderService := NewReminderWithLocalRepo(mock.Logger{}, mockedRepo, &mock.ScheduleRepo{})
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			marked, err := reminderService.MarkReminderSent(c.reminder)
+			if marked != c.expectMarked {
+				t.Errorf("Expected marked:%v;Actual:%v", c.expectMarked, marked)
+			}
+			if !c.expectErr && err != nil {
+				t.Errorf("Expected no error, but got %s", err)
+			}
+			if c.expectErr && err == nil {
+				t.Errorf("Expected error, but got nothing")
+			}
+			if markedUserID != c.reminder.UserID || markedDate != c.reminder.Date {
+				t.Errorf("Expected marked user:%v on %s;Actual:%v on %s",
+					c.reminder.UserID, c.reminder.Date, markedUserID, markedDate)
+			}
+			mockedRepo.Reset()
+		})
+	}
+}
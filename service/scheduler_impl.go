@@ -0,0 +1,147 @@
+package service
+
+import (
+	"fmt"
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"github.com/dmalyar/pimpmyvocab/log"
+	"github.com/dmalyar/pimpmyvocab/repo"
+	"math"
+	"time"
+)
+
+const (
+	minEaseFactor = 1.3
+	day           = 24 * time.Hour
+)
+
+// SchedulerWithLocalRepo implements service.Scheduler interface using SM-2 algorithm
+// and the local repository for storing review schedules.
+type SchedulerWithLocalRepo struct {
+	logger       log.Logger
+	vocabRepo    repo.Vocab
+	scheduleRepo repo.Schedule
+	now          func() time.Time
+}
+
+func NewSchedulerWithLocalRepo(logger log.Logger, vocabRepo repo.Vocab, scheduleRepo repo.Schedule) *SchedulerWithLocalRepo {
+	return &SchedulerWithLocalRepo{
+		logger:       logger,
+		vocabRepo:    vocabRepo,
+		scheduleRepo: scheduleRepo,
+		now:          time.Now,
+	}
+}
+
+// GetNextEntryToReview returns the most overdue in the given direction vocab entry from the user's vocab.
+// If there is no entry in the user's vocab then returns nil.
+// If the given previousEntryID is not 0 then uses it to not return the same entry.
+// Ignores if it is the only entry in the vocab.
+func (s *SchedulerWithLocalRepo) GetNextEntryToReview(
+	userID, previousEntryID int,
+	direction domain.Direction,
+) (*domain.VocabEntry, error) {
+	logger := s.logger.WithFields(map[string]interface{}{
+		"userID":          userID,
+		"previousEntryID": previousEntryID,
+		"direction":       direction,
+	})
+	logger.Debug("Getting next vocab entry to review")
+	entryIDs, err := s.scheduleRepo.GetEntryIDsToReview(userID, direction)
+	if err != nil {
+		return nil, fmt.Errorf("getting entry IDs to review: %s", err)
+	}
+	if len(entryIDs) == 0 {
+		logger.Info("User's vocab is empty")
+		return nil, nil
+	}
+	id := entryIDs[0]
+	if id == previousEntryID && len(entryIDs) > 1 {
+		id = entryIDs[1]
+	}
+	entry, err := s.vocabRepo.GetVocabEntryByID(id)
+	if err != nil {
+		return nil, fmt.Errorf("getting vocab entry by ID: %s", err)
+	}
+	logger.WithField("entry", entry).Info("Next vocab entry to review found")
+	return entry, nil
+}
+
+// ReviewEntry records the review of the entry from the user's vocab in the given direction,
+// reschedules the entry in this direction and advances the user's progress in learning it
+// according to the given grade.
+// If the entry is not in the user's vocab then do nothing.
+func (s *SchedulerWithLocalRepo) ReviewEntry(entryID, userID int, direction domain.Direction, grade domain.Grade) error {
+	logger := s.logger.WithFields(map[string]interface{}{
+		"entryID":   entryID,
+		"userID":    userID,
+		"direction": direction,
+		"grade":     grade,
+	})
+	logger.Debug("Reviewing the entry")
+	schedule, err := s.scheduleRepo.GetSchedule(entryID, userID, direction)
+	if err != nil {
+		return fmt.Errorf("getting schedule: %s", err)
+	}
+	if schedule == nil {
+		logger.Info("Vocab entry is not in the user's vocab")
+		return nil
+	}
+	now := s.now()
+	review := &domain.Review{
+		EntryID:    entryID,
+		Direction:  direction,
+		Grade:      grade,
+		ReviewedAt: now,
+	}
+	reschedule(schedule, grade, now)
+	progress, err := s.scheduleRepo.SaveReview(review, schedule, userID)
+	if err != nil {
+		return fmt.Errorf("saving review: %s", err)
+	}
+	logger.WithField("schedule", schedule).Info("Vocab entry rescheduled")
+	if progress == nil {
+		logger.Info("Vocab entry was removed from the user's vocab while reviewing")
+		return nil
+	}
+	logger.WithField("progress", progress).Info("Vocab entry progress updated")
+	return nil
+}
+
+// reschedule applies SM-2 algorithm to the schedule.
+// Grades are mapped to SM-2 quality of response as follows: again – 1, hard – 3, good – 4, easy – 5.
+func reschedule(schedule *domain.Schedule, grade domain.Grade, now time.Time) {
+	quality := gradeToQuality(grade)
+	if quality < 3 {
+		schedule.Repetitions = 0
+		schedule.Interval = 1
+	} else {
+		switch schedule.Repetitions {
+		case 0:
+			schedule.Interval = 1
+		case 1:
+			schedule.Interval = 6
+		default:
+			schedule.Interval = int(math.Round(float64(schedule.Interval) * schedule.EaseFactor))
+		}
+		schedule.Repetitions++
+	}
+	q := float64(5 - quality)
+	schedule.EaseFactor += 0.1 - q*(0.08+q*0.02)
+	if schedule.EaseFactor < minEaseFactor {
+		schedule.EaseFactor = minEaseFactor
+	}
+	schedule.DueAt = now.Add(time.Duration(schedule.Interval) * day)
+}
+
+func gradeToQuality(grade domain.Grade) int {
+	switch grade {
+	case domain.GradeAgain:
+		return 1
+	case domain.GradeHard:
+		return 3
+	case domain.GradeGood:
+		return 4
+	default:
+		return 5
+	}
+}
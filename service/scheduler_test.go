@@ -0,0 +1,295 @@
+package service
+
+import (
+	"fmt"
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"github.com/dmalyar/pimpmyvocab/mock"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSchedulerWithLocalRepo_GetNextEntryToReview(t *testing.T) {
+	testCases := []struct {
+		name                    string
+		userID, previousEntryID int
+		direction               domain.Direction
+		expectedEntry           *domain.VocabEntry
+		expectErr               bool
+		expectGetEntryIDsInv    bool
+		expectGetEntryByIDInv   bool
+	}{
+		{
+			name:                  "Positive most overdue entry",
+			userID:                1,
+			previousEntryID:       3,
+			expectedEntry:         &domain.VocabEntry{ID: 1},
+			expectGetEntryIDsInv:  true,
+			expectGetEntryByIDInv: true,
+		},
+		{
+			name:                  "Positive most overdue entry is the previous one",
+			userID:                1,
+			previousEntryID:       1,
+			expectedEntry:         &domain.VocabEntry{ID: 2},
+			expectGetEntryIDsInv:  true,
+			expectGetEntryByIDInv: true,
+		},
+		{
+			name:                  "Positive reverse direction",
+			userID:                1,
+			previousEntryID:       1,
+			direction:             domain.DirectionReverse,
+			expectedEntry:         &domain.VocabEntry{ID: 3},
+			expectGetEntryIDsInv:  true,
+			expectGetEntryByIDInv: true,
+		},
+		{
+			name:                  "Positive single entry",
+			userID:                2,
+			previousEntryID:       1,
+			expectedEntry:         &domain.VocabEntry{ID: 1},
+			expectGetEntryIDsInv:  true,
+			expectGetEntryByIDInv: true,
+		},
+		{
+			name:                 "Positive no entries",
+			userID:               3,
+			previousEntryID:      1,
+			expectGetEntryIDsInv: true,
+		},
+		{
+			name:                 "Get entry IDs returns error",
+			userID:               4,
+			previousEntryID:      1,
+			expectGetEntryIDsInv: true,
+			expectErr:            true,
+		},
+		{
+			name:                  "Get entry by ID returns error",
+			userID:                5,
+			previousEntryID:       1,
+			expectGetEntryIDsInv:  true,
+			expectGetEntryByIDInv: true,
+			expectErr:             true,
+		},
+	}
+
+	mockedScheduleRepo := &mock.ScheduleRepo{
+		GetEntryIDsToReviewFn: func(userID int, direction domain.Direction) ([]int, error) {
+			if direction == domain.DirectionReverse {
+				return []int{3, 2, 1}, nil
+			}
+			switch userID {
+			case 1:
+				return []int{1, 2, 3}, nil
+			case 2:
+				return []int{1}, nil
+			case 4:
+				return nil, fmt.Errorf("error")
+			case 5:
+				return []int{5}, nil
+			default:
+				return nil, nil
+			}
+		},
+	}
+	mockedRepo := &mock.VocabRepo{
+		GetVocabEntryByIDFn: func(id int) (*domain.VocabEntry, error) {
+			switch id {
+			case 5:
+				return nil, fmt.Errorf("error")
+			default:
+				return &domain.VocabEntry{ID: id}, nil
+			}
+		},
+	}
+
+	scheduler := NewSchedulerWithLocalRepo(mock.Logger{}, mockedRepo, mockedScheduleRepo)
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			entry, err := scheduler.GetNextEntryToReview(c.userID, c.previousEntryID, c.direction)
+			if !c.expectErr && err != nil {
+				t.Errorf("Expected no error, but got %s", err)
+			}
+			if c.expectErr && err == nil {
+				t.Errorf("Expected error, but got nothing")
+			}
+			if c.expectGetEntryIDsInv != mockedScheduleRepo.GetEntryIDsToReviewInvoked {
+				t.Errorf("Actual invocation of GetEntryIDsToReview(%v) doesn't match expectations", mockedScheduleRepo.GetEntryIDsToReviewInvoked)
+			}
+			if c.expectGetEntryByIDInv != mockedRepo.GetVocabEntryByIDInvoked {
+				t.Errorf("Actual invocation of GetVocabEntryByID(%v) doesn't match expectations", mockedRepo.GetVocabEntryByIDInvoked)
+			}
+			if !reflect.DeepEqual(c.expectedEntry, entry) {
+				t.Errorf("Expected entry:%+v;Actual:%+v", c.expectedEntry, entry)
+			}
+			mockedRepo.Reset()
+			mockedScheduleRepo.Reset()
+		})
+	}
+}
+
+func TestSchedulerWithLocalRepo_ReviewEntry(t *testing.T) {
+	now := time.Date(2020, 5, 1, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name                string
+		entryID, userID     int
+		direction           domain.Direction
+		grade               domain.Grade
+		expectedSchedule    *domain.Schedule
+		expectErr           bool
+		expectSaveReviewInv bool
+	}{
+		{
+			name:    "Positive first good review",
+			entryID: 1,
+			userID:  1,
+			grade:   domain.GradeGood,
+			expectedSchedule: &domain.Schedule{
+				EntryID:     1,
+				EaseFactor:  2.5,
+				Interval:    1,
+				Repetitions: 1,
+				DueAt:       now.Add(day),
+			},
+			expectSaveReviewInv: true,
+		},
+		{
+			name:    "Positive second easy review",
+			entryID: 2,
+			userID:  1,
+			grade:   domain.GradeEasy,
+			expectedSchedule: &domain.Schedule{
+				EntryID:     2,
+				EaseFactor:  2.6,
+				Interval:    6,
+				Repetitions: 2,
+				DueAt:       now.Add(6 * day),
+			},
+			expectSaveReviewInv: true,
+		},
+		{
+			name:    "Positive third hard review",
+			entryID: 3,
+			userID:  1,
+			grade:   domain.GradeHard,
+			expectedSchedule: &domain.Schedule{
+				EntryID:     3,
+				EaseFactor:  2.36,
+				Interval:    15,
+				Repetitions: 3,
+				DueAt:       now.Add(15 * day),
+			},
+			expectSaveReviewInv: true,
+		},
+		{
+			name:      "Positive failed review with min ease factor",
+			entryID:   4,
+			userID:    1,
+			direction: domain.DirectionReverse,
+			grade:     domain.GradeAgain,
+			expectedSchedule: &domain.Schedule{
+				EntryID:     4,
+				Direction:   domain.DirectionReverse,
+				EaseFactor:  minEaseFactor,
+				Interval:    1,
+				Repetitions: 0,
+				DueAt:       now.Add(day),
+			},
+			expectSaveReviewInv: true,
+		},
+		{
+			name:    "Positive entry is not in vocab",
+			entryID: 5,
+			userID:  1,
+			grade:   domain.GradeGood,
+		},
+		{
+			name:      "Get schedule returns error",
+			entryID:   6,
+			userID:    1,
+			grade:     domain.GradeGood,
+			expectErr: true,
+		},
+		{
+			name:                "Save review returns error",
+			entryID:             7,
+			userID:              1,
+			grade:               domain.GradeGood,
+			expectErr:           true,
+			expectSaveReviewInv: true,
+		},
+	}
+
+	var updatedSchedule *domain.Schedule
+	var savedReview *domain.Review
+	mockedScheduleRepo := &mock.ScheduleRepo{
+		GetScheduleFn: func(entryID, userID int, direction domain.Direction) (*domain.Schedule, error) {
+			switch entryID {
+			case 1, 7:
+				return &domain.Schedule{EntryID: entryID, EaseFactor: 2.5}, nil
+			case 2:
+				return &domain.Schedule{EntryID: entryID, EaseFactor: 2.5, Interval: 1, Repetitions: 1}, nil
+			case 3:
+				return &domain.Schedule{EntryID: entryID, EaseFactor: 2.5, Interval: 6, Repetitions: 2}, nil
+			case 4:
+				return &domain.Schedule{EntryID: entryID, Direction: direction, EaseFactor: 1.4, Interval: 10, Repetitions: 3}, nil
+			case 6:
+				return nil, fmt.Errorf("error")
+			default:
+				return nil, nil
+			}
+		},
+		SaveReviewFn: func(review *domain.Review, schedule *domain.Schedule, userID int) (*domain.EntryProgress, error) {
+			if review.EntryID == 7 {
+				return nil, fmt.Errorf("error")
+			}
+			if review.ReviewedAt != now || review.EntryID != schedule.EntryID {
+				return nil, fmt.Errorf("unexpected review")
+			}
+			updatedSchedule = schedule
+			savedReview = review
+			return &domain.EntryProgress{EntryID: review.EntryID}, nil
+		},
+	}
+
+	scheduler := NewSchedulerWithLocalRepo(mock.Logger{}, &mock.VocabRepo{}, mockedScheduleRepo)
+	scheduler.now = func() time.Time {
+		return now
+	}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			err := scheduler.ReviewEntry(c.entryID, c.userID, c.direction, c.grade)
+			if !c.expectErr && err != nil {
+				t.Errorf("Expected no error, but got %s", err)
+			}
+			if c.expectErr && err == nil {
+				t.Errorf("Expected error, but got nothing")
+			}
+			if !mockedScheduleRepo.GetScheduleInvoked {
+				t.Errorf("GetSchedule was not invoked")
+			}
+			if c.expectSaveReviewInv != mockedScheduleRepo.SaveReviewInvoked {
+				t.Errorf("Actual invocation of SaveReview(%v) doesn't match expectations", mockedScheduleRepo.SaveReviewInvoked)
+			}
+			if c.expectedSchedule != nil {
+				if updatedSchedule == nil {
+					t.Fatalf("Schedule was not updated")
+				}
+				updatedSchedule.EaseFactor = math.Round(updatedSchedule.EaseFactor*100) / 100
+				if !reflect.DeepEqual(c.expectedSchedule, updatedSchedule) {
+					t.Errorf("Expected schedule:%+v;Actual:%+v", c.expectedSchedule, updatedSchedule)
+				}
+				expectedReview := &domain.Review{EntryID: c.entryID, Direction: c.direction, Grade: c.grade, ReviewedAt: now}
+				if !reflect.DeepEqual(expectedReview, savedReview) {
+					t.Errorf("Expected review:%+v;Actual:%+v", expectedReview, savedReview)
+				}
+			}
+			updatedSchedule = nil
+			savedReview = nil
+			mockedScheduleRepo.Reset()
+		})
+	}
+}
@@ -0,0 +1,232 @@
+package service
+
+import (
+	"fmt"
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"github.com/dmalyar/pimpmyvocab/log"
+	"github.com/dmalyar/pimpmyvocab/repo"
+	"time"
+)
+
+const (
+	week  = 7 * day
+	month = 30 * day
+
+	// streakWindowDays is the number of the latest days whose review counts are fetched to count the streak.
+	// Earlier days are fetched only if the streak doesn't break within them.
+	streakWindowDays = 30
+)
+
+// StatsWithLocalRepo implements service.Stats interface for working with local repository.
+// Days are counted in the user's timezone.
+type StatsWithLocalRepo struct {
+	logger       log.Logger
+	statsRepo    repo.Stats
+	settingsRepo repo.UserSettings
+	now          func() time.Time
+}
+
+func NewStatsWithLocalRepo(logger log.Logger, statsRepo repo.Stats, settingsRepo repo.UserSettings) *StatsWithLocalRepo {
+	return &StatsWithLocalRepo{
+		logger:       logger,
+		statsRepo:    statsRepo,
+		settingsRepo: settingsRepo,
+		now:          time.Now,
+	}
+}
+
+// GetStats returns statistics of the user's vocab and reviews.
+// Weeks and months are counted as the last 7 and 30 days.
+func (s *StatsWithLocalRepo) GetStats(userID int) (*domain.Stats, error) {
+	logger := s.logger.WithField("userID", userID)
+	logger.Debug("Getting stats")
+	settings, err := s.getUserSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now().In(settings.Location())
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	stats := new(domain.Stats)
+	stats.TotalEntries, err = s.statsRepo.CountEntries(userID)
+	if err != nil {
+		return nil, fmt.Errorf("counting entries: %s", err)
+	}
+	stats.AddedThisWeek, err = s.statsRepo.CountEntriesAddedSince(userID, now.Add(-week))
+	if err != nil {
+		return nil, fmt.Errorf("counting entries added this week: %s", err)
+	}
+	stats.ReviewsToday, _, err = s.statsRepo.CountReviewsSince(userID, today)
+	if err != nil {
+		return nil, fmt.Errorf("counting reviews today: %s", err)
+	}
+	stats.ReviewsLastWeek, _, err = s.statsRepo.CountReviewsSince(userID, now.Add(-week))
+	if err != nil {
+		return nil, fmt.Errorf("counting reviews for the last week: %s", err)
+	}
+	var recalled int
+	stats.ReviewsLastMonth, recalled, err = s.statsRepo.CountReviewsSince(userID, now.Add(-month))
+	if err != nil {
+		return nil, fmt.Errorf("counting reviews for the last month: %s", err)
+	}
+	if stats.ReviewsLastMonth != 0 {
+		stats.Accuracy = float64(recalled) / float64(stats.ReviewsLastMonth)
+	}
+	_, stats.Streak, err = s.getStreak(settings, today)
+	if err != nil {
+		return nil, err
+	}
+	logger.WithField("stats", stats).Info("Stats collected")
+	return stats, nil
+}
+
+// GetDailyProgress returns the user's progress towards the daily goal.
+func (s *StatsWithLocalRepo) GetDailyProgress(userID int) (*domain.DailyProgress, error) {
+	logger := s.logger.WithField("userID", userID)
+	logger.Debug("Getting daily progress")
+	settings, err := s.getUserSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	progress, err := s.getDailyProgress(settings, s.now().In(settings.Location()))
+	if err != nil {
+		return nil, err
+	}
+	logger.WithField("dailyProgress", progress).Debug("Daily progress collected")
+	return progress, nil
+}
+
+// CheckDailyGoalReached returns the user's progress towards the daily goal and true
+// if the goal is reached and it's the first check since it was reached today.
+// The goal is reported at most once a day, even if the review count passes it between checks
+// or the goal is changed after being reached.
+func (s *StatsWithLocalRepo) CheckDailyGoalReached(userID int) (*domain.DailyProgress, bool, error) {
+	logger := s.logger.WithField("userID", userID)
+	logger.Debug("Checking daily goal")
+	settings, err := s.getUserSettings(userID)
+	if err != nil {
+		return nil, false, err
+	}
+	today := s.now().In(settings.Location())
+	progress, err := s.getDailyProgress(settings, today)
+	if err != nil {
+		return nil, false, err
+	}
+	if progress.Goal == 0 || progress.ReviewsToday < progress.Goal {
+		return progress, false, nil
+	}
+	date := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, time.UTC)
+	marked, err := s.settingsRepo.MarkGoalReached(userID, date)
+	if err != nil {
+		return nil, false, fmt.Errorf("marking daily goal as reached: %s", err)
+	}
+	if marked {
+		logger.WithField("dailyProgress", progress).Info("Daily goal reached")
+	}
+	return progress, marked, nil
+}
+
+func (s *StatsWithLocalRepo) getDailyProgress(
+	settings *domain.UserSettings,
+	today time.Time,
+) (*domain.DailyProgress, error) {
+	counts, streak, err := s.getStreak(settings, today)
+	if err != nil {
+		return nil, err
+	}
+	progress := &domain.DailyProgress{
+		Goal:   settings.DailyGoal,
+		Streak: streak,
+	}
+	if len(counts) != 0 && sameDate(counts[0].Date, today) {
+		progress.ReviewsToday = counts[0].Qnt
+	}
+	return progress, nil
+}
+
+// SetDailyGoal sets the number of reviews the user wants to do every day and returns the progress towards it.
+// Zero goal removes the goal.
+func (s *StatsWithLocalRepo) SetDailyGoal(userID int, chatID int64, goal int) (*domain.DailyProgress, error) {
+	logger := s.logger.WithFields(map[string]interface{}{
+		"userID": userID,
+		"goal":   goal,
+	})
+	logger.Debug("Setting daily goal")
+	settings, err := s.getUserSettings(userID)
+	if err != nil {
+		return nil, err
+	}
+	settings.ChatID = chatID
+	settings.DailyGoal = goal
+	err = s.settingsRepo.SaveUserSettings(settings)
+	if err != nil {
+		return nil, fmt.Errorf("saving user settings: %s", err)
+	}
+	logger.Info("Daily goal set")
+	return s.GetDailyProgress(userID)
+}
+
+func (s *StatsWithLocalRepo) getUserSettings(userID int) (*domain.UserSettings, error) {
+	settings, err := s.settingsRepo.GetUserSettings(userID)
+	if err != nil {
+		return nil, fmt.Errorf("getting user settings: %s", err)
+	}
+	if settings == nil {
+		settings = domain.DefaultUserSettings(userID)
+	}
+	return settings, nil
+}
+
+// getStreak returns the user's streak and the daily review counts it's counted by, the latest date goes first.
+// Only the counts of the latest streakWindowDays days are fetched, the window is doubled while the streak
+// lasts through it, so the whole review history isn't scanned on every answer.
+func (s *StatsWithLocalRepo) getStreak(
+	settings *domain.UserSettings,
+	today time.Time,
+) ([]*domain.DailyReviews, int, error) {
+	midnight := time.Date(today.Year(), today.Month(), today.Day(), 0, 0, 0, 0, today.Location())
+	for days := streakWindowDays; ; days *= 2 {
+		counts, err := s.statsRepo.GetDailyReviewCounts(settings.UserID, settings.Timezone, midnight.AddDate(0, 0, -days))
+		if err != nil {
+			return nil, 0, fmt.Errorf("getting daily review counts: %s", err)
+		}
+		streak := countStreak(counts, today, settings.DailyGoal)
+		if streak < days {
+			return counts, streak, nil
+		}
+	}
+}
+
+// countStreak returns the number of consecutive days ending today or yesterday
+// with at least goal reviews or at least one review if there is no goal.
+// Counts must be ordered from the latest date.
+func countStreak(counts []*domain.DailyReviews, today time.Time, goal int) int {
+	if goal < 1 {
+		goal = 1
+	}
+	if len(counts) == 0 {
+		return 0
+	}
+	expected := today
+	if !sameDate(counts[0].Date, today) || counts[0].Qnt < goal {
+		// Today's goal may still be reached later, so the streak is not broken yet.
+		expected = today.AddDate(0, 0, -1)
+	}
+	streak := 0
+	for _, c := range counts {
+		if sameDate(c.Date, today) && !sameDate(expected, today) {
+			continue
+		}
+		if !sameDate(c.Date, expected) || c.Qnt < goal {
+			break
+		}
+		streak++
+		expected = expected.AddDate(0, 0, -1)
+	}
+	return streak
+}
+
+func sameDate(a, b time.Time) bool {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.Date()
+	return ay == by && am == bm && ad == bd
+}
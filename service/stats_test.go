@@ -0,0 +1,439 @@
+package service
+
+import (
+	"fmt"
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"github.com/dmalyar/pimpmyvocab/mock"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestStatsWithLocalRepo_GetStats(t *testing.T) {
+	now := time.Date(2020, 5, 10, 15, 30, 0, 0, time.UTC)
+	today := time.Date(2020, 5, 10, 0, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name          string
+		userID        int
+		expectedStats *domain.Stats
+		expectErr     bool
+	}{
+		{
+			name:   "Positive",
+			userID: 1,
+			expectedStats: &domain.Stats{
+				TotalEntries:     10,
+				AddedThisWeek:    3,
+				ReviewsToday:     5,
+				ReviewsLastWeek:  20,
+				ReviewsLastMonth: 40,
+				Accuracy:         0.75,
+				Streak:           2,
+			},
+		},
+		{
+			name:          "Positive no reviews",
+			userID:        2,
+			expectedStats: &domain.Stats{TotalEntries: 10, AddedThisWeek: 3},
+		},
+		{
+			name:      "Count entries returns error",
+			userID:    3,
+			expectErr: true,
+		},
+		{
+			name:      "Count reviews returns error",
+			userID:    4,
+			expectErr: true,
+		},
+		{
+			name:      "Get review dates returns error",
+			userID:    5,
+			expectErr: true,
+		},
+	}
+
+	mockedRepo := &mock.StatsRepo{
+		CountEntriesFn: func(userID int) (int, error) {
+			if userID == 3 {
+				return 0, fmt.Errorf("error")
+			}
+			return 10, nil
+		},
+		CountEntriesAddedSinceFn: func(userID int, since time.Time) (int, error) {
+			if !since.Equal(now.Add(-7 * 24 * time.Hour)) {
+				return 0, fmt.Errorf("unexpected since: %s", since)
+			}
+			return 3, nil
+		},
+		CountReviewsSinceFn: func(userID int, since time.Time) (int, int, error) {
+			switch userID {
+			case 2:
+				return 0, 0, nil
+			case 4:
+				return 0, 0, fmt.Errorf("error")
+			}
+			switch {
+			case since.Equal(today):
+				return 5, 4, nil
+			case since.Equal(now.Add(-7 * 24 * time.Hour)):
+				return 20, 16, nil
+			case since.Equal(now.Add(-30 * 24 * time.Hour)):
+				return 40, 30, nil
+			default:
+				return 0, 0, fmt.Errorf("unexpected since: %s", since)
+			}
+		},
+		GetDailyReviewCountsFn: func(userID int, timezone string, since time.Time) ([]*domain.DailyReviews, error) {
+			switch userID {
+			case 1:
+				return []*domain.DailyReviews{
+					{Date: today.AddDate(0, 0, -1), Qnt: 3},
+					{Date: today.AddDate(0, 0, -2), Qnt: 1},
+					{Date: today.AddDate(0, 0, -4), Qnt: 5},
+				}, nil
+			case 5:
+				return nil, fmt.Errorf("error")
+			default:
+				return nil, nil
+			}
+		},
+	}
+	mockedSettingsRepo := &mock.UserSettingsRepo{
+		GetUserSettingsFn: func(userID int) (*domain.UserSettings, error) {
+			return nil, nil
+		},
+	}
+
+	statsService := NewStatsWithLocalRepo(mock.Logger{}, mockedRepo, mockedSettingsRepo)
+	statsService.now = func() time.Time {
+		return now
+	}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			stats, err := statsService.GetStats(c.userID)
+			if !c.expectErr && err != nil {
+				t.Errorf("Expected no error, but got %s", err)
+			}
+			if c.expectErr && err == nil {
+				t.Errorf("Expected error, but got nothing")
+			}
+			if !reflect.DeepEqual(stats, c.expectedStats) {
+				t.Errorf("Expected stats:%v;Actual:%v", c.expectedStats, stats)
+			}
+			mockedRepo.Reset()
+		})
+	}
+}
+
+func TestStatsWithLocalRepo_GetDailyProgress(t *testing.T) {
+	// 2020-05-10 in UTC, but already 2020-05-11 in Tokyo.
+	now := time.Date(2020, 5, 10, 20, 0, 0, 0, time.UTC)
+	date := func(day int) time.Time {
+		return time.Date(2020, 5, day, 0, 0, 0, 0, time.UTC)
+	}
+	testCases := []struct {
+		name             string
+		userID           int
+		expectedProgress *domain.DailyProgress
+		expectErr        bool
+	}{
+		{
+			name:             "Positive goal not reached yet",
+			userID:           1,
+			expectedProgress: &domain.DailyProgress{Goal: 10, ReviewsToday: 5, Streak: 2},
+		},
+		{
+			name:             "Positive goal reached in the user's timezone",
+			userID:           2,
+			expectedProgress: &domain.DailyProgress{Goal: 5, ReviewsToday: 5, Streak: 1},
+		},
+		{
+			name:             "Positive no settings",
+			userID:           3,
+			expectedProgress: &domain.DailyProgress{ReviewsToday: 5, Streak: 4},
+		},
+		{
+			name:      "Get settings returns error",
+			userID:    4,
+			expectErr: true,
+		},
+		{
+			name:      "Get daily review counts returns error",
+			userID:    5,
+			expectErr: true,
+		},
+	}
+
+	mockedRepo := &mock.StatsRepo{
+		GetDailyReviewCountsFn: func(userID int, timezone string, since time.Time) ([]*domain.DailyReviews, error) {
+			switch userID {
+			case 2:
+				if timezone != "Asia/Tokyo" {
+					return nil, fmt.Errorf("unexpected timezone: %s", timezone)
+				}
+				return []*domain.DailyReviews{{Date: date(11), Qnt: 5}, {Date: date(10), Qnt: 3}}, nil
+			case 5:
+				return nil, fmt.Errorf("error")
+			default:
+				return []*domain.DailyReviews{
+					{Date: date(10), Qnt: 5},
+					{Date: date(9), Qnt: 10},
+					{Date: date(8), Qnt: 12},
+					{Date: date(7), Qnt: 1},
+				}, nil
+			}
+		},
+	}
+	mockedSettingsRepo := &mock.UserSettingsRepo{
+		GetUserSettingsFn: func(userID int) (*domain.UserSettings, error) {
+			switch userID {
+			case 1:
+				return &domain.UserSettings{UserID: 1, Timezone: "UTC", DailyGoal: 10}, nil
+			case 2:
+				return &domain.UserSettings{UserID: 2, Timezone: "Asia/Tokyo", DailyGoal: 5}, nil
+			case 4:
+				return nil, fmt.Errorf("error")
+			default:
+				return nil, nil
+			}
+		},
+	}
+
+	statsService := NewStatsWithLocalRepo(mock.Logger{}, mockedRepo, mockedSettingsRepo)
+	statsService.now = func() time.Time {
+		return now
+	}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			progress, err := statsService.GetDailyProgress(c.userID)
+			if !c.expectErr && err != nil {
+				t.Errorf("Expected no error, but got %s", err)
+			}
+			if c.expectErr && err == nil {
+				t.Errorf("Expected error, but got nothing")
+			}
+			if !reflect.DeepEqual(progress, c.expectedProgress) {
+				t.Errorf("Expected progress:%v;Actual:%v", c.expectedProgress, progress)
+			}
+			mockedRepo.Reset()
+			mockedSettingsRepo.Reset()
+		})
+	}
+}
+
+func TestStatsWithLocalRepo_GetDailyProgressLongStreak(t *testing.T) {
+	now := time.Date(2020, 5, 10, 20, 0, 0, 0, time.UTC)
+	today := time.Date(2020, 5, 10, 0, 0, 0, 0, time.UTC)
+	var history []*domain.DailyReviews
+	for i := 0; i < 70; i++ {
+		history = append(history, &domain.DailyReviews{Date: today.AddDate(0, 0, -i), Qnt: 1})
+	}
+	var sinceDates []time.Time
+	mockedRepo := &mock.StatsRepo{
+		GetDailyReviewCountsFn: func(userID int, timezone string, since time.Time) ([]*domain.DailyReviews, error) {
+			sinceDates = append(sinceDates, since)
+			var counts []*domain.DailyReviews
+			for _, c := range history {
+				if !c.Date.Before(since) {
+					counts = append(counts, c)
+				}
+			}
+			return counts, nil
+		},
+	}
+	mockedSettingsRepo := &mock.UserSettingsRepo{
+		GetUserSettingsFn: func(userID int) (*domain.UserSettings, error) {
+			return nil, nil
+		},
+	}
+
+	statsService := NewStatsWithLocalRepo(mock.Logger{}, mockedRepo, mockedSettingsRepo)
+	statsService.now = func() time.Time {
+		return now
+	}
+	progress, err := statsService.GetDailyProgress(1)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err)
+	}
+	expectedProgress := &domain.DailyProgress{ReviewsToday: 1, Streak: 70}
+	if !reflect.DeepEqual(progress, expectedProgress) {
+		t.Errorf("Expected progress:%v;Actual:%v", expectedProgress, progress)
+	}
+	expectedSinceDates := []time.Time{today.AddDate(0, 0, -30), today.AddDate(0, 0, -60), today.AddDate(0, 0, -120)}
+	if !reflect.DeepEqual(sinceDates, expectedSinceDates) {
+		t.Errorf("Expected counts since:%v;Actual:%v", expectedSinceDates, sinceDates)
+	}
+}
+
+func TestStatsWithLocalRepo_CheckDailyGoalReached(t *testing.T) {
+	// 2020-05-10 in UTC, but already 2020-05-11 in Tokyo.
+	now := time.Date(2020, 5, 10, 20, 0, 0, 0, time.UTC)
+	date := func(day int) time.Time {
+		return time.Date(2020, 5, day, 0, 0, 0, 0, time.UTC)
+	}
+	testCases := []struct {
+		name              string
+		userID            int
+		expectedProgress  *domain.DailyProgress
+		expectReached     bool
+		expectErr         bool
+		expectMarkInv     bool
+		expectedMarkedDay time.Time
+	}{
+		{
+			name:             "Positive goal not reached yet",
+			userID:           1,
+			expectedProgress: &domain.DailyProgress{Goal: 10, ReviewsToday: 5, Streak: 2},
+		},
+		{
+			name:              "Positive goal reached in the user's timezone",
+			userID:            2,
+			expectedProgress:  &domain.DailyProgress{Goal: 5, ReviewsToday: 5, Streak: 1},
+			expectReached:     true,
+			expectMarkInv:     true,
+			expectedMarkedDay: date(11),
+		},
+		{
+			name:              "Positive goal passed between checks",
+			userID:            3,
+			expectedProgress:  &domain.DailyProgress{Goal: 3, ReviewsToday: 5, Streak: 3},
+			expectReached:     true,
+			expectMarkInv:     true,
+			expectedMarkedDay: date(10),
+		},
+		{
+			name:              "Positive goal already reached today",
+			userID:            4,
+			expectedProgress:  &domain.DailyProgress{Goal: 3, ReviewsToday: 5, Streak: 3},
+			expectMarkInv:     true,
+			expectedMarkedDay: date(10),
+		},
+		{
+			name:             "Positive no goal",
+			userID:           5,
+			expectedProgress: &domain.DailyProgress{ReviewsToday: 5, Streak: 4},
+		},
+		{
+			name:              "Mark goal reached returns error",
+			userID:            6,
+			expectErr:         true,
+			expectMarkInv:     true,
+			expectedMarkedDay: date(10),
+		},
+		{
+			name:      "Get settings returns error",
+			userID:    7,
+			expectErr: true,
+		},
+	}
+
+	mockedRepo := &mock.StatsRepo{
+		GetDailyReviewCountsFn: func(userID int, timezone string, since time.Time) ([]*domain.DailyReviews, error) {
+			if userID == 2 {
+				return []*domain.DailyReviews{{Date: date(11), Qnt: 5}, {Date: date(10), Qnt: 3}}, nil
+			}
+			return []*domain.DailyReviews{
+				{Date: date(10), Qnt: 5},
+				{Date: date(9), Qnt: 10},
+				{Date: date(8), Qnt: 12},
+				{Date: date(7), Qnt: 1},
+			}, nil
+		},
+	}
+	var markedDay time.Time
+	mockedSettingsRepo := &mock.UserSettingsRepo{
+		GetUserSettingsFn: func(userID int) (*domain.UserSettings, error) {
+			switch userID {
+			case 1:
+				return &domain.UserSettings{UserID: 1, Timezone: "UTC", DailyGoal: 10}, nil
+			case 2:
+				return &domain.UserSettings{UserID: 2, Timezone: "Asia/Tokyo", DailyGoal: 5}, nil
+			case 5:
+				return nil, nil
+			case 7:
+				return nil, fmt.Errorf("error")
+			default:
+				return &domain.UserSettings{UserID: userID, Timezone: "UTC", DailyGoal: 3}, nil
+			}
+		},
+		MarkGoalReachedFn: func(userID int, date time.Time) (bool, error) {
+			markedDay = date
+			switch userID {
+			case 4:
+				return false, nil
+			case 6:
+				return false, fmt.Errorf("error")
+			}
+			return true, nil
+		},
+	}
+
+	statsService := NewStatsWithLocalRepo(mock.Logger{}, mockedRepo, mockedSettingsRepo)
+	statsService.now = func() time.Time {
+		return now
+	}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			markedDay = time.Time{}
+			progress, reached, err := statsService.CheckDailyGoalReached(c.userID)
+			if !c.expectErr && err != nil {
+				t.Errorf("Expected no error, but got %s", err)
+			}
+			if c.expectErr && err == nil {
+				t.Errorf("Expected error, but got nothing")
+			}
+			if !reflect.DeepEqual(progress, c.expectedProgress) {
+				t.Errorf("Expected progress:%v;Actual:%v", c.expectedProgress, progress)
+			}
+			if reached != c.expectReached {
+				t.Errorf("Expected reached:%v;Actual:%v", c.expectReached, reached)
+			}
+			if c.expectMarkInv != mockedSettingsRepo.MarkGoalReachedInvoked {
+				t.Errorf("Actual invocation of MarkGoalReached(%v) doesn't match expectations",
+					mockedSettingsRepo.MarkGoalReachedInvoked)
+			}
+			if !markedDay.Equal(c.expectedMarkedDay) {
+				t.Errorf("Expected marked day:%s;Actual:%s", c.expectedMarkedDay, markedDay)
+			}
+			mockedRepo.Reset()
+			mockedSettingsRepo.Reset()
+		})
+	}
+}
+
+func TestCountStreak(t *testing.T) {
+	today := time.Date(2020, 5, 10, 0, 0, 0, 0, time.UTC)
+	daysAgo := func(qnts map[int]int) []*domain.DailyReviews {
+		var counts []*domain.DailyReviews
+		for d := 0; d < 10; d++ {
+			if qnt, ok := qnts[d]; ok {
+				counts = append(counts, &domain.DailyReviews{Date: today.AddDate(0, 0, -d), Qnt: qnt})
+			}
+		}
+		return counts
+	}
+	testCases := []struct {
+		name     string
+		counts   []*domain.DailyReviews
+		goal     int
+		expected int
+	}{
+		{name: "No reviews", expected: 0},
+		{name: "Only today", counts: daysAgo(map[int]int{0: 1}), expected: 1},
+		{name: "Ending today", counts: daysAgo(map[int]int{0: 1, 1: 1, 2: 1, 4: 1}), expected: 3},
+		{name: "Ending yesterday", counts: daysAgo(map[int]int{1: 1, 2: 1, 3: 1}), expected: 3},
+		{name: "Day missed", counts: daysAgo(map[int]int{2: 1, 3: 1}), expected: 0},
+		{name: "Goal reached today", counts: daysAgo(map[int]int{0: 5, 1: 7, 2: 1}), goal: 5, expected: 2},
+		{name: "Goal not reached today yet", counts: daysAgo(map[int]int{0: 2, 1: 7, 2: 5}), goal: 5, expected: 2},
+		{name: "Goal not reached yesterday", counts: daysAgo(map[int]int{0: 5, 1: 4, 2: 5}), goal: 5, expected: 1},
+	}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			res := countStreak(c.counts, today, c.goal)
+			if res != c.expected {
+				t.Errorf("Expected streak:%v;Actual:%v", c.expected, res)
+			}
+		})
+	}
+}
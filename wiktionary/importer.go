@@ -0,0 +1,81 @@
+package wiktionary
+
+import (
+	"fmt"
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"github.com/dmalyar/pimpmyvocab/log"
+	"github.com/dmalyar/pimpmyvocab/repo"
+	"io"
+)
+
+// DefaultBatchSize is the number of entries inserted in one transaction by default.
+const DefaultBatchSize = 500
+
+// Importer loads the entries of the Wiktionary extract into the repo in batches.
+type Importer struct {
+	logger    log.Logger
+	repo      repo.Dictionary
+	batchSize int
+}
+
+// ImportResult is the number of entries read from the extract and inserted into the repo.
+// The entries already existing in the repo are not inserted.
+type ImportResult struct {
+	Parsed   int
+	Inserted int
+}
+
+func NewImporter(logger log.Logger, repo repo.Dictionary, batchSize int) *Importer {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+	return &Importer{
+		logger:    logger,
+		repo:      repo,
+		batchSize: batchSize,
+	}
+}
+
+// Import reads the entries of the language pair from the extract and inserts them into the repo.
+// The batches inserted before the error are kept, so the import can be rerun to continue.
+func (i *Importer) Import(r io.Reader, langPair domain.LangPair) (*ImportResult, error) {
+	logger := i.logger.WithField("langPair", langPair)
+	logger.Info("Importing Wiktionary extract")
+	parser := NewParser(r, langPair)
+	result := new(ImportResult)
+	batch := make([]*domain.VocabEntry, 0, i.batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		inserted, err := i.repo.AddVocabEntries(batch)
+		if err != nil {
+			return fmt.Errorf("adding entries: %s", err)
+		}
+		result.Inserted += inserted
+		batch = batch[:0]
+		logger.Infof("%v entries parsed, %v inserted", result.Parsed, result.Inserted)
+		return nil
+	}
+	for {
+		entry, err := parser.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return result, err
+		}
+		result.Parsed++
+		batch = append(batch, entry)
+		if len(batch) == i.batchSize {
+			if err = flush(); err != nil {
+				return result, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return result, err
+	}
+	logger.Info("Wiktionary extract imported")
+	return result, nil
+}
@@ -0,0 +1,140 @@
+package wiktionary
+
+import (
+	"fmt"
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"github.com/dmalyar/pimpmyvocab/mock"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var langPair = domain.LangPair{Source: "en", Target: "ru"}
+
+var expectedEntries = []*domain.VocabEntry{
+	{
+		Text:            "go",
+		LangPair:        langPair,
+		Source:          Source,
+		Transcription:   "ɡəʊ",
+		MainTranslation: "идти",
+		Translations: []*domain.Translation{
+			{Text: "идти", Class: "verb", Position: 0},
+			{Text: "ехать", Class: "verb", Position: 1},
+			{Text: "попытка", Class: "noun", Position: 2},
+		},
+	},
+	{
+		Text:            "hedgehog",
+		LangPair:        langPair,
+		Source:          Source,
+		Transcription:   "ˈhɛdʒhɒɡ",
+		MainTranslation: "ёж",
+		Translations: []*domain.Translation{
+			{Text: "ёж", Class: "noun", Position: 0},
+			{Text: "противотанковый ёж", Class: "noun", Position: 1},
+		},
+	},
+	{
+		Text:            "quickly",
+		LangPair:        langPair,
+		Source:          Source,
+		Transcription:   "ˈkwɪkli",
+		MainTranslation: "быстро",
+		Translations: []*domain.Translation{
+			{Text: "быстро", Class: "adverb", Position: 0},
+		},
+	},
+}
+
+func TestImporter_Import(t *testing.T) {
+	testCases := []struct {
+		name             string
+		batchSize        int
+		addFn            func(entries []*domain.VocabEntry) (int, error)
+		expectedBatches  []int
+		expectedResult   *ImportResult
+		expectErr        bool
+		expectedImported []*domain.VocabEntry
+	}{
+		{
+			name:      "All entries in one batch",
+			batchSize: 10,
+			addFn: func(entries []*domain.VocabEntry) (int, error) {
+				return len(entries), nil
+			},
+			expectedBatches:  []int{3},
+			expectedResult:   &ImportResult{Parsed: 3, Inserted: 3},
+			expectedImported: expectedEntries,
+		},
+		{
+			name:      "Several batches, existing entries skipped",
+			batchSize: 2,
+			addFn: func(entries []*domain.VocabEntry) (int, error) {
+				return 1, nil
+			},
+			expectedBatches:  []int{2, 1},
+			expectedResult:   &ImportResult{Parsed: 3, Inserted: 2},
+			expectedImported: expectedEntries,
+		},
+		{
+			name:      "Error adding entries",
+			batchSize: 2,
+			addFn: func(entries []*domain.VocabEntry) (int, error) {
+				return 0, fmt.Errorf("error")
+			},
+			expectedBatches: []int{2},
+			expectedResult:  &ImportResult{Parsed: 2},
+			expectErr:       true,
+		},
+	}
+	for _, c := range testCases {
+		t.Run(c.name, func(t *testing.T) {
+			f, err := os.Open("testdata/extract.jsonl")
+			if err != nil {
+				t.Fatalf("Expected no error, but got %s", err)
+			}
+			defer f.Close()
+			var batches []int
+			var imported []*domain.VocabEntry
+			dictRepo := &mock.DictionaryRepo{
+				AddVocabEntriesFn: func(entries []*domain.VocabEntry) (int, error) {
+					batches = append(batches, len(entries))
+					inserted, err := c.addFn(entries)
+					if err == nil {
+						imported = append(imported, entries...)
+					}
+					return inserted, err
+				},
+			}
+			importer := NewImporter(mock.Logger{}, dictRepo, c.batchSize)
+			result, err := importer.Import(f, langPair)
+			if c.expectErr && err == nil {
+				t.Errorf("Expected error, but got nothing")
+			}
+			if !c.expectErr && err != nil {
+				t.Errorf("Expected no error, but got %s", err)
+			}
+			if !reflect.DeepEqual(c.expectedResult, result) {
+				t.Errorf("Expected result:%v;Actual:%v", c.expectedResult, result)
+			}
+			if !reflect.DeepEqual(c.expectedBatches, batches) {
+				t.Errorf("Expected batches:%v;Actual:%v", c.expectedBatches, batches)
+			}
+			if !reflect.DeepEqual(c.expectedImported, imported) {
+				t.Errorf("Expected entries:%v;Actual:%v", c.expectedImported, imported)
+			}
+		})
+	}
+}
+
+func TestParser_Next_Malformed(t *testing.T) {
+	extract := `{"word": "go", "lang_code": "en", "pos": "verb", "translations": [{"code": "ru", "word": "идти"}]}
+{"word": "go", "lang_code": `
+	parser := NewParser(strings.NewReader(extract), langPair)
+	entry, err := parser.Next()
+	if err == nil {
+		t.Errorf("Expected error, but got entry %v", entry)
+	}
+}
@@ -0,0 +1,176 @@
+// Package wiktionary provides the importer of the Wiktionary extracts into the local dictionary.
+// The extracts are JSONL files made by wiktextract (e.g. the ones published on kaikki.org):
+// one JSON object per word and part of speech.
+package wiktionary
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/dmalyar/pimpmyvocab/domain"
+	"io"
+	"strings"
+)
+
+// Source is the source of the entries imported from Wiktionary.
+const Source = "wiktionary"
+
+// classes maps the parts of speech of wiktextract to the classes Yandex.Dictionary uses.
+var classes = map[string]string{
+	"adj":  "adjective",
+	"adv":  "adverb",
+	"pron": "pronoun",
+	"prep": "preposition",
+	"conj": "conjunction",
+	"intj": "interjection",
+	"num":  "numeral",
+}
+
+// namePos is the part of speech of the proper names. They're skipped since their text can't be lowercased.
+const namePos = "name"
+
+type wordJSON struct {
+	Word         string            `json:"word"`
+	LangCode     string            `json:"lang_code"`
+	Pos          string            `json:"pos"`
+	Sounds       []soundJSON       `json:"sounds"`
+	Translations []translationJSON `json:"translations"`
+	Senses       []senseJSON       `json:"senses"`
+}
+
+type soundJSON struct {
+	IPA string `json:"ipa"`
+}
+
+type senseJSON struct {
+	Translations []translationJSON `json:"translations"`
+}
+
+// translationJSON is a translation of the word. Older extracts keep the language in code field
+// and the translations at the word level, newer ones use lang_code field and keep the translations in the senses.
+type translationJSON struct {
+	Code     string `json:"code"`
+	LangCode string `json:"lang_code"`
+	Word     string `json:"word"`
+}
+
+// Parser reads the entries of the language pair from the extract one by one without loading the whole extract.
+// The words in the source language having translations to the target language are taken.
+// The neighbouring objects of the same word (different parts of speech) are merged into one entry.
+type Parser struct {
+	decoder  *json.Decoder
+	langPair domain.LangPair
+	pending  *domain.VocabEntry
+	line     int
+}
+
+func NewParser(r io.Reader, langPair domain.LangPair) *Parser {
+	return &Parser{
+		decoder:  json.NewDecoder(r),
+		langPair: langPair,
+	}
+}
+
+// Next returns the next entry of the extract. Returns io.EOF when there are no more entries.
+func (p *Parser) Next() (*domain.VocabEntry, error) {
+	for {
+		var word wordJSON
+		err := p.decoder.Decode(&word)
+		if err == io.EOF {
+			entry := p.pending
+			p.pending = nil
+			if entry == nil {
+				return nil, io.EOF
+			}
+			return entry, nil
+		}
+		p.line++
+		if err != nil {
+			return nil, fmt.Errorf("parsing object %v of the extract: %s", p.line, err)
+		}
+		entry := p.toVocabEntry(&word)
+		if entry == nil {
+			continue
+		}
+		if p.pending != nil && p.pending.Text == entry.Text {
+			mergeEntry(p.pending, entry)
+			continue
+		}
+		prev := p.pending
+		p.pending = entry
+		if prev != nil {
+			return prev, nil
+		}
+	}
+}
+
+// toVocabEntry returns the entry of the word or nil if the word is not in the source language, is a proper name
+// or has no translations to the target language.
+// The text is lowercased the same way the bot lowercases the looked up words.
+func (p *Parser) toVocabEntry(word *wordJSON) *domain.VocabEntry {
+	text := strings.ToLower(strings.TrimSpace(word.Word))
+	if word.LangCode != p.langPair.Source || word.Pos == namePos || text == "" {
+		return nil
+	}
+	entry := &domain.VocabEntry{
+		Text:     text,
+		LangPair: p.langPair,
+		Source:   Source,
+	}
+	for _, s := range word.Sounds {
+		if s.IPA != "" {
+			entry.Transcription = strings.Trim(s.IPA, "/[] ")
+			break
+		}
+	}
+	translations := word.Translations
+	for _, s := range word.Senses {
+		translations = append(translations, s.Translations...)
+	}
+	class := wordClass(word.Pos)
+	for _, t := range translations {
+		if t.Code != p.langPair.Target && t.LangCode != p.langPair.Target {
+			continue
+		}
+		addTranslation(entry, &domain.Translation{Text: strings.TrimSpace(t.Word), Class: class})
+	}
+	if len(entry.Translations) == 0 {
+		return nil
+	}
+	entry.MainTranslation = entry.Translations[0].Text
+	return entry
+}
+
+// mergeEntry adds the translations of the other entry to the end of the entry.
+func mergeEntry(entry, other *domain.VocabEntry) {
+	if entry.Transcription == "" {
+		entry.Transcription = other.Transcription
+	}
+	for _, t := range other.Translations {
+		addTranslation(entry, t)
+	}
+}
+
+// addTranslation adds the translation to the end of the entry unless the entry already has it.
+func addTranslation(entry *domain.VocabEntry, translation *domain.Translation) {
+	if translation.Text == "" {
+		return
+	}
+	for _, t := range entry.Translations {
+		if t.Class == translation.Class && strings.EqualFold(t.Text, translation.Text) {
+			return
+		}
+	}
+	entry.Translations = append(entry.Translations, &domain.Translation{
+		Text:     translation.Text,
+		Class:    translation.Class,
+		Position: len(entry.Translations),
+	})
+}
+
+// wordClass returns the class of the wiktextract part of speech.
+func wordClass(pos string) string {
+	if class, ok := classes[pos]; ok {
+		return class
+	}
+	return pos
+}